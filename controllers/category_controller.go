@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/maxime-louis14/api-golang/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// categoryScrapeTimeout borne le parcours d'une catégorie par POST /scraper/category
+const categoryScrapeTimeout = 10 * time.Minute
+
+// categoryScraper parcourt une seule catégorie (remplaçable dans les tests)
+var categoryScraper = ScrapeCategory
+
+// ScrapeCategory lance le scraper en mode -category et décode les recettes écrites en NDJSON sur sa sortie standard
+// Les recettes invalides (ValidateRecette) sont ignorées ; leur nombre est retourné avec les recettes
+func ScrapeCategory(ctx context.Context, categoryURL string, maxPages int, requestID string) (recettes []models.Recette, invalid int, err error) {
+	scraperPath := GetScraperPath()
+	ctx, span := startScraperSpan(ctx, "scraper.category", scraperPath, requestID, attribute.String("scraper.category_url", categoryURL))
+	defer func() { tracing.End(span, err) }()
+	if err := CheckScraperBinary(scraperPath); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrScraperNotStarted, err)
+	}
+
+	// Même environnement que scraperCommand (request ID et trace), avec -category et une annulation par ctx
+	cmd := exec.CommandContext(ctx, scraperPath, "-category", categoryURL, "-category-pages", strconv.Itoa(maxPages))
+	cmd.Dir = scraperDataDir
+	cmd.Env = scraperEnv(ctx, requestID)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", err, strings.TrimSpace(lastLine(stderr.String())))
+	}
+
+	_, err = models.ReadRecettes(&stdout, func(recette models.Recette) error {
+		recettes = append(recettes, recette)
+		return nil
+	}, func(int, error) {
+		invalid++
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("sortie du scraper illisible: %w", err)
+	}
+	return recettes, invalid, nil
+}
+
+// categoryResponse est le résultat de POST /scraper/category
+type categoryResponse struct {
+	URL      string           `json:"url"`
+	MaxPages int              `json:"max_pages"`
+	Count    int              `json:"count"`
+	Invalid  int              `json:"invalid"`         // Recettes extraites mais invalides, ignorées
+	Saved    *importCounts    `json:"saved,omitempty"` // Résultat de l'enregistrement avec "upsert": true
+	Recettes []models.Recette `json:"recettes"`
+}
+
+// ScrapeScraperCategory parcourt une seule catégorie à la demande, sans toucher à la liste configurée,
+// et renvoie ses recettes ; avec "upsert": true, elles sont aussi enregistrées (upsert par page)
+func ScrapeScraperCategory(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID, _ := c.Locals("requestID").(string)
+
+	var body models.CategoryRequest
+	if err := c.BodyParser(&body); err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Corps JSON invalide: {\"url\": \"https://www.allrecipes.com/recipes/...\", \"max_pages\": 3} attendu")
+	}
+	if err := body.Validate(); err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	logger.LogInfo("Parcours d'une catégorie à la demande", map[string]interface{}{
+		"request_id": requestID,
+		"category":   body.URL,
+		"max_pages":  body.MaxPages,
+		"upsert":     body.Upsert,
+	})
+
+	// Même garde que /scraper/run : une exécution à la fois, SCRAPER_MIN_INTERVAL entre deux lancements
+	reservedAt, err := beginScraperRun(true)
+	if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+		return respErr
+	}
+	scrapeCtx, cancelScrape := context.WithTimeout(c.UserContext(), categoryScrapeTimeout)
+	defer cancelScrape()
+	recettes, invalid, err := categoryScraper(scrapeCtx, body.URL, body.MaxPages, requestID)
+	endScraperRun(reservedAt, !errors.Is(err, ErrScraperNotStarted))
+	if err != nil {
+		logger.LogError("Échec du parcours de la catégorie", err, map[string]interface{}{
+			"request_id": requestID,
+			"category":   body.URL,
+		})
+		return responses.SendError(c, 502, responses.CodeScraperFailed, fmt.Sprintf("Parcours de la catégorie impossible: %v", err))
+	}
+
+	response := categoryResponse{
+		URL:      body.URL,
+		MaxPages: body.MaxPages,
+		Count:    len(recettes),
+		Invalid:  invalid,
+		Recettes: recettes,
+	}
+
+	if body.Upsert {
+		ctx, cancel := context.WithTimeout(c.UserContext(), time.Minute)
+		defer cancel()
+		counts := importCounts{Format: "ndjson"}
+		if err := upsertBatch(ctx, recettes, time.Now().UTC(), &counts, true); err != nil {
+			logger.LogError("Échec de l'enregistrement des recettes de la catégorie", err, map[string]interface{}{
+				"request_id": requestID,
+				"category":   body.URL,
+			})
+			return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de l'enregistrement des recettes")
+		}
+		response.Saved = &counts
+	}
+
+	logger.LogInfo("Catégorie parcourue", map[string]interface{}{
+		"request_id": requestID,
+		"category":   body.URL,
+		"count":      len(recettes),
+		"invalid":    invalid,
+		"duration":   time.Since(start).String(),
+	})
+	return responses.SendJSON(c, 200, response)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+)
+
+// GetScraperConfigPath retourne le chemin de scraper_config.json (configurable via SCRAPER_CONFIG_PATH)
+// Par défaut, le fichier est dans le répertoire de travail du scraper, où il le lit au démarrage
+func GetScraperConfigPath() string {
+	if path := os.Getenv("SCRAPER_CONFIG_PATH"); path != "" {
+		return path
+	}
+	return filepath.Join(scraperDataDir, "scraper_config.json")
+}
+
+// categoriesRequest est le corps attendu par POST /scraper/categories
+type categoriesRequest struct {
+	Categories []string `json:"categories"`
+}
+
+// GetScraperCategories liste les catégories que le scraper parcourra à sa prochaine exécution
+func GetScraperCategories(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+
+	configPath := GetScraperConfigPath()
+	config, err := models.LoadScraperConfig(configPath)
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture de la configuration du scraper", err, map[string]interface{}{
+			"request_id":  requestID,
+			"config_path": configPath,
+		})
+		return responses.SendError(c, 500, responses.CodeConfigError, "Erreur lors de la lecture de la configuration du scraper")
+	}
+
+	return responses.SendJSON(c, 200, fiber.Map{
+		"path":       configPath,
+		"count":      len(config.Categories),
+		"categories": config.Categories,
+	})
+}
+
+// UpdateScraperCategories remplace les catégories du scraper (protégée par clé API)
+// La nouvelle liste est persistée dans scraper_config.json et prise en compte à la prochaine exécution
+func UpdateScraperCategories(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+
+	var body categoriesRequest
+	if err := c.BodyParser(&body); err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Corps JSON invalide: {\"categories\": [\"https://...\"]} attendu")
+	}
+	categories, err := models.ValidateCategoryURLs(body.Categories)
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	configPath := GetScraperConfigPath()
+	config, err := models.LoadScraperConfig(configPath)
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture de la configuration du scraper", err, map[string]interface{}{
+			"request_id":  requestID,
+			"config_path": configPath,
+		})
+		return responses.SendError(c, 500, responses.CodeConfigError, "Erreur lors de la lecture de la configuration du scraper")
+	}
+	config.Categories = categories
+	if err := models.SaveScraperConfig(configPath, config); err != nil {
+		logger.LogError("Erreur lors de l'écriture de la configuration du scraper", err, map[string]interface{}{
+			"request_id":  requestID,
+			"config_path": configPath,
+		})
+		return responses.SendError(c, 500, responses.CodeConfigError, "Erreur lors de l'écriture de la configuration du scraper")
+	}
+
+	logger.LogInfo("Catégories du scraper mises à jour", map[string]interface{}{
+		"request_id":  requestID,
+		"config_path": configPath,
+		"count":       len(categories),
+	})
+	return responses.SendJSON(c, 200, fiber.Map{
+		"path":       configPath,
+		"count":      len(categories),
+		"categories": categories,
+	})
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/maxime-louis14/api-golang/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// singleRecipeTimeout borne le scrape d'une recette par POST /scraper/diff
+const singleRecipeTimeout = 2 * time.Minute
+
+// singleRecipeScraper scrape une seule recette (remplaçable dans les tests)
+var singleRecipeScraper = ScrapeSingleRecipe
+
+// ScrapeSingleRecipe lance le scraper en mode -recipe et décode la recette écrite sur sa sortie standard
+func ScrapeSingleRecipe(ctx context.Context, recipeURL, requestID string) (recette models.Recette, err error) {
+	scraperPath := GetScraperPath()
+	ctx, span := startScraperSpan(ctx, "scraper.recipe", scraperPath, requestID, attribute.String("scraper.recipe_url", recipeURL))
+	defer func() { tracing.End(span, err) }()
+	if err := CheckScraperBinary(scraperPath); err != nil {
+		return models.Recette{}, fmt.Errorf("%w: %v", ErrScraperNotStarted, err)
+	}
+
+	// Même environnement que scraperCommand (request ID et trace), avec -recipe et une annulation par ctx
+	cmd := exec.CommandContext(ctx, scraperPath, "-recipe", recipeURL)
+	cmd.Dir = scraperDataDir
+	cmd.Env = scraperEnv(ctx, requestID)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return models.Recette{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(lastLine(stderr.String())))
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &recette); err != nil {
+		return models.Recette{}, fmt.Errorf("sortie du scraper illisible: %w", err)
+	}
+	return recette, nil
+}
+
+// lastLine retourne la dernière ligne non vide d'une sortie
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
+
+// diffRequest est le corps attendu par POST /scraper/diff
+type diffRequest struct {
+	URL string `json:"url"`
+}
+
+// DiffScraperRecipe scrape une recette et la compare à la version enregistrée pour la même page
+func DiffScraperRecipe(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID, _ := c.Locals("requestID").(string)
+
+	var body diffRequest
+	if err := c.BodyParser(&body); err != nil || body.URL == "" {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Corps JSON invalide: {\"url\": \"https://www.allrecipes.com/recipe/...\"} attendu")
+	}
+	if parsed, err := url.Parse(body.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, fmt.Sprintf("URL invalide %q: URL http(s) absolue attendue", body.URL))
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var stored models.Recette
+	if err := recetteCollection.FindOne(ctx, bson.M{"page": body.URL}).Decode(&stored); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return responses.SendError(c, 404, responses.CodeRecipeNotFound, "Aucune version enregistrée pour cette page")
+		}
+		logger.LogError("Échec de la recherche de recette par page", err, map[string]interface{}{
+			"request_id": requestID,
+			"page":       body.URL,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération de la recette")
+	}
+
+	// Même garde que /scraper/run : une exécution à la fois, SCRAPER_MIN_INTERVAL entre deux lancements
+	reservedAt, err := beginScraperRun(true)
+	if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+		return respErr
+	}
+	scrapeCtx, cancelScrape := context.WithTimeout(c.UserContext(), singleRecipeTimeout)
+	defer cancelScrape()
+	fresh, err := singleRecipeScraper(scrapeCtx, body.URL, requestID)
+	endScraperRun(reservedAt, !errors.Is(err, ErrScraperNotStarted))
+	if err != nil {
+		logger.LogError("Échec du scrape de la recette à comparer", err, map[string]interface{}{
+			"request_id": requestID,
+			"page":       body.URL,
+		})
+		return responses.SendError(c, 502, responses.CodeScraperFailed, fmt.Sprintf("Scrape de la recette impossible: %v", err))
+	}
+	if fresh.Page == "" {
+		fresh.Page = body.URL
+	}
+
+	diff := models.DiffRecettes(stored, fresh)
+	logger.LogInfo("Recette comparée à la version enregistrée", map[string]interface{}{
+		"request_id": requestID,
+		"page":       body.URL,
+		"changed":    diff.Changed,
+		"duration":   time.Since(start).String(),
+	})
+	return responses.SendJSON(c, 200, diff)
+}
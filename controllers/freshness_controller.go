@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+)
+
+// refreshResponse est la décision de POST /scraper/refresh-if-stale
+type refreshResponse struct {
+	Launched  bool                 `json:"launched"`
+	JobID     string               `json:"job_id,omitempty"` // Job consultable via GET /scraper/jobs/:id
+	Freshness models.DataFreshness `json:"freshness"`
+}
+
+// dataFreshness compare l'âge de data.json à maxAge (emplacement du volume partagé si le fichier est absent)
+func dataFreshness(maxAge time.Duration) (models.DataFreshness, error) {
+	path, found := findScraperData()
+	if !found {
+		path = filepath.Join(scraperDataDir, "data.json")
+	}
+	return models.CheckDataFreshness(path, maxAge, time.Now())
+}
+
+// GetScraperDataFreshness indique si data.json est plus vieux que ?max_age= (24h par défaut)
+func GetScraperDataFreshness(c *fiber.Ctx) error {
+	requestID := c.Locals("requestID").(string)
+
+	maxAge, err := models.ParseMaxAge(c.Query("max_age"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	freshness, err := dataFreshness(maxAge)
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture des informations de data.json", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de la lecture des informations du fichier")
+	}
+	return responses.SendJSON(c, 200, freshness)
+}
+
+// RefreshScraperDataIfStale lance le scraper en arrière-plan si data.json est absent ou plus vieux que ?max_age=
+// Répond 200 sans rien lancer si les données sont fraîches, 202 avec le job_id sinon,
+// 409 si une exécution est déjà en cours, 429 si la précédente date de moins de SCRAPER_MIN_INTERVAL
+func RefreshScraperDataIfStale(c *fiber.Ctx) error {
+	requestID := c.Locals("requestID").(string)
+
+	maxAge, err := models.ParseMaxAge(c.Query("max_age"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	freshness, err := dataFreshness(maxAge)
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture des informations de data.json", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de la lecture des informations du fichier")
+	}
+	if !freshness.Stale {
+		return responses.SendJSON(c, 200, refreshResponse{Freshness: freshness})
+	}
+
+	// Prendre le verrou avant de répondre : la décision renvoyée est celle appliquée
+	reservedAt, err := beginScraperRun(true)
+	if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+		return respErr
+	}
+	logger.LogInfo("Données périmées: lancement du scraper", map[string]interface{}{
+		"request_id":  requestID,
+		"age_seconds": freshness.AgeSeconds,
+		"max_age":     freshness.MaxAge,
+	})
+	job := startScraperJob(c, requestID, reservedAt)
+	return responses.SendJSON(c, 202, refreshResponse{Launched: true, JobID: job.ID, Freshness: freshness})
+}
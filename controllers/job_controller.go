@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+)
+
+// scraperJobs conserve les exécutions lancées en arrière-plan (POST /scraper/refresh-if-stale)
+var scraperJobs = models.NewJobRegistry(models.MaxScraperJobs)
+
+// startScraperJob lance en arrière-plan une exécution réservée par beginScraperRun et l'enregistre dans scraperJobs
+// La fin de l'exécution libère le verrou et met à jour le job
+func startScraperJob(c *fiber.Ctx, requestID string, reservedAt time.Time) models.ScraperJob {
+	job := scraperJobs.Start(requestID, time.Now())
+	// La réponse part avant la fin de l'exécution : garder la trace de la requête sans son annulation
+	runCtx := context.WithoutCancel(c.UserContext())
+	go func() {
+		runStart := time.Now()
+		err := scraperRunner(runCtx, requestID)
+		endScraperRun(reservedAt, !errors.Is(err, ErrScraperNotStarted))
+		scraperJobs.Finish(job.ID, err, time.Now())
+		logger.RecordScraperRun(err == nil, time.Since(runStart))
+		if err != nil {
+			logger.LogError("Échec du job scraper", err, map[string]interface{}{
+				"request_id": requestID,
+				"job_id":     job.ID,
+			})
+		}
+	}()
+	return job
+}
+
+// GetScraperJob retourne l'état d'un job du scraper, avec le request ID de la requête qui l'a lancé
+func GetScraperJob(c *fiber.Ctx) error {
+	job, ok := scraperJobs.Get(c.Params("id"))
+	if !ok {
+		return responses.SendError(c, 404, responses.CodeJobNotFound, "Job scraper introuvable")
+	}
+	return responses.SendJSON(c, 200, job)
+}
+
+// FindScraperJob retrouve le dernier job lancé par la requête ?request_id= (en-tête X-Request-ID de sa réponse)
+func FindScraperJob(c *fiber.Ctx) error {
+	requestID := c.Query("request_id")
+	if requestID == "" {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Paramètre request_id requis")
+	}
+	job, ok := scraperJobs.FindByRequestID(requestID)
+	if !ok {
+		return responses.SendError(c, 404, responses.CodeJobNotFound, "Aucun job scraper pour ce request ID")
+	}
+	return responses.SendJSON(c, 200, job)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+)
+
+// Le request ID de la requête qui lance le job est transmis au scraper et relu via GET /scraper/jobs/:id
+func TestScraperJobRequestID(t *testing.T) {
+	savedPaths := scraperDataPaths
+	scraperDataPaths = []string{filepath.Join(t.TempDir(), "data.json")} // Absent : données périmées
+	t.Cleanup(func() { scraperDataPaths = savedPaths })
+
+	release := make(chan struct{})
+	runnerRequestID := make(chan string, 1)
+	useScraperRunner(t, func(ctx context.Context, id string) error {
+		runnerRequestID <- id
+		<-release
+		return nil
+	})
+	app := newTestApp()
+	app.Post("/scraper/refresh-if-stale", RefreshScraperDataIfStale)
+	app.Get("/scraper/jobs/:id", GetScraperJob)
+	getJob := func(id string) models.ScraperJob {
+		resp, err := app.Test(httptest.NewRequest("GET", "/scraper/jobs/"+id, nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+		var job models.ScraperJob
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+		return job
+	}
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/scraper/refresh-if-stale", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, 202, resp.StatusCode)
+	var launched refreshResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&launched))
+	require.NotEmpty(t, launched.JobID)
+	assert.Equal(t, "test-request", <-runnerRequestID)
+
+	running := getJob(launched.JobID)
+	assert.Equal(t, "test-request", running.RequestID)
+	assert.Equal(t, models.JobRunning, running.Status)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return getJob(launched.JobID).Status == models.JobSucceeded
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "test-request", getJob(launched.JobID).RequestID)
+
+	status, body := getError(t, app, "/scraper/jobs/inconnu")
+	assert.Equal(t, 404, status)
+	assert.Equal(t, responses.CodeJobNotFound, body.Code)
+}
+
+// GET /scraper/jobs?request_id= retrouve le job lancé par une requête
+func TestFindScraperJob(t *testing.T) {
+	saved := scraperJobs
+	scraperJobs = models.NewJobRegistry(models.MaxScraperJobs)
+	t.Cleanup(func() { scraperJobs = saved })
+	job := scraperJobs.Start("68f381f9ce484519", time.Now())
+
+	app := newTestApp()
+	app.Get("/scraper/jobs", FindScraperJob)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/scraper/jobs?request_id=68f381f9ce484519", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	var found models.ScraperJob
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&found))
+	assert.Equal(t, job.ID, found.ID)
+	assert.Equal(t, "68f381f9ce484519", found.RequestID)
+
+	status, body := getError(t, app, "/scraper/jobs?request_id=inconnu")
+	assert.Equal(t, 404, status)
+	assert.Equal(t, responses.CodeJobNotFound, body.Code)
+
+	status, body = getError(t, app, "/scraper/jobs")
+	assert.Equal(t, 400, status)
+	assert.Equal(t, responses.CodeInvalidParameter, body.Code)
+}
@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,12 +16,21 @@ import (
 	"github.com/maxime-louis14/api-golang/database"
 	"github.com/maxime-louis14/api-golang/logger"
 	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/maxime-louis14/api-golang/responses"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var recetteCollection *mongo.Collection = database.OpenCollection(database.Client, "recettes")
+// recetteCollection est la collection des recettes, ouverte au démarrage de l'API par SetupCollections
+var recetteCollection *mongo.Collection
+
+// SetupCollections ouvre les collections des contrôleurs sur le client MongoDB de l'API
+func SetupCollections(client *mongo.Client) {
+	recetteCollection = database.OpenCollection(client, "recettes")
+}
 
 // getScraperDataPath retourne un chemin absolu vers data.json
 func getScraperDataPath() (string, error) {
@@ -58,7 +70,7 @@ func PostRecette(c *fiber.Ctx) error {
 		logger.LogError("Échec de localisation du fichier data.json", err, map[string]interface{}{
 			"request_id": requestID,
 		})
-		return c.Status(500).SendString("Erreur lors de la localisation du fichier data.json")
+		return responses.SendError(c, 500, responses.CodeDataFileNotFound, "Erreur lors de la localisation du fichier data.json")
 	}
 
 	// Debug: afficher le chemin trouvé
@@ -74,7 +86,7 @@ func PostRecette(c *fiber.Ctx) error {
 			"request_id": requestID,
 			"file_path":  dataPath,
 		})
-		return c.Status(500).SendString("Erreur lors de l'ouverture du fichier data.json")
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de l'ouverture du fichier data.json")
 	}
 	defer file.Close()
 
@@ -85,7 +97,7 @@ func PostRecette(c *fiber.Ctx) error {
 			"request_id": requestID,
 			"file_path":  dataPath,
 		})
-		return c.Status(500).SendString("Erreur lors de la lecture du fichier data.json")
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de la lecture du fichier data.json")
 	}
 
 	// Décoder les données JSON
@@ -94,19 +106,37 @@ func PostRecette(c *fiber.Ctx) error {
 		logger.LogError("Échec du décodage JSON", err, map[string]interface{}{
 			"request_id": requestID,
 		})
-		return c.Status(500).SendString("Erreur lors du décodage des données JSON")
+		return responses.SendError(c, 500, responses.CodeInvalidData, "Erreur lors du décodage des données JSON")
+	}
+
+	// Valider toutes les recettes avant d'en insérer une seule
+	if rejections := validateRecettes(recettes); len(rejections) > 0 {
+		logger.LogInfo("Recettes invalides dans data.json", map[string]interface{}{
+			"request_id": requestID,
+			"invalid":    len(rejections),
+		})
+		details := fiber.Map{"invalid": len(rejections), "recettes": rejections}
+		if len(rejections) > maxRejections {
+			details["recettes"] = rejections[:maxRejections]
+		}
+		return responses.SendErrorDetails(c, 400, responses.CodeValidationFailed,
+			fmt.Sprintf("%d recette(s) invalide(s) sur %d, aucune insérée", len(rejections), len(recettes)), details)
 	}
 
 	// Insérer les recettes dans MongoDB
 	insertedCount := 0
+	now := time.Now().UTC()
 	for _, recette := range recettes {
-		_, err := recetteCollection.InsertOne(context.Background(), recette)
+		recette.CreatedAt = &now
+		recette.RecipeID = recipe.ID(recette.Page)
+		models.NormalizeIngredients(&recette)
+		_, err := recetteCollection.InsertOne(c.UserContext(), recette)
 		if err != nil {
 			logger.LogError("Échec d'insertion d'une recette", err, map[string]interface{}{
 				"request_id": requestID,
 				"recette":    recette.Name,
 			})
-			return c.Status(500).SendString("Erreur lors de l'insertion des recettes")
+			return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de l'insertion des recettes")
 		}
 		insertedCount++
 	}
@@ -120,43 +150,244 @@ func PostRecette(c *fiber.Ctx) error {
 	return c.Status(201).SendString("Recettes ajoutées avec succès")
 }
 
+// maxRejections borne le détail des recettes refusées renvoyé par POST /recettes et l'import
+const maxRejections = 100
+
+// recetteRejection détaille une recette de data.json refusée par la validation (Index à partir de 0)
+type recetteRejection struct {
+	Index  int                 `json:"index"`
+	Name   string              `json:"name,omitempty"`
+	Fields []models.FieldError `json:"fields"`
+}
+
+// importRejection détaille un enregistrement refusé à l'import (Record à partir de 1)
+// Error remplace Fields pour un enregistrement illisible
+type importRejection struct {
+	Record int                 `json:"record"`
+	Error  string              `json:"error,omitempty"`
+	Fields []models.FieldError `json:"fields,omitempty"`
+}
+
+// validateRecettes retourne le détail des recettes ne respectant pas les tags validate du modèle
+func validateRecettes(recettes []models.Recette) []recetteRejection {
+	var rejections []recetteRejection
+	for i, recette := range recettes {
+		var validationErr *models.ValidationError
+		if errors.As(models.ValidateRecette(recette), &validationErr) {
+			rejections = append(rejections, recetteRejection{Index: i, Name: recette.Name, Fields: validationErr.Fields})
+		}
+	}
+	return rejections
+}
+
+// findRecettes exécute la recherche avec le tri, la pagination et le niveau de détail demandés
+// En summary, la projection évite de transférer les instructions depuis MongoDB
+func findRecettes(ctx context.Context, filter bson.M, query models.ListQuery) (interface{}, int, error) {
+	aggregateOptions := options.Aggregate()
+	if collation := query.Collation(); collation != nil {
+		aggregateOptions.SetCollation(collation)
+	}
+
+	cursor, err := recetteCollection.Aggregate(ctx, query.Pipeline(filter), aggregateOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	if query.Fields == models.FieldsSummary {
+		recettes := []models.RecetteSummary{}
+		if err := cursor.All(ctx, &recettes); err != nil {
+			return nil, 0, err
+		}
+		return recettes, len(recettes), nil
+	}
+
+	recettes := []models.Recette{}
+	if err := cursor.All(ctx, &recettes); err != nil {
+		return nil, 0, err
+	}
+	return recettes, len(recettes), nil
+}
+
+// listParams extrait les paramètres de liste (fields, sort, order, page, limit, max_total_minutes) de la requête
+func listParams(c *fiber.Ctx) models.ListParams {
+	return models.ListParams{
+		Fields: c.Query("fields"),
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+		Page:   c.Query("page"),
+		Limit:  c.Query("limit"),
+
+		MaxTotalMinutes: c.Query("max_total_minutes"),
+	}
+}
+
 // GetAllRecettes retourne toutes les recettes
+// Paramètres : ?fields=summary|full, ?sort=name|recent|ingredients, ?order=asc|desc, ?page=, ?limit=, ?max_total_minutes=
 func GetAllRecettes(c *fiber.Ctx) error {
 	start := time.Now()
 	requestID := c.Locals("requestID").(string)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+	query, err := listParams(c).Parse()
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	logger.LogDatabase(logger.INFO, "Début de récupération de toutes les recettes", "find_all", "mongodb", time.Since(start), map[string]interface{}{
 		"request_id": requestID,
+		"fields":     query.Fields,
+		"sort":       query.Sort,
 	})
 
 	// Récupérer toutes les recettes
-	cursor, err := recetteCollection.Find(ctx, bson.M{})
+	recettes, count, err := findRecettes(ctx, bson.M{}, query)
 	if err != nil {
 		logger.LogError("Échec de récupération des recettes", err, map[string]interface{}{
 			"request_id": requestID,
 		})
-		return c.Status(500).SendString("Erreur lors de la récupération des recettes")
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération des recettes")
+	}
+
+	duration := time.Since(start)
+	logger.LogDatabase(logger.INFO, "Récupération de toutes les recettes terminée", "find_all", "mongodb", duration, map[string]interface{}{
+		"request_id":     requestID,
+		"fields":         query.Fields,
+		"recettes_count": count,
+	})
+
+	return responses.SendJSON(c, 200, recettes)
+}
+
+// GetRandomRecette retourne une recette tirée au hasard (?count=n pour en obtenir plusieurs)
+func GetRandomRecette(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+
+	count, err := models.ParseRandomCount(c.Query("count"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	// $sample laisse MongoDB choisir les documents sans tout charger
+	cursor, err := recetteCollection.Aggregate(ctx, models.RandomPipeline(count))
+	if err != nil {
+		logger.LogError("Échec du tirage aléatoire de recettes", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération des recettes")
 	}
 	defer cursor.Close(ctx)
 
-	// Décoder les recettes
-	var recettes []models.Recette
+	recettes := []models.Recette{}
 	if err := cursor.All(ctx, &recettes); err != nil {
-		logger.LogError("Échec du décodage des recettes", err, map[string]interface{}{
+		logger.LogError("Échec du décodage des recettes aléatoires", err, map[string]interface{}{
 			"request_id": requestID,
 		})
-		return c.Status(500).SendString("Erreur lors du décodage des recettes")
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors du décodage des recettes")
+	}
+
+	if len(recettes) == 0 {
+		return responses.SendError(c, 404, responses.CodeNoRecipes, "Aucune recette disponible")
 	}
 
 	duration := time.Since(start)
-	logger.LogDatabase(logger.INFO, "Récupération de toutes les recettes terminée", "find_all", "mongodb", duration, map[string]interface{}{
+	logger.LogDatabase(logger.INFO, "Recettes aléatoires récupérées", "sample", "mongodb", duration, map[string]interface{}{
 		"request_id":     requestID,
 		"recettes_count": len(recettes),
 	})
 
-	return c.Status(200).JSON(recettes)
+	// Sans ?count, une seule recette est renvoyée (objet et non tableau)
+	if c.Query("count") == "" {
+		return responses.SendJSON(c, 200, recettes[0])
+	}
+	return responses.SendJSON(c, 200, recettes)
+}
+
+// GetTopIngredients retourne les ingrédients les plus fréquents des recettes (?limit=20), avec leur nombre de recettes
+// Le comptage est fait par MongoDB ($unwind, $group, $sort, $limit) ; collection vide : liste vide
+func GetTopIngredients(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+
+	limit, err := models.ParseTopIngredientsLimit(c.Query("limit"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := recetteCollection.Aggregate(ctx, models.TopIngredientsPipeline(limit))
+	if err != nil {
+		logger.LogError("Échec du comptage des ingrédients", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors du comptage des ingrédients")
+	}
+	defer cursor.Close(ctx)
+
+	ingredients := []models.IngredientCount{}
+	if err := cursor.All(ctx, &ingredients); err != nil {
+		logger.LogError("Échec du décodage des ingrédients les plus fréquents", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors du décodage des ingrédients")
+	}
+
+	logger.LogDatabase(logger.INFO, "Ingrédients les plus fréquents récupérés", "top_ingredients", "mongodb", time.Since(start), map[string]interface{}{
+		"request_id":        requestID,
+		"ingredients_count": len(ingredients),
+		"limit":             limit,
+	})
+	return responses.SendJSON(c, 200, ingredients)
+}
+
+// GetIncompleteRecettes retourne les recettes auxquelles manque une donnée (?missing=), pour cibler un nouveau scraping
+// Accepte les paramètres de liste de GetAllRecettes ; la liste est paginée (?limit=50 par défaut)
+func GetIncompleteRecettes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+	missing := c.Query("missing")
+
+	filter, err := models.MissingFilter(missing)
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	params := listParams(c)
+	if params.Limit == "" {
+		params.Limit = strconv.Itoa(models.DefaultIncompleteLimit)
+	}
+	query, err := params.Parse()
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	recettes, count, err := findRecettes(ctx, filter, query)
+	if err != nil {
+		logger.LogError("Échec de récupération des recettes incomplètes", err, map[string]interface{}{
+			"request_id": requestID,
+			"missing":    missing,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération des recettes")
+	}
+
+	logger.LogDatabase(logger.INFO, "Recettes incomplètes récupérées", "find_incomplete", "mongodb", time.Since(start), map[string]interface{}{
+		"request_id":     requestID,
+		"missing":        missing,
+		"page":           query.Page,
+		"recettes_count": count,
+	})
+	return responses.SendJSON(c, 200, recettes)
 }
 
 // GetRecetteByID retourne une recette spécifique en fonction de son ID
@@ -170,25 +401,36 @@ func GetRecetteByID(c *fiber.Ctx) error {
 		"recipe_id":  id,
 	})
 
-	// Convertir l'ID en ObjectID
+	// Convertir l'ID en ObjectID (24 caractères hexadécimaux)
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		logger.LogError("ID de recette invalide", err, map[string]interface{}{
 			"request_id": requestID,
 			"recipe_id":  id,
 		})
-		return c.Status(400).SendString("ID de recette invalide")
+		return responses.SendError(c, 400, responses.CodeInvalidRecipeID, "ID de recette invalide: un ObjectID de 24 caractères hexadécimaux est attendu")
 	}
 
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
 	// Rechercher la recette
 	filter := bson.M{"_id": objID}
 	var recette models.Recette
-	if err := recetteCollection.FindOne(context.Background(), filter).Decode(&recette); err != nil {
-		logger.LogError("Recette introuvable", err, map[string]interface{}{
+	if err := recetteCollection.FindOne(ctx, filter).Decode(&recette); err != nil {
+		// Distinguer l'absence de document d'une erreur de la base
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.LogInfo("Recette introuvable", map[string]interface{}{
+				"request_id": requestID,
+				"recipe_id":  id,
+			})
+			return responses.SendError(c, 404, responses.CodeRecipeNotFound, "Recette introuvable")
+		}
+		logger.LogError("Échec de la recherche de recette par ID", err, map[string]interface{}{
 			"request_id": requestID,
 			"recipe_id":  id,
 		})
-		return c.Status(404).SendString("Recette introuvable")
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération de la recette")
 	}
 
 	duration := time.Since(start)
@@ -198,29 +440,107 @@ func GetRecetteByID(c *fiber.Ctx) error {
 		"recipe_name": recette.Name,
 	})
 
-	return c.Status(200).JSON(recette)
+	return responses.SendJSON(c, 200, recette)
+}
+
+// GetSimilarRecettes retourne les recettes partageant le plus d'ingrédients avec la recette :id (?limit=5)
+// Chaque recette porte ses ingrédients en commun et leur nombre (score), par score décroissant
+func GetSimilarRecettes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+	id := c.Params("id")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidRecipeID, "ID de recette invalide: un ObjectID de 24 caractères hexadécimaux est attendu")
+	}
+	limit, err := models.ParseSimilarLimit(c.Query("limit"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var recette models.Recette
+	if err := recetteCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&recette); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return responses.SendError(c, 404, responses.CodeRecipeNotFound, "Recette introuvable")
+		}
+		logger.LogError("Échec de la recherche de recette par ID", err, map[string]interface{}{
+			"request_id": requestID,
+			"recipe_id":  id,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération de la recette")
+	}
+
+	// Sans ingrédient nommé, aucune recette ne peut être rapprochée
+	similar := []models.SimilarRecette{}
+	if names := models.IngredientNames(recette); len(names) > 0 {
+		cursor, err := recetteCollection.Aggregate(ctx, models.SimilarPipeline(objID, names, limit))
+		if err != nil {
+			logger.LogError("Échec de la recherche de recettes similaires", err, map[string]interface{}{
+				"request_id": requestID,
+				"recipe_id":  id,
+			})
+			return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la recherche des recettes similaires")
+		}
+		defer cursor.Close(ctx)
+		if err := cursor.All(ctx, &similar); err != nil {
+			logger.LogError("Échec du décodage des recettes similaires", err, map[string]interface{}{
+				"request_id": requestID,
+				"recipe_id":  id,
+			})
+			return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors du décodage des recettes similaires")
+		}
+	}
+
+	logger.LogDatabase(logger.INFO, "Recettes similaires récupérées", "similar", "mongodb", time.Since(start), map[string]interface{}{
+		"request_id":     requestID,
+		"recipe_id":      id,
+		"recettes_count": len(similar),
+	})
+	return responses.SendJSON(c, 200, similar)
+}
+
+// EnsureRecetteIndexes crée les index de la collection recettes s'ils n'existent pas
+// L'index name_ci sert la recherche par nom insensible à la casse et le tri ?sort=name,
+// l'index recipe_id les upserts par identifiant stable
+func EnsureRecetteIndexes(ctx context.Context) error {
+	_, err := recetteCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{models.NameIndexModel(), models.RecipeIDIndexModel()})
+	return err
 }
 
-// GetRecetteByName retourne une recette en fonction de son nom
+// GetRecetteByName retourne une recette en fonction de son nom, sans tenir compte de la casse
 func GetRecetteByName(c *fiber.Ctx) error {
 	start := time.Now()
 	requestID := c.Locals("requestID").(string)
 	nomRecette := strings.ReplaceAll(c.Params("name"), "%20", " ")
 
+	fuzzy, err := models.ParseFuzzy(c.Query("fuzzy"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
 	logger.LogInfo("Recherche de recette par nom", map[string]interface{}{
 		"request_id":  requestID,
 		"recipe_name": nomRecette,
+		"fuzzy":       fuzzy,
 	})
 
-	// Rechercher la recette par nom
-	filter := bson.M{"name": nomRecette}
+	if fuzzy {
+		return getRecettesByFuzzyName(c, requestID, nomRecette, start)
+	}
+
+	// Rechercher la recette par nom, sans tenir compte de la casse (index name_ci)
+	filter, findOptions := models.NameLookup(nomRecette)
 	var recette models.Recette
-	if err := recetteCollection.FindOne(context.Background(), filter).Decode(&recette); err != nil {
+	if err := recetteCollection.FindOne(c.UserContext(), filter, findOptions).Decode(&recette); err != nil {
 		logger.LogError("Recette introuvable par nom", err, map[string]interface{}{
 			"request_id":  requestID,
 			"recipe_name": nomRecette,
 		})
-		return c.Status(404).SendString("Recette introuvable")
+		return responses.SendError(c, 404, responses.CodeRecipeNotFound, "Recette introuvable")
 	}
 
 	duration := time.Since(start)
@@ -229,14 +549,57 @@ func GetRecetteByName(c *fiber.Ctx) error {
 		"recipe_name": nomRecette,
 	})
 
-	return c.Status(200).JSON(recette)
+	return responses.SendJSON(c, 200, recette)
+}
+
+// getRecettesByFuzzyName retourne les recettes dont le nom est proche de nomRecette (?fuzzy=true),
+// de la plus proche à la plus éloignée, avec leur score de similarité
+func getRecettesByFuzzyName(c *fiber.Ctx, requestID, nomRecette string, start time.Time) error {
+	// Présélection en base, scores calculés en Go sur les candidats
+	findOptions := options.Find().SetLimit(models.MaxFuzzyCandidates)
+	cursor, err := recetteCollection.Find(c.UserContext(), models.FuzzyCandidateFilter(nomRecette), findOptions)
+	if err != nil {
+		logger.LogError("Erreur lors de la recherche approchée par nom", err, map[string]interface{}{
+			"request_id":  requestID,
+			"recipe_name": nomRecette,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la recherche des recettes")
+	}
+	var candidates []models.Recette
+	if err := cursor.All(c.UserContext(), &candidates); err != nil {
+		logger.LogError("Erreur lors du décodage des recettes", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors du décodage des recettes")
+	}
+
+	matches := models.RankByName(nomRecette, candidates, models.FuzzyThreshold, models.MaxFuzzyResults)
+
+	duration := time.Since(start)
+	logger.LogDatabase(logger.INFO, "Recherche approchée par nom", "find", "mongodb", duration, map[string]interface{}{
+		"request_id":  requestID,
+		"recipe_name": nomRecette,
+		"candidates":  len(candidates),
+		"matches":     len(matches),
+	})
+
+	if len(matches) == 0 {
+		return responses.SendError(c, 404, responses.CodeRecipeNotFound, "Aucune recette au nom proche")
+	}
+	return responses.SendJSON(c, 200, matches)
 }
 
 // GetRecettesByIngredient retourne toutes les recettes contenant un ingrédient spécifique
+// Accepte les mêmes paramètres de liste que GetAllRecettes
 func GetRecettesByIngredient(c *fiber.Ctx) error {
 	start := time.Now()
 	requestID := c.Locals("requestID").(string)
-	ingredient := c.Params("unit")
+	ingredient := c.Params("ingredient")
+
+	query, err := listParams(c).Parse()
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
 
 	logger.LogInfo("Recherche de recettes par ingrédient", map[string]interface{}{
 		"request_id": requestID,
@@ -244,33 +607,284 @@ func GetRecettesByIngredient(c *fiber.Ctx) error {
 	})
 
 	// Rechercher les recettes par ingrédient
-	filter := bson.M{"ingredients": bson.M{"$elemMatch": bson.M{"unit": ingredient}}}
-	cursor, err := recetteCollection.Find(context.Background(), filter)
+	filter := models.IngredientFilter(ingredient)
+	recettes, count, err := findRecettes(c.UserContext(), filter, query)
 	if err != nil {
 		logger.LogError("Échec de récupération des recettes par ingrédient", err, map[string]interface{}{
 			"request_id": requestID,
 			"ingredient": ingredient,
 		})
-		return c.Status(500).SendString("Erreur lors de la récupération des recettes")
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la récupération des recettes")
 	}
-	defer cursor.Close(context.Background())
 
-	// Décoder les recettes
-	var recettes []models.Recette
-	if err := cursor.All(context.Background(), &recettes); err != nil {
-		logger.LogError("Échec du décodage des recettes par ingrédient", err, map[string]interface{}{
+	duration := time.Since(start)
+	logger.LogDatabase(logger.INFO, "Recettes trouvées par ingrédient", "find_many", "mongodb", duration, map[string]interface{}{
+		"request_id":     requestID,
+		"ingredient":     ingredient,
+		"fields":         query.Fields,
+		"recettes_count": count,
+	})
+
+	return responses.SendJSON(c, 200, recettes)
+}
+
+// DeleteRecettes supprime les recettes correspondant au filtre (protégée par clé API)
+// Paramètres : ?category=, ?ingredient=, ?empty_instructions=true ; au moins un est requis
+func DeleteRecettes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+
+	params := models.DeleteParams{
+		Category:          c.Query("category"),
+		Ingredient:        c.Query("ingredient"),
+		EmptyInstructions: c.Query("empty_instructions"),
+	}
+	filter, err := params.Filter()
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	result, err := recetteCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		logger.LogError("Échec de la suppression des recettes", err, map[string]interface{}{
 			"request_id": requestID,
-			"ingredient": ingredient,
+			"filter":     params,
 		})
-		return c.Status(500).SendString("Erreur lors du décodage des recettes")
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de la suppression des recettes")
 	}
 
 	duration := time.Since(start)
-	logger.LogDatabase(logger.INFO, "Recettes trouvées par ingrédient", "find_many", "mongodb", duration, map[string]interface{}{
-		"request_id":     requestID,
-		"ingredient":     ingredient,
-		"recettes_count": len(recettes),
+	logger.LogDatabase(logger.INFO, "Suppression de recettes terminée", "delete_many", "mongodb", duration, map[string]interface{}{
+		"request_id":    requestID,
+		"filter":        params,
+		"deleted_count": result.DeletedCount,
+	})
+
+	return responses.SendJSON(c, 200, fiber.Map{
+		"deleted_count": result.DeletedCount,
+	})
+}
+
+// ExportRecettes renvoie toutes les recettes en NDJSON (une recette JSON par ligne) à télécharger
+// Les documents sont lus au fil du curseur : la collection n'est jamais chargée entièrement en mémoire
+func ExportRecettes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+
+	// Le contexte doit rester valide pendant tout le streaming, après le retour du handler
+	ctx, cancel := context.WithCancel(c.UserContext())
+	cursor, err := recetteCollection.Find(ctx, bson.M{})
+	if err != nil {
+		cancel()
+		logger.LogError("Échec de l'ouverture du curseur d'export", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de l'export des recettes")
+	}
+
+	c.Set(fiber.HeaderContentType, responses.NDJSONContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"recettes-%s.ndjson\"", time.Now().Format("20060102-150405")))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer cursor.Close(ctx)
+
+		count, err := responses.WriteNDJSON(ctx, w, cursor, func() interface{} { return &models.Recette{} })
+		if err != nil {
+			logger.LogError("Export des recettes interrompu", err, map[string]interface{}{
+				"request_id":     requestID,
+				"recettes_count": count,
+			})
+			return
+		}
+		logger.LogDatabase(logger.INFO, "Export des recettes terminé", "export", "mongodb", time.Since(start), map[string]interface{}{
+			"request_id":     requestID,
+			"recettes_count": count,
+		})
 	})
+	return nil
+}
+
+// importCounts résume le résultat d'un import
+type importCounts struct {
+	Format    string `json:"format"`
+	Inserted  int64  `json:"inserted"`
+	Updated   int64  `json:"updated"`
+	Unchanged int64  `json:"unchanged"` // Recettes dont l'empreinte de contenu est déjà en base, non réécrites
+	Failed    int64  `json:"failed"`
 
-	return c.Status(200).JSON(recettes)
+	Rejected []importRejection `json:"rejected,omitempty"` // Détail des recettes illisibles ou invalides (100 au plus)
+}
+
+// ImportRecettes importe un fichier envoyé en multipart (champ "file"), tableau JSON ou NDJSON
+// Chaque recette est insérée ou mise à jour selon sa page, par lots de BulkWrite
+// Une recette dont l'empreinte de contenu correspond à celle enregistrée n'est pas réécrite
+func ImportRecettes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID := c.Locals("requestID").(string)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Fichier manquant: envoyer le fichier dans le champ multipart \"file\"")
+	}
+	if fileHeader.Size > models.MaxImportBytes {
+		return responses.SendError(c, 413, responses.CodeDataFileTooLarge, fmt.Sprintf("Fichier trop volumineux: %d octets (maximum %d)", fileHeader.Size, models.MaxImportBytes))
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.LogError("Échec d'ouverture du fichier importé", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de la lecture du fichier importé")
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Minute)
+	defer cancel()
+
+	counts := importCounts{}
+	now := time.Now().UTC()
+	pending := make([]models.Recette, 0, models.ImportBatchSize)
+
+	// flush envoie le lot courant ; les erreurs d'écriture comptent comme des échecs sans arrêter l'import
+	var dbErr error
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		err := upsertBatch(ctx, pending, now, &counts, true)
+		pending = pending[:0]
+		if err != nil {
+			dbErr = err
+		}
+		return err
+	}
+
+	format, err := models.ReadRecettes(file, func(recette models.Recette) error {
+		pending = append(pending, recette)
+		if len(pending) >= models.ImportBatchSize {
+			return flush()
+		}
+		return nil
+	}, func(record int, err error) {
+		counts.Failed++
+		if len(counts.Rejected) < maxRejections {
+			rejection := importRejection{Record: record}
+			var validationErr *models.ValidationError
+			if errors.As(err, &validationErr) {
+				rejection.Fields = validationErr.Fields
+			} else {
+				rejection.Error = err.Error()
+			}
+			counts.Rejected = append(counts.Rejected, rejection)
+		}
+		logger.LogInfo("Recette ignorée à l'import", map[string]interface{}{
+			"request_id": requestID,
+			"record":     record,
+			"error":      err.Error(),
+		})
+	})
+	if err == nil {
+		err = flush()
+	}
+	counts.Format = format
+
+	if dbErr != nil {
+		logger.LogError("Échec de l'import des recettes", dbErr, map[string]interface{}{
+			"request_id": requestID,
+			"counts":     counts,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de l'enregistrement des recettes")
+	}
+	if err != nil {
+		// Fichier illisible : les lots déjà envoyés restent enregistrés et sont décomptés dans le message
+		return responses.SendError(c, 400, responses.CodeInvalidData, fmt.Sprintf("Fichier invalide (%d insérées, %d mises à jour avant l'erreur): %v", counts.Inserted, counts.Updated, err))
+	}
+
+	logger.LogDatabase(logger.INFO, "Import des recettes terminé", "bulk_upsert", "mongodb", time.Since(start), map[string]interface{}{
+		"request_id": requestID,
+		"format":     counts.Format,
+		"inserted":   counts.Inserted,
+		"updated":    counts.Updated,
+		"unchanged":  counts.Unchanged,
+		"failed":     counts.Failed,
+	})
+	return responses.SendJSON(c, 200, counts)
+}
+
+// upsertBatch insère ou met à jour un lot de recettes selon leur recipeId (ou leur page) et ajoute le résultat à counts
+// Avec skipUnchanged, les recettes dont l'empreinte de contenu est déjà en base ne sont pas réécrites ;
+// sans, toutes sont écrites (nouveau scrape qui complète image, étiquettes... hors empreinte)
+// Les erreurs d'écriture d'une recette comptent comme des échecs, seule une erreur de la base entière est retournée
+func upsertBatch(ctx context.Context, recettes []models.Recette, now time.Time, counts *importCounts, skipUnchanged bool) error {
+	if len(recettes) == 0 {
+		return nil
+	}
+	changed := recettes
+	if skipUnchanged {
+		stored, err := storedContentHashes(ctx, recettes)
+		if err != nil {
+			return err
+		}
+		var unchanged int
+		changed, unchanged = models.SkipUnchanged(recettes, stored)
+		counts.Unchanged += int64(unchanged)
+		if len(changed) == 0 {
+			return nil
+		}
+	}
+
+	batch := make([]mongo.WriteModel, 0, len(changed))
+	for _, recette := range changed {
+		batch = append(batch, models.UpsertByPage(recette, now))
+	}
+	result, err := recetteCollection.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		counts.Failed += int64(len(bulkErr.WriteErrors))
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	counts.Inserted += result.UpsertedCount
+	counts.Updated += result.MatchedCount
+	return nil
+}
+
+// storedContentHashes lit les empreintes de contenu enregistrées pour les recettes d'un lot, par recipeId
+// Les recettes enregistrées avant l'identifiant sont retrouvées par leur page exacte
+func storedContentHashes(ctx context.Context, recettes []models.Recette) (map[string]string, error) {
+	ids := make([]string, 0, len(recettes))
+	pages := make([]string, 0, len(recettes))
+	for _, recette := range recettes {
+		ids = append(ids, recipe.ID(recette.Page))
+		pages = append(pages, recette.Page)
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"recipeId": bson.M{"$in": ids}},
+		bson.M{"page": bson.M{"$in": pages}},
+	}}
+	cursor, err := recetteCollection.Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"_id": 0, "page": 1, "contentHash": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		Page        string `bson:"page"`
+		ContentHash string `bson:"contentHash"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		stored[recipe.ID(doc.Page)] = doc.ContentHash
+	}
+	return stored, nil
 }
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// newTestApp crée une application avec le request ID posé par le middleware de logging
+func newTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("requestID", "test-request")
+		return c.Next()
+	})
+	return app
+}
+
+// getError exécute une requête GET et décode l'enveloppe d'erreur
+func getError(t *testing.T, app *fiber.App, path string) (int, responses.ErrorResponse) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	var body responses.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+// Test de GET /recette/:id : ID invalide (400) et recette absente (404), sur un serveur MongoDB simulé
+func TestGetRecetteByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("ID invalide", func(mt *mtest.T) {
+		recetteCollection = mt.Coll
+		app := newTestApp()
+		app.Get("/recette/:id", GetRecetteByID)
+
+		status, body := getError(mt.T, app, "/recette/pas-un-id")
+		assert.Equal(mt, 400, status)
+		assert.Equal(mt, responses.CodeInvalidRecipeID, body.Code)
+		assert.Equal(mt, "test-request", body.RequestID)
+	})
+
+	mt.Run("recette absente", func(mt *mtest.T) {
+		recetteCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.recettes", mtest.FirstBatch))
+		app := newTestApp()
+		app.Get("/recette/:id", GetRecetteByID)
+
+		status, body := getError(mt.T, app, "/recette/"+primitive.NewObjectID().Hex())
+		assert.Equal(mt, 404, status)
+		assert.Equal(mt, responses.CodeRecipeNotFound, body.Code)
+	})
+
+	mt.Run("recette trouvée", func(mt *mtest.T) {
+		recetteCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "db.recettes", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "name", Value: "Soupe de légumes"},
+		}))
+		app := newTestApp()
+		app.Get("/recette/:id", GetRecetteByID)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/recette/"+id.Hex(), nil))
+		require.NoError(mt, err)
+		assert.Equal(mt, 200, resp.StatusCode)
+		var body map[string]interface{}
+		require.NoError(mt, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(mt, id.Hex(), body["id"])
+		assert.Equal(mt, "Soupe de légumes", body["name"])
+	})
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+)
+
+// rescrapeTimeout borne l'ensemble des scrapes de POST /scraper/rescrape (chacun reste borné par singleRecipeTimeout)
+const rescrapeTimeout = 15 * time.Minute
+
+// rescrapeResponse est le résultat de POST /scraper/rescrape
+type rescrapeResponse struct {
+	Count     int                     `json:"count"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Saved     importCounts            `json:"saved"` // Enregistrement des recettes scrapées (upsert par page)
+	Results   []models.RescrapeResult `json:"results"`
+}
+
+// RescrapeRecipes scrape de nouveau une liste de recettes ({"urls": [...]}) et enregistre celles obtenues
+// Les scrapes (mode -recipe du scraper) sont lancés en parallèle, models.RescrapeConcurrency au plus ;
+// la réponse détaille le résultat de chaque URL, un échec n'interrompt pas les autres
+func RescrapeRecipes(c *fiber.Ctx) error {
+	start := time.Now()
+	requestID, _ := c.Locals("requestID").(string)
+
+	var body models.RescrapeRequest
+	if err := c.BodyParser(&body); err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Corps JSON invalide: {\"urls\": [\"https://www.allrecipes.com/recipe/...\"]} attendu")
+	}
+	if err := body.Validate(); err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+
+	logger.LogInfo("Nouveau scrape d'une liste de recettes", map[string]interface{}{
+		"request_id": requestID,
+		"urls":       len(body.URLs),
+	})
+
+	// Même garde que /scraper/run : la liste compte pour une exécution, SCRAPER_MIN_INTERVAL entre deux lancements
+	reservedAt, err := beginScraperRun(true)
+	if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+		return respErr
+	}
+	var started atomic.Bool // Au moins un scraper lancé : la réservation est gardée
+	scrapeCtx, cancelScrape := context.WithTimeout(c.UserContext(), rescrapeTimeout)
+	defer cancelScrape()
+	results := models.RescrapeURLs(scrapeCtx, body.URLs, models.RescrapeConcurrency, func(ctx context.Context, recipeURL string) (models.Recette, error) {
+		ctx, cancel := context.WithTimeout(ctx, singleRecipeTimeout)
+		defer cancel()
+		recette, err := singleRecipeScraper(ctx, recipeURL, requestID)
+		if !errors.Is(err, ErrScraperNotStarted) {
+			started.Store(true)
+		}
+		return recette, err
+	})
+	endScraperRun(reservedAt, started.Load())
+
+	response := rescrapeResponse{Count: len(results), Saved: importCounts{Format: "json"}, Results: results}
+	var recettes []models.Recette
+	for _, result := range results {
+		if result.Status != models.RescrapeSucceeded {
+			response.Failed++
+			logger.LogWarn("Échec du nouveau scrape d'une recette", map[string]interface{}{
+				"request_id": requestID,
+				"page":       result.URL,
+				"error":      result.Error,
+			})
+			continue
+		}
+		response.Succeeded++
+		recettes = append(recettes, *result.Recette)
+	}
+
+	// Toutes les recettes obtenues sont réécrites : l'empreinte ne couvre que le nom, les ingrédients et
+	// les instructions, un nouveau scrape sert aussi à compléter l'image, les étiquettes, recipeId...
+	ctx, cancel := context.WithTimeout(c.UserContext(), time.Minute)
+	defer cancel()
+	if err := upsertBatch(ctx, recettes, time.Now().UTC(), &response.Saved, false); err != nil {
+		logger.LogError("Échec de l'enregistrement des recettes scrapées de nouveau", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeDatabaseError, "Erreur lors de l'enregistrement des recettes")
+	}
+
+	logger.LogInfo("Liste de recettes scrapée de nouveau", map[string]interface{}{
+		"request_id": requestID,
+		"count":      response.Count,
+		"succeeded":  response.Succeeded,
+		"failed":     response.Failed,
+		"duration":   time.Since(start).String(),
+	})
+	return responses.SendJSON(c, 200, response)
+}
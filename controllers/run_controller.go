@@ -2,17 +2,136 @@ package controllers
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/fileutil"
 	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/maxime-louis14/api-golang/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// scraperRunner exécute le scraper de façon synchrone (remplaçable dans les tests)
+var scraperRunner = RunScraper
+
+// launchScraperDelay est l'attente de LaunchScraper avant le lancement (remplaçable dans les tests)
+var launchScraperDelay = 4 * time.Second
+
+// ErrScraperRunning est retournée quand une exécution du scraper est déjà en cours
+var ErrScraperRunning = errors.New("le scraper est déjà en cours d'exécution")
+
+// scraperRunLock garantit une seule exécution du scraper à la fois (API, planification, rafraîchissement)
+var scraperRunLock atomic.Bool
+
+// scraperThrottle impose SCRAPER_MIN_INTERVAL entre deux exécutions lancées par l'API
+var scraperThrottle = models.NewRunThrottle(scraperMinInterval())
+
+// scraperMinInterval lit SCRAPER_MIN_INTERVAL ; une valeur invalide garde le défaut (5m)
+func scraperMinInterval() time.Duration {
+	value := strings.TrimSpace(os.Getenv("SCRAPER_MIN_INTERVAL"))
+	interval, err := models.ParseMinInterval(value)
+	if err != nil {
+		logger.LogWarn("SCRAPER_MIN_INTERVAL invalide, intervalle par défaut conservé", map[string]interface{}{
+			"value":        value,
+			"min_interval": models.DefaultScraperMinInterval.String(),
+		})
+		return models.DefaultScraperMinInterval
+	}
+	return interval
+}
+
+// ErrScraperNotStarted signale un scraper qui n'a pas démarré (binaire absent, lancement impossible) :
+// le site n'a pas été sollicité, l'exécution ne compte pas dans l'intervalle minimal
+var ErrScraperNotStarted = errors.New("le scraper n'a pas démarré")
+
+// runScraperExclusive exécute le scraper si aucune autre exécution n'est en cours, ErrScraperRunning sinon
+// ctx porte la trace de la requête à l'origine de l'exécution (context.Background() hors requête)
+// Une exécution demandée par l'API (fromAPI) respecte SCRAPER_MIN_INTERVAL (*models.RunTooSoonError sinon) ;
+// une exécution planifiée n'est jamais refusée, mais sa date compte pour les demandes suivantes
+func runScraperExclusive(ctx context.Context, requestID string, fromAPI bool) error {
+	reservedAt, err := beginScraperRun(fromAPI)
+	if err != nil {
+		return err
+	}
+	err = scraperRunner(ctx, requestID)
+	endScraperRun(reservedAt, !errors.Is(err, ErrScraperNotStarted))
+	return err
+}
+
+// beginScraperRun prend le verrou d'exécution (ErrScraperRunning) puis enregistre le lancement dans
+// scraperThrottle (*models.RunTooSoonError pour une demande de l'API trop proche de la précédente)
+// Toute route qui lance des processus scraper passe par là ; sans erreur, l'appelant doit appeler endScraperRun
+func beginScraperRun(fromAPI bool) (time.Time, error) {
+	if !scraperRunLock.CompareAndSwap(false, true) {
+		return time.Time{}, ErrScraperRunning
+	}
+	now := time.Now()
+	if !fromAPI {
+		scraperThrottle.Record(now)
+		return now, nil
+	}
+	if err := scraperThrottle.Reserve(now); err != nil {
+		scraperRunLock.Store(false)
+		return time.Time{}, err
+	}
+	return now, nil
+}
+
+// endScraperRun libère le verrou d'exécution ; un scraper qui n'a pas démarré (started false)
+// rend aussi sa réservation, pour ne pas bloquer les demandes suivantes pendant SCRAPER_MIN_INTERVAL
+func endScraperRun(reservedAt time.Time, started bool) {
+	if !started {
+		scraperThrottle.Release(reservedAt)
+	}
+	scraperRunLock.Store(false)
+}
+
+// respondScraperUnavailable renvoie 409 (exécution en cours) ou 429 (intervalle minimal) pour une erreur
+// de beginScraperRun ; handled est faux pour toute autre erreur
+func respondScraperUnavailable(c *fiber.Ctx, requestID string, err error) (handled bool, _ error) {
+	var tooSoon *models.RunTooSoonError
+	switch {
+	case errors.Is(err, ErrScraperRunning):
+		logger.LogInfo("Scraper déjà en cours d'exécution", map[string]interface{}{
+			"request_id": requestID,
+		})
+		return true, responses.SendError(c, 409, responses.CodeScraperRunning, err.Error())
+	case errors.As(err, &tooSoon):
+		return true, respondTooSoon(c, requestID, tooSoon)
+	}
+	return false, nil
+}
+
+// respondTooSoon renvoie 429 avec l'attente restante (en-tête Retry-After et details)
+func respondTooSoon(c *fiber.Ctx, requestID string, tooSoon *models.RunTooSoonError) error {
+	logger.LogInfo("Exécution du scraper refusée: intervalle minimal non écoulé", map[string]interface{}{
+		"request_id":          requestID,
+		"retry_after_seconds": tooSoon.RetryAfterSeconds(),
+	})
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(tooSoon.RetryAfterSeconds()))
+	return responses.SendErrorDetails(c, 429, responses.CodeScraperTooSoon, tooSoon.Error(), fiber.Map{
+		"retry_after_seconds": tooSoon.RetryAfterSeconds(),
+		"min_interval":        tooSoon.Interval.String(),
+		"last_run":            scraperThrottle.LastRun().UTC().Format(time.RFC3339),
+	})
+}
+
 // LaunchScraper lance le scraper via une route API
 func LaunchScraper(c *fiber.Ctx) error {
 	start := time.Now()
@@ -23,15 +142,21 @@ func LaunchScraper(c *fiber.Ctx) error {
 	})
 
 	// Ajoute un délai de 4 secondes
-	time.Sleep(4 * time.Second)
+	time.Sleep(launchScraperDelay)
 
 	// Exécute le scraper
-	if err := RunScraper(); err != nil {
+	runStart := time.Now()
+	if err := runScraperExclusive(c.UserContext(), requestID, true); err != nil {
+		if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+			return respErr
+		}
+		logger.RecordScraperRun(false, time.Since(runStart))
 		logger.LogError("Erreur lors de l'exécution du scraper", err, map[string]interface{}{
 			"request_id": requestID,
 		})
-		return c.Status(500).SendString("Erreur lors de l'exécution du scraper")
+		return responses.SendError(c, 500, responses.CodeScraperFailed, "Erreur lors de l'exécution du scraper")
 	}
+	logger.RecordScraperRun(true, time.Since(runStart))
 
 	duration := time.Since(start)
 	logger.LogInfo("Scraper exécuté avec succès", map[string]interface{}{
@@ -42,11 +167,87 @@ func LaunchScraper(c *fiber.Ctx) error {
 	return c.Status(200).SendString("Scraper exécuté avec succès")
 }
 
+// defaultScraperPath est l'emplacement du binaire scraper dans l'image Docker
+const defaultScraperPath = "/app/scraper"
+
+// scraperDataDir est le répertoire de travail du scraper (data.json, stats.json, scraper.log)
+const scraperDataDir = "/go_api_mongo_scrapper/scraper"
+
+// GetScraperLogPath retourne le chemin de scraper.log (configurable via SCRAPER_LOG_PATH)
+func GetScraperLogPath() string {
+	if path := os.Getenv("SCRAPER_LOG_PATH"); path != "" {
+		return path
+	}
+	return filepath.Join(scraperDataDir, "scraper.log")
+}
+
+// GetScraperStatusPath retourne le chemin de status.json (configurable via SCRAPER_STATUS_PATH)
+func GetScraperStatusPath() string {
+	if path := os.Getenv("SCRAPER_STATUS_PATH"); path != "" {
+		return path
+	}
+	return filepath.Join(scraperDataDir, "status.json")
+}
+
+// GetScraperPath retourne le chemin du binaire scraper (configurable via SCRAPER_PATH)
+func GetScraperPath() string {
+	if path := os.Getenv("SCRAPER_PATH"); path != "" {
+		return path
+	}
+	return defaultScraperPath
+}
+
+// CheckScraperBinary vérifie que le binaire scraper existe et est exécutable
+func CheckScraperBinary(scraperPath string) error {
+	info, err := os.Stat(scraperPath)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s est un répertoire", scraperPath)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%s n'est pas exécutable", scraperPath)
+	}
+	return nil
+}
+
+// scraperRequestIDEnv transmet le request ID de l'appel API au scraper, qui le logge au démarrage
+const scraperRequestIDEnv = "SCRAPER_REQUEST_ID"
+
+// scraperEnv retourne l'environnement du scraper : celui de l'API, le request ID et le contexte de trace
+// de ctx (TRACEPARENT), pour rattacher l'exécution à la requête d'origine
+func scraperEnv(ctx context.Context, requestID string) []string {
+	env := append(os.Environ(), scraperRequestIDEnv+"="+requestID)
+	return tracing.InjectEnv(ctx, env)
+}
+
+// startScraperSpan ouvre le span d'un lancement du scraper, enfant de ctx
+func startScraperSpan(ctx context.Context, name, scraperPath, requestID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.ProcessExecutablePath(scraperPath))
+	return tracing.Start(ctx, name, requestID, attrs...)
+}
+
+// scraperCommand prépare l'exécution du scraper dans dataDir, avec le request ID et la trace de ctx dans son environnement
+func scraperCommand(ctx context.Context, scraperPath, dataDir, requestID string) *exec.Cmd {
+	cmd := exec.Command(scraperPath)
+	cmd.Dir = dataDir
+	cmd.Env = scraperEnv(ctx, requestID)
+	return cmd
+}
+
 // RunScraper exécute le binaire du scraper
-func RunScraper() error {
+// ctx : contexte de la requête API à l'origine de l'exécution, dont le span devient le parent de scraper.run
+// et dont la trace est transmise au scraper (TRACEPARENT) ; hors requête (planification), context.Background()
+// ouvre une nouvelle trace
+// requestID : identifiant de la requête API à l'origine de l'exécution (tracé dans scraper.log)
+func RunScraper(ctx context.Context, requestID string) (err error) {
 	start := time.Now()
 	// Chemin vers le binaire du scraper
-	scraperPath := "/app/scraper"
+	scraperPath := GetScraperPath()
+
+	ctx, span := startScraperSpan(ctx, "scraper.run", scraperPath, requestID)
+	defer func() { tracing.End(span, err) }()
 
 	logger.LogInfo("Vérification de l'existence du binaire scraper", map[string]interface{}{
 		"scraper_path": scraperPath,
@@ -57,7 +258,7 @@ func RunScraper() error {
 		logger.LogError("Binaire scraper introuvable", err, map[string]interface{}{
 			"scraper_path": scraperPath,
 		})
-		return err
+		return fmt.Errorf("%w: %v", ErrScraperNotStarted, err)
 	}
 
 	logger.LogInfo("Lancement du binaire scraper", map[string]interface{}{
@@ -65,7 +266,7 @@ func RunScraper() error {
 	})
 
 	// S'assurer que le répertoire de sauvegarde existe
-	dataDir := "/go_api_mongo_scrapper/scraper"
+	dataDir := scraperDataDir
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		logger.LogError("Erreur lors de la création du répertoire de sauvegarde", err, map[string]interface{}{
 			"data_dir": dataDir,
@@ -74,17 +275,21 @@ func RunScraper() error {
 	}
 
 	// Commande pour exécuter le scraper
-	cmd := exec.Command(scraperPath)
-
-	// Définir le répertoire de travail pour que le fichier data.json soit sauvegardé dans un emplacement connu
-	cmd.Dir = dataDir
+	// Le répertoire de travail garantit que data.json est sauvegardé dans un emplacement connu
+	cmd := scraperCommand(ctx, scraperPath, dataDir, requestID)
 
 	// Associe les sorties standard et erreur du scraper aux sorties du serveur
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	// Exécute la commande
-	if err := cmd.Run(); err != nil {
+	// Exécute la commande ; un échec du lancement n'a pas sollicité le site
+	if err := cmd.Start(); err != nil {
+		logger.LogError("Échec du lancement du scraper", err, map[string]interface{}{
+			"scraper_path": scraperPath,
+		})
+		return fmt.Errorf("%w: %v", ErrScraperNotStarted, err)
+	}
+	if err := cmd.Wait(); err != nil {
 		logger.LogError("Échec de l'exécution du scraper", err, map[string]interface{}{
 			"scraper_path": scraperPath,
 		})
@@ -111,6 +316,28 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	requestID := c.Locals("requestID").(string)
 	start := time.Now()
 
+	// Chemin vers le binaire du scraper
+	scraperPath := GetScraperPath()
+
+	// Vérifie que le binaire existe et est exécutable, avant de réserver l'exécution
+	if err := CheckScraperBinary(scraperPath); err != nil {
+		errorMsg := fmt.Sprintf("❌ Binaire scraper inutilisable: %v", err)
+		logger.LogError("Binaire scraper inutilisable", err, map[string]interface{}{
+			"scraper_path": scraperPath,
+			"request_id":   requestID,
+		})
+		return responses.SendError(c, 500, responses.CodeScraperNotFound, errorMsg)
+	}
+
+	// Une seule exécution à la fois et intervalle minimal, comme /scraper/run ; vérifiés avant de passer
+	// en SSE : le refus est une réponse JSON. Le scraper tourne pendant la requête : le verrou est libéré à la fin du flux
+	reservedAt, err := beginScraperRun(true)
+	if handled, respErr := respondScraperUnavailable(c, requestID, err); handled {
+		return respErr
+	}
+	started := false
+	defer func() { endScraperRun(reservedAt, started) }()
+
 	// Configuration des headers pour Server-Sent Events (SSE)
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
@@ -121,19 +348,6 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 		"request_id": requestID,
 	})
 
-	// Chemin vers le binaire du scraper
-	scraperPath := "/app/scraper"
-
-	// Vérifie que le fichier existe
-	if _, err := os.Stat(scraperPath); os.IsNotExist(err) {
-		errorMsg := fmt.Sprintf("❌ Binaire scraper introuvable: %s", scraperPath)
-		logger.LogError("Binaire scraper introuvable", err, map[string]interface{}{
-			"scraper_path": scraperPath,
-			"request_id":   requestID,
-		})
-		return c.Status(500).SendString(errorMsg)
-	}
-
 	// Utiliser directement BodyWriter pour le streaming
 	w := c.Context().Response.BodyWriter()
 
@@ -147,7 +361,7 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	fmt.Fprintf(w, "data: %s\n\n", jsonData)
 
 	// S'assurer que le répertoire de sauvegarde existe
-	dataDir := "/go_api_mongo_scrapper/scraper"
+	dataDir := scraperDataDir
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		logger.LogError("Erreur lors de la création du répertoire de sauvegarde", err, map[string]interface{}{
 			"data_dir":   dataDir,
@@ -157,10 +371,11 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	}
 
 	// Commande pour exécuter le scraper
-	cmd := exec.Command(scraperPath)
-
-	// Définir le répertoire de travail pour que le fichier data.json soit sauvegardé dans un emplacement connu
-	cmd.Dir = dataDir
+	// Le répertoire de travail garantit que data.json est sauvegardé dans un emplacement connu
+	runCtx, span := startScraperSpan(c.UserContext(), "scraper.run", scraperPath, requestID)
+	var runErr error
+	defer func() { tracing.End(span, runErr) }()
+	cmd := scraperCommand(runCtx, scraperPath, dataDir, requestID)
 
 	// Créer des pipes pour capturer stdout et stderr
 	stdoutPipe, err := cmd.StdoutPipe()
@@ -190,7 +405,10 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	}
 
 	// Démarrer la commande
+	runStart := time.Now()
 	if err := cmd.Start(); err != nil {
+		runErr = err
+		logger.RecordScraperRun(false, time.Since(runStart))
 		errorMsg := fmt.Sprintf("❌ Erreur lors du démarrage du scraper: %v", err)
 		msg := LogMessage{
 			Type:      "error",
@@ -204,6 +422,7 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 		})
 		return err
 	}
+	started = true
 
 	// WaitGroup pour synchroniser les goroutines
 	var wg sync.WaitGroup
@@ -245,6 +464,9 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	// Attendre la fin de l'exécution
 	err = cmd.Wait()
 	wg.Wait() // Attendre que toutes les goroutines de lecture soient terminées
+	runErr = err
+
+	logger.RecordScraperRun(err == nil, time.Since(runStart))
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("❌ Le scraper s'est terminé avec une erreur: %v", err)
@@ -281,6 +503,20 @@ func LaunchScraperStream(c *fiber.Ctx) error {
 	return nil
 }
 
+// scraperDataPaths liste les emplacements possibles du fichier data.json, par ordre de priorité
+var scraperDataPaths = []string{
+	"/app/data.json", // Répertoire de travail de l'API
+	"/go_api_mongo_scrapper/scraper/data.json", // Volume partagé scraper_data
+	"./data.json", // Répertoire courant
+	"data.json",   // Répertoire courant (relatif)
+}
+
+// findScraperData retourne le fichier de recettes le plus récent : data.json ou data-{timestamp}.json
+// (historique des exécutions), dans le premier emplacement de scraperDataPaths qui en contient
+func findScraperData() (string, bool) {
+	return models.LatestDataFile(scraperDataPaths, scraperspec.TimestampedDataPattern)
+}
+
 // GetScraperData récupère le fichier JSON généré par le scraper
 func GetScraperData(c *fiber.Ctx) error {
 	requestID := "unknown"
@@ -288,35 +524,27 @@ func GetScraperData(c *fiber.Ctx) error {
 		requestID = id
 	}
 
-	// Emplacements possibles du fichier data.json
-	possiblePaths := []string{
-		"/app/data.json", // Répertoire de travail de l'API
-		"/go_api_mongo_scrapper/scraper/data.json", // Volume partagé scraper_data
-		"./data.json", // Répertoire courant
-		"data.json",   // Répertoire courant (relatif)
-	}
-
-	var filePath string
-	var found bool
-
-	// Chercher le fichier dans les emplacements possibles
-	for _, path := range possiblePaths {
-		if _, err := os.Stat(path); err == nil {
-			filePath = path
-			found = true
-			break
-		}
-	}
-
+	filePath, found := findScraperData()
 	if !found {
 		logger.LogError("Fichier data.json introuvable", nil, map[string]interface{}{
 			"request_id":     requestID,
-			"searched_paths": possiblePaths,
+			"searched_paths": scraperDataPaths,
 		})
-		return c.Status(404).JSON(fiber.Map{
-			"error":   true,
-			"message": "Fichier data.json introuvable. Le scraper n'a peut-être pas encore été exécuté.",
+		return responses.SendError(c, 404, responses.CodeDataFileNotFound, "Fichier data.json introuvable. Le scraper n'a peut-être pas encore été exécuté.")
+	}
+
+	// Vérifier la taille avant de lire le fichier (?max_bytes ou SCRAPER_MAX_DOWNLOAD_BYTES)
+	limit, err := fileutil.DownloadLimit(c.Query("max_bytes"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	if _, err := fileutil.CheckDownloadSize(filePath, limit); errors.Is(err, fileutil.ErrDownloadTooLarge) {
+		logger.LogInfo("Fichier data.json trop volumineux pour être téléchargé", map[string]interface{}{
+			"request_id": requestID,
+			"file_path":  filePath,
+			"max_bytes":  limit,
 		})
+		return responses.SendError(c, 413, responses.CodeDataFileTooLarge, err.Error())
 	}
 
 	// Lire le fichier
@@ -326,10 +554,7 @@ func GetScraperData(c *fiber.Ctx) error {
 			"request_id": requestID,
 			"file_path":  filePath,
 		})
-		return c.Status(500).JSON(fiber.Map{
-			"error":   true,
-			"message": "Erreur lors de la lecture du fichier",
-		})
+		return responses.SendError(c, 500, responses.CodeDataFileError, "Erreur lors de la lecture du fichier")
 	}
 
 	// Obtenir les informations du fichier
@@ -357,3 +582,93 @@ func GetScraperData(c *fiber.Ctx) error {
 	// Envoyer le fichier
 	return c.Send(fileContent)
 }
+
+// GetScraperLogs renvoie les dernières lignes de scraper.log (?tail=200 par défaut, ?format=text|json)
+func GetScraperLogs(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+
+	tail, err := fileutil.ParseTail(c.Query("tail"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	format := c.Query("format", "json")
+	if format != "json" && format != "text" {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, fmt.Sprintf("format invalide %q: \"json\" ou \"text\" attendu", format))
+	}
+
+	logPath := GetScraperLogPath()
+	lines, err := fileutil.TailLines(logPath, tail)
+	if errors.Is(err, os.ErrNotExist) {
+		return responses.SendError(c, 404, responses.CodeLogFileNotFound, "Fichier scraper.log introuvable. Le scraper n'a peut-être pas encore été exécuté.")
+	}
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture de scraper.log", err, map[string]interface{}{
+			"request_id": requestID,
+			"log_path":   logPath,
+		})
+		return responses.SendError(c, 500, responses.CodeLogFileError, "Erreur lors de la lecture du fichier de log")
+	}
+
+	if format == "text" {
+		c.Set("Content-Type", "text/plain; charset=utf-8")
+		if len(lines) == 0 {
+			return c.SendString("")
+		}
+		return c.SendString(strings.Join(lines, "\n") + "\n")
+	}
+	return responses.SendJSON(c, 200, fiber.Map{
+		"path":  logPath,
+		"count": len(lines),
+		"lines": lines,
+	})
+}
+
+// GetScraperStatus renvoie la progression de l'exécution en cours (ou de la dernière) du scraper
+// Le scraper réécrit status.json chaque seconde : une exécution qui ne le met plus à jour est signalée "stale"
+func GetScraperStatus(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+
+	statusPath := GetScraperStatusPath()
+	status, err := models.LoadScraperStatus(statusPath, time.Now())
+	if errors.Is(err, os.ErrNotExist) {
+		return responses.SendError(c, 404, responses.CodeStatusNotFound, "Fichier status.json introuvable. Le scraper n'a peut-être pas encore été exécuté.")
+	}
+	if err != nil {
+		logger.LogError("Erreur lors de la lecture de status.json", err, map[string]interface{}{
+			"request_id":  requestID,
+			"status_path": statusPath,
+		})
+		return responses.SendError(c, 500, responses.CodeStatusFileError, "Erreur lors de la lecture du fichier de statut")
+	}
+
+	return responses.SendJSON(c, 200, status)
+}
+
+// ClearScraperLogs vide scraper.log et renvoie le nombre d'octets libérés (protégée par clé API)
+// Le scraper tourne dans un autre processus : le fichier est tronqué, ses écritures en append reprennent au début
+func ClearScraperLogs(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+
+	logPath := GetScraperLogPath()
+	freed, err := fileutil.TruncateFile(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return responses.SendError(c, 404, responses.CodeLogFileNotFound, "Fichier scraper.log introuvable. Le scraper n'a peut-être pas encore été exécuté.")
+	}
+	if err != nil {
+		logger.LogError("Erreur lors de la troncature de scraper.log", err, map[string]interface{}{
+			"request_id": requestID,
+			"log_path":   logPath,
+		})
+		return responses.SendError(c, 500, responses.CodeLogFileError, "Erreur lors de la troncature du fichier de log")
+	}
+
+	logger.LogInfo("Fichier scraper.log vidé", map[string]interface{}{
+		"request_id":  requestID,
+		"log_path":    logPath,
+		"freed_bytes": freed,
+	})
+	return responses.SendJSON(c, 200, fiber.Map{
+		"path":        logPath,
+		"freed_bytes": freed,
+	})
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/middleware"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useScraperRunner remplace le scraper par run, sans délai ni intervalle minimal, pour la durée du test
+func useScraperRunner(t *testing.T, run func(ctx context.Context, requestID string) error) {
+	savedRunner, savedDelay, savedThrottle := scraperRunner, launchScraperDelay, scraperThrottle
+	scraperRunner, launchScraperDelay, scraperThrottle = run, 0, models.NewRunThrottle(0)
+	t.Cleanup(func() {
+		scraperRunner, launchScraperDelay, scraperThrottle = savedRunner, savedDelay, savedThrottle
+	})
+}
+
+// Test de POST /scraper/run : succès (200), exécution déjà en cours (409) et échec du scraper (500)
+func TestLaunchScraper(t *testing.T) {
+	app := newTestApp()
+	app.Post("/scraper/run", LaunchScraper)
+	post := func() (int, string) {
+		resp, err := app.Test(httptest.NewRequest("POST", "/scraper/run", nil), -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("succès", func(t *testing.T) {
+		var requestID string
+		useScraperRunner(t, func(ctx context.Context, id string) error {
+			requestID = id
+			return nil
+		})
+
+		status, body := post()
+		assert.Equal(t, 200, status)
+		assert.Equal(t, "Scraper exécuté avec succès", body)
+		assert.Equal(t, "test-request", requestID)
+	})
+
+	t.Run("exécution en cours", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		useScraperRunner(t, func(ctx context.Context, id string) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		first := make(chan int)
+		go func() {
+			status, _ := post()
+			first <- status
+		}()
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("le scraper simulé n'a pas démarré")
+		}
+
+		status, body := post()
+		assert.Equal(t, 409, status)
+		assert.Contains(t, body, responses.CodeScraperRunning)
+
+		close(release)
+		assert.Equal(t, 200, <-first)
+	})
+
+	t.Run("échec du scraper", func(t *testing.T) {
+		useScraperRunner(t, func(ctx context.Context, id string) error {
+			return errors.New("exit status 1")
+		})
+
+		status, body := post()
+		assert.Equal(t, 500, status)
+		assert.Contains(t, body, responses.CodeScraperFailed)
+
+		// Le verrou est libéré : une nouvelle demande est acceptée
+		useScraperRunner(t, func(ctx context.Context, id string) error { return nil })
+		status, _ = post()
+		assert.Equal(t, 200, status)
+	})
+}
+
+// Un 409 reçu avec un Idempotency-Key n'est pas mémorisé : la même clé lance le scraper une fois le verrou libéré
+func TestLaunchScraperIdempotencyAfterConflict(t *testing.T) {
+	runs := 0
+	useScraperRunner(t, func(ctx context.Context, id string) error {
+		runs++
+		return nil
+	})
+	app := newTestApp()
+	app.Post("/scraper/run", middleware.Idempotency(middleware.DefaultIdempotencyTTL), LaunchScraper)
+	post := func() (int, string) {
+		req := httptest.NewRequest("POST", "/scraper/run", nil)
+		req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	// Une autre exécution tient le verrou
+	require.True(t, scraperRunLock.CompareAndSwap(false, true))
+	status, body := post()
+	assert.Equal(t, 409, status)
+	assert.Contains(t, body, responses.CodeScraperRunning)
+	assert.Zero(t, runs)
+
+	scraperRunLock.Store(false)
+	status, body = post()
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "Scraper exécuté avec succès", body)
+	assert.Equal(t, 1, runs)
+}
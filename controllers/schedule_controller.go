@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/maxime-louis14/api-golang/scheduler"
+)
+
+// scraperScheduler lance le scraper selon la planification gérée par /scraper/schedule (nil avant StartScraperScheduler)
+var scraperScheduler *scheduler.Scheduler
+
+// GetScraperSchedulePath retourne le chemin du fichier de planification (configurable via SCRAPER_SCHEDULE_PATH)
+func GetScraperSchedulePath() string {
+	if path := os.Getenv("SCRAPER_SCHEDULE_PATH"); path != "" {
+		return path
+	}
+	return filepath.Join(scraperDataDir, "scraper_schedule.json")
+}
+
+// StartScraperScheduler démarre le planificateur avec la planification persistée
+func StartScraperScheduler() (*scheduler.Scheduler, error) {
+	s, err := scheduler.New(GetScraperSchedulePath(), runScheduledScraper)
+	if err != nil {
+		return nil, err
+	}
+	scraperScheduler = s
+	return s, nil
+}
+
+// runScheduledScraper exécute le scraper pour une échéance de la planification
+func runScheduledScraper() {
+	requestID := fmt.Sprintf("schedule-%d", time.Now().Unix())
+	logger.LogInfo("Démarrage du scraper planifié", map[string]interface{}{
+		"request_id": requestID,
+	})
+
+	runStart := time.Now()
+	if err := runScraperExclusive(context.Background(), requestID, false); errors.Is(err, ErrScraperRunning) {
+		logger.LogInfo("Échéance ignorée: scraper déjà en cours d'exécution", map[string]interface{}{
+			"request_id": requestID,
+		})
+		return
+	} else if err != nil {
+		logger.RecordScraperRun(false, time.Since(runStart))
+		logger.LogError("Erreur lors de l'exécution planifiée du scraper", err, map[string]interface{}{
+			"request_id": requestID,
+		})
+		return
+	}
+	logger.RecordScraperRun(true, time.Since(runStart))
+}
+
+// scheduleRequest est le corps attendu par PUT /scraper/schedule
+type scheduleRequest struct {
+	Schedule string `json:"schedule"`
+}
+
+// GetScraperSchedule retourne la planification active et la prochaine exécution
+func GetScraperSchedule(c *fiber.Ctx) error {
+	if scraperScheduler == nil {
+		return responses.SendError(c, 503, responses.CodeConfigError, "Planificateur du scraper indisponible")
+	}
+	return responses.SendJSON(c, 200, scraperScheduler.Status())
+}
+
+// UpdateScraperSchedule définit la planification (protégée par clé API), persistée pour les prochains démarrages
+func UpdateScraperSchedule(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+	if scraperScheduler == nil {
+		return responses.SendError(c, 503, responses.CodeConfigError, "Planificateur du scraper indisponible")
+	}
+
+	var body scheduleRequest
+	if err := c.BodyParser(&body); err != nil || body.Schedule == "" {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, "Corps JSON invalide: {\"schedule\": \"@every 6h\"} ou cron à 5 champs attendu")
+	}
+
+	status, err := scraperScheduler.Set(body.Schedule)
+	if errors.Is(err, scheduler.ErrInvalidSchedule) {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	if err != nil {
+		logger.LogError("Erreur lors de l'enregistrement de la planification du scraper", err, map[string]interface{}{
+			"request_id":    requestID,
+			"schedule_path": GetScraperSchedulePath(),
+		})
+		return responses.SendError(c, 500, responses.CodeConfigError, "Erreur lors de l'enregistrement de la planification du scraper")
+	}
+
+	logger.LogInfo("Planification du scraper mise à jour", map[string]interface{}{
+		"request_id": requestID,
+		"schedule":   status.Schedule,
+	})
+	return responses.SendJSON(c, 200, status)
+}
+
+// DeleteScraperSchedule désactive la planification (protégée par clé API)
+func DeleteScraperSchedule(c *fiber.Ctx) error {
+	requestID, _ := c.Locals("requestID").(string)
+	if scraperScheduler == nil {
+		return responses.SendError(c, 503, responses.CodeConfigError, "Planificateur du scraper indisponible")
+	}
+
+	if err := scraperScheduler.Disable(); err != nil {
+		logger.LogError("Erreur lors de la désactivation de la planification du scraper", err, map[string]interface{}{
+			"request_id":    requestID,
+			"schedule_path": GetScraperSchedulePath(),
+		})
+		return responses.SendError(c, 500, responses.CodeConfigError, "Erreur lors de la désactivation de la planification du scraper")
+	}
+
+	logger.LogInfo("Planification du scraper désactivée", map[string]interface{}{
+		"request_id": requestID,
+	})
+	return responses.SendJSON(c, 200, scraperScheduler.Status())
+}
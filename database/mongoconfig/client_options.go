@@ -0,0 +1,94 @@
+// Package mongoconfig construit les options du client MongoDB à partir des variables d'environnement
+// Séparé du package database, qui se connecte dès son initialisation, pour pouvoir être testé
+package mongoconfig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Variables d'environnement appliquées aux options du client (elles priment sur les paramètres de l'URI)
+const (
+	EnvWriteConcern = "MONGO_WRITE_CONCERN" // "majority" ou nombre de membres devant acquitter l'écriture
+	EnvRetryWrites  = "MONGO_RETRY_WRITES"  // true/false
+	EnvMaxPoolSize  = "MONGO_MAX_POOL_SIZE" // Connexions maximum par serveur (0 = illimité)
+	EnvMinPoolSize  = "MONGO_MIN_POOL_SIZE" // Connexions gardées ouvertes par serveur
+)
+
+// ClientOptions retourne les options du client pour l'URI, complétées par les variables d'environnement
+// Une variable absente laisse la valeur de l'URI ou le défaut du driver
+func ClientOptions(uri string) (*options.ClientOptions, error) {
+	clientOptions := options.Client().ApplyURI(uri)
+
+	if value := strings.TrimSpace(os.Getenv(EnvWriteConcern)); value != "" {
+		wc, err := parseWriteConcern(value)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetWriteConcern(wc)
+	}
+
+	if value := strings.TrimSpace(os.Getenv(EnvRetryWrites)); value != "" {
+		retry, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s invalide %q: true ou false attendu", EnvRetryWrites, value)
+		}
+		clientOptions.SetRetryWrites(retry)
+	}
+
+	if value := strings.TrimSpace(os.Getenv(EnvMaxPoolSize)); value != "" {
+		size, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s invalide %q: entier positif attendu", EnvMaxPoolSize, value)
+		}
+		clientOptions.SetMaxPoolSize(size)
+	}
+
+	if value := strings.TrimSpace(os.Getenv(EnvMinPoolSize)); value != "" {
+		size, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s invalide %q: entier positif attendu", EnvMinPoolSize, value)
+		}
+		clientOptions.SetMinPoolSize(size)
+	}
+
+	// Le driver refuse un minimum supérieur au maximum, autant l'indiquer avec le nom des variables
+	maxPool, minPool := PoolSizes(clientOptions)
+	if maxPool != 0 && minPool > maxPool {
+		return nil, fmt.Errorf("%s (%d) supérieur à %s (%d)", EnvMinPoolSize, minPool, EnvMaxPoolSize, maxPool)
+	}
+
+	return clientOptions, nil
+}
+
+// defaultMaxPoolSize est la taille maximale du pool appliquée par le driver quand rien n'est configuré
+const defaultMaxPoolSize = 100
+
+// PoolSizes retourne les tailles effectives du pool (max, min), défauts du driver compris
+func PoolSizes(clientOptions *options.ClientOptions) (maxPool, minPool uint64) {
+	maxPool = defaultMaxPoolSize
+	if clientOptions.MaxPoolSize != nil {
+		maxPool = *clientOptions.MaxPoolSize
+	}
+	if clientOptions.MinPoolSize != nil {
+		minPool = *clientOptions.MinPoolSize
+	}
+	return maxPool, minPool
+}
+
+// parseWriteConcern accepte "majority" ou un entier positif ou nul
+func parseWriteConcern(value string) (*writeconcern.WriteConcern, error) {
+	if strings.EqualFold(value, "majority") {
+		return writeconcern.New(writeconcern.WMajority()), nil
+	}
+	w, err := strconv.Atoi(value)
+	if err != nil || w < 0 {
+		return nil, fmt.Errorf("%s invalide %q: \"majority\" ou entier positif attendu", EnvWriteConcern, value)
+	}
+	return writeconcern.New(writeconcern.W(w)), nil
+}
@@ -0,0 +1,80 @@
+package mongoconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURI = "mongodb://localhost:27017"
+
+func TestClientOptionsDefaults(t *testing.T) {
+	t.Setenv(EnvWriteConcern, "")
+	t.Setenv(EnvRetryWrites, "")
+
+	clientOptions, err := ClientOptions(testURI)
+	require.NoError(t, err)
+	assert.Nil(t, clientOptions.WriteConcern)
+	assert.Nil(t, clientOptions.RetryWrites)
+}
+
+func TestClientOptionsWriteConcernAndRetryWrites(t *testing.T) {
+	t.Setenv(EnvWriteConcern, "majority")
+	t.Setenv(EnvRetryWrites, "false")
+
+	clientOptions, err := ClientOptions(testURI)
+	require.NoError(t, err)
+	require.NotNil(t, clientOptions.WriteConcern)
+	assert.Equal(t, "majority", clientOptions.WriteConcern.GetW())
+	require.NotNil(t, clientOptions.RetryWrites)
+	assert.False(t, *clientOptions.RetryWrites)
+
+	// Les variables d'environnement priment sur l'URI
+	t.Setenv(EnvWriteConcern, "2")
+	t.Setenv(EnvRetryWrites, "true")
+	clientOptions, err = ClientOptions(testURI + "/?w=1&retryWrites=false")
+	require.NoError(t, err)
+	assert.Equal(t, 2, clientOptions.WriteConcern.GetW())
+	assert.True(t, *clientOptions.RetryWrites)
+}
+
+func TestClientOptionsInvalidValues(t *testing.T) {
+	t.Setenv(EnvRetryWrites, "")
+	for _, value := range []string{"all", "-1"} {
+		t.Setenv(EnvWriteConcern, value)
+		_, err := ClientOptions(testURI)
+		assert.Error(t, err, value)
+	}
+
+	t.Setenv(EnvWriteConcern, "")
+	t.Setenv(EnvRetryWrites, "maybe")
+	_, err := ClientOptions(testURI)
+	assert.Error(t, err)
+}
+
+func TestClientOptionsPoolSizes(t *testing.T) {
+	t.Setenv(EnvMaxPoolSize, "")
+	t.Setenv(EnvMinPoolSize, "")
+	clientOptions, err := ClientOptions(testURI)
+	require.NoError(t, err)
+	maxPool, minPool := PoolSizes(clientOptions)
+	assert.Equal(t, uint64(100), maxPool)
+	assert.Equal(t, uint64(0), minPool)
+
+	t.Setenv(EnvMaxPoolSize, "50")
+	t.Setenv(EnvMinPoolSize, "5")
+	clientOptions, err = ClientOptions(testURI + "/?maxPoolSize=10")
+	require.NoError(t, err)
+	require.NotNil(t, clientOptions.MaxPoolSize)
+	require.NotNil(t, clientOptions.MinPoolSize)
+	assert.Equal(t, uint64(50), *clientOptions.MaxPoolSize)
+	assert.Equal(t, uint64(5), *clientOptions.MinPoolSize)
+
+	for _, sizes := range [][2]string{{"-1", ""}, {"", "abc"}, {"5", "10"}} {
+		t.Setenv(EnvMaxPoolSize, sizes[0])
+		t.Setenv(EnvMinPoolSize, sizes[1])
+		_, err := ClientOptions(testURI)
+		assert.Error(t, err, "%v", sizes)
+	}
+}
@@ -0,0 +1,47 @@
+package mongoconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// EnvURI est la variable portant l'URI de connexion MongoDB
+const EnvURI = "MONGO_URI"
+
+// legacyURIEnvs sont les anciens noms de EnvURI, encore lus si MONGO_URI est absente
+var legacyURIEnvs = []string{"MONGODB_URL", "MONGODB_URI"}
+
+// ErrEmptyURI est retournée quand aucune URI n'est configurée
+var ErrEmptyURI = fmt.Errorf("%s non définie", EnvURI)
+
+// URIFromEnv retourne l'URI configurée et la variable d'où elle vient
+// MONGO_URI prime ; à défaut MONGODB_URL puis MONGODB_URI (anciens noms). Source vide si rien n'est défini
+func URIFromEnv() (uri, source string) {
+	for _, name := range append([]string{EnvURI}, legacyURIEnvs...) {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value, name
+		}
+	}
+	return "", ""
+}
+
+// ParseURI valide l'URI avant toute connexion : schéma mongodb:// ou mongodb+srv://, au moins un hôte,
+// options connues. Les messages d'erreur ne reprennent jamais le mot de passe
+func ParseURI(uri string) (connstring.ConnString, error) {
+	if strings.TrimSpace(uri) == "" {
+		return connstring.ConnString{}, ErrEmptyURI
+	}
+	cs, err := connstring.ParseAndValidate(uri)
+	if err != nil {
+		return connstring.ConnString{}, fmt.Errorf("%s invalide: %w", EnvURI, err)
+	}
+	return cs, nil
+}
+
+// Hosts retourne les hôtes de l'URI, sans identifiants, pour les logs
+func Hosts(cs connstring.ConnString) string {
+	return strings.Join(cs.Hosts, ",")
+}
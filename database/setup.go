@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/maxime-louis14/api-golang/database/mongoconfig"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/tracing"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // DBinstance initialise une connexion MongoDB et retourne un client
@@ -20,20 +22,31 @@ func DBinstance() *mongo.Client {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
 
-	// Récupérer l'URL MongoDB
-	MongoDb := os.Getenv("MONGODB_URL")
-	if MongoDb == "" {
-		// Fallback vers MONGODB_URI si MONGODB_URL n'est pas défini
-		MongoDb = os.Getenv("MONGODB_URI")
-		if MongoDb == "" {
-			log.Fatal("Neither MONGODB_URL nor MONGODB_URI is set in environment variables")
-		}
+	// Récupérer et valider l'URI MongoDB avant toute tentative de connexion
+	MongoDb, source := mongoconfig.URIFromEnv()
+	if source != "" && source != mongoconfig.EnvURI {
+		log.Printf("Warning: %s is deprecated, set %s instead", source, mongoconfig.EnvURI)
 	}
+	connString, err := mongoconfig.ParseURI(MongoDb)
+	if err != nil {
+		log.Fatalf("Invalid MongoDB configuration: %s", logger.RedactURI(err.Error()))
+	}
+	log.Printf("Connecting to MongoDB: hosts=%s", mongoconfig.Hosts(connString))
+
+	// Options du client : URI + write concern, retryWrites et taille du pool (variables MONGO_*)
+	clientOptions, err := mongoconfig.ClientOptions(MongoDb)
+	if err != nil {
+		log.Fatalf("Invalid MongoDB configuration: %s", logger.RedactURI(err.Error()))
+	}
+	// Un span OpenTelemetry par commande MongoDB
+	clientOptions.SetMonitor(tracing.CommandMonitor())
+	maxPool, minPool := mongoconfig.PoolSizes(clientOptions)
+	log.Printf("MongoDB connection pool: maxPoolSize=%d minPoolSize=%d", maxPool, minPool)
 
 	// Créer un nouveau client MongoDB
-	client, err := mongo.NewClient(options.Client().ApplyURI(MongoDb))
+	client, err := mongo.NewClient(clientOptions)
 	if err != nil {
-		log.Fatalf("Failed to create MongoDB client: %v", err)
+		log.Fatalf("Failed to create MongoDB client: %s", logger.RedactURI(err.Error()))
 	}
 
 	// Contexte avec un timeout pour la connexion
@@ -43,16 +56,13 @@ func DBinstance() *mongo.Client {
 	// Connecter le client à MongoDB
 	err = client.Connect(ctx)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		log.Fatalf("Failed to connect to MongoDB: %s", logger.RedactURI(err.Error()))
 	}
 	fmt.Println("Connected to MongoDB!")
 
 	return client
 }
 
-// Client est une instance globale de MongoDB
-var Client *mongo.Client = DBinstance()
-
 // OpenCollection retourne une collection MongoDB
 func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collection {
 	dbName := os.Getenv("DB_NAME") // Récupérer le nom de la base de données
@@ -0,0 +1,42 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ErrDownloadTooLarge signale un fichier dépassant la taille maximale de téléchargement
+var ErrDownloadTooLarge = errors.New("fichier trop volumineux")
+
+// DownloadLimit résout la taille maximale d'un téléchargement en octets (0 = illimité)
+// ?max_bytes est prioritaire sur SCRAPER_MAX_DOWNLOAD_BYTES
+func DownloadLimit(maxBytes string) (int64, error) {
+	source := "max_bytes"
+	if maxBytes == "" {
+		maxBytes = os.Getenv("SCRAPER_MAX_DOWNLOAD_BYTES")
+		source = "SCRAPER_MAX_DOWNLOAD_BYTES"
+	}
+	if maxBytes == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.ParseInt(maxBytes, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("%s invalide %q: entier positif attendu", source, maxBytes)
+	}
+	return limit, nil
+}
+
+// CheckDownloadSize retourne la taille du fichier, ou ErrDownloadTooLarge si elle dépasse limit
+func CheckDownloadSize(path string, limit int64) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if limit > 0 && info.Size() > limit {
+		return info.Size(), fmt.Errorf("%w: %d octets (maximum %d)", ErrDownloadTooLarge, info.Size(), limit)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,62 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile crée un fichier temporaire de size octets
+func writeFile(t *testing.T, size int) string {
+	path := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	return path
+}
+
+// Test d'un fichier sous la limite
+func TestCheckDownloadSizeUnderLimit(t *testing.T) {
+	path := writeFile(t, 100)
+
+	size, err := CheckDownloadSize(path, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), size)
+
+	// 0 = illimité
+	_, err = CheckDownloadSize(path, 0)
+	assert.NoError(t, err)
+}
+
+// Test d'un fichier au-dessus de la limite
+func TestCheckDownloadSizeOverLimit(t *testing.T) {
+	path := writeFile(t, 101)
+
+	size, err := CheckDownloadSize(path, 100)
+	assert.True(t, errors.Is(err, ErrDownloadTooLarge))
+	assert.Equal(t, int64(101), size)
+}
+
+// Test de la résolution de la limite (paramètre puis variable d'environnement)
+func TestDownloadLimit(t *testing.T) {
+	t.Setenv("SCRAPER_MAX_DOWNLOAD_BYTES", "")
+	limit, err := DownloadLimit("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), limit)
+
+	t.Setenv("SCRAPER_MAX_DOWNLOAD_BYTES", "2048")
+	limit, err = DownloadLimit("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), limit)
+
+	limit, err = DownloadLimit("512")
+	require.NoError(t, err)
+	assert.Equal(t, int64(512), limit)
+
+	_, err = DownloadLimit("-1")
+	assert.Error(t, err)
+	_, err = DownloadLimit("abc")
+	assert.Error(t, err)
+}
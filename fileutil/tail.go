@@ -0,0 +1,74 @@
+// Package fileutil regroupe les opérations sur les fichiers servis par l'API : lecture de la fin d'un log,
+// troncature et taille maximale de téléchargement
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Bornes du paramètre ?tail= de GET /scraper/logs
+const (
+	DefaultTailLines = 200
+	MaxTailLines     = 5000
+)
+
+// tailChunkSize est la taille des blocs lus depuis la fin du fichier
+const tailChunkSize = 4096
+
+// ParseTail valide le paramètre ?tail= (vide = DefaultTailLines)
+func ParseTail(value string) (int, error) {
+	if value == "" {
+		return DefaultTailLines, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > MaxTailLines {
+		return 0, fmt.Errorf("tail invalide %q: entier entre 1 et %d attendu", value, MaxTailLines)
+	}
+	return n, nil
+}
+
+// TailLines retourne les n dernières lignes du fichier, dans l'ordre
+// Le fichier est lu par blocs depuis la fin : seule la portion utile est chargée en mémoire
+func TailLines(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := info.Size()
+	var data []byte
+	chunk := make([]byte, tailChunkSize)
+	// n+1 sauts de ligne garantissent n lignes complètes (le dernier peut terminer le fichier)
+	for offset > 0 && bytes.Count(data, []byte("\n")) <= n {
+		size := int64(tailChunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		if _, err := file.ReadAt(chunk[:size], offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		data = append(append([]byte{}, chunk[:size]...), data...)
+	}
+
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return []string{}, nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,82 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLog crée un fichier de log de count lignes numérotées
+func writeLog(t *testing.T, count int) string {
+	var builder strings.Builder
+	for i := 1; i <= count; i++ {
+		fmt.Fprintf(&builder, "ligne %d %s\n", i, strings.Repeat("x", 50))
+	}
+	path := filepath.Join(t.TempDir(), "scraper.log")
+	require.NoError(t, os.WriteFile(path, []byte(builder.String()), 0644))
+	return path
+}
+
+func TestTailLines(t *testing.T) {
+	// Plusieurs blocs de lecture : les lignes à cheval sur deux blocs restent entières
+	path := writeLog(t, 1000)
+
+	lines, err := TailLines(path, 3)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "ligne 998 "))
+	assert.True(t, strings.HasPrefix(lines[2], "ligne 1000 "))
+
+	lines, err = TailLines(path, 200)
+	require.NoError(t, err)
+	require.Len(t, lines, 200)
+	assert.True(t, strings.HasPrefix(lines[0], "ligne 801 "))
+	assert.Len(t, lines[0], len("ligne 801 ")+50)
+}
+
+func TestTailLinesShortFile(t *testing.T) {
+	// Moins de lignes que demandé : tout le fichier
+	path := writeLog(t, 5)
+	lines, err := TailLines(path, 200)
+	require.NoError(t, err)
+	assert.Len(t, lines, 5)
+
+	// Dernière ligne sans retour à la ligne final
+	path = filepath.Join(t.TempDir(), "scraper.log")
+	require.NoError(t, os.WriteFile(path, []byte("a\nb\nc"), 0644))
+	lines, err = TailLines(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, lines)
+
+	// Fichier vide
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	lines, err = TailLines(path, 10)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestTailLinesMissingFile(t *testing.T) {
+	_, err := TailLines(filepath.Join(t.TempDir(), "absent.log"), 10)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestParseTail(t *testing.T) {
+	n, err := ParseTail("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTailLines, n)
+
+	n, err = ParseTail("50")
+	require.NoError(t, err)
+	assert.Equal(t, 50, n)
+
+	for _, value := range []string{"0", "-1", "5001", "abc"} {
+		_, err := ParseTail(value)
+		assert.Error(t, err, value)
+	}
+}
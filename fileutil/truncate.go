@@ -0,0 +1,16 @@
+package fileutil
+
+import "os"
+
+// TruncateFile vide le fichier et retourne le nombre d'octets libérés
+// Un processus qui écrit en mode append (comme le scraper) continue simplement au début du fichier vide
+func TruncateFile(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,28 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateFile(t *testing.T) {
+	path := writeLog(t, 10)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	freed, err := TruncateFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), freed)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+
+	_, err = TruncateFile(filepath.Join(t.TempDir(), "absent.log"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -51,6 +51,15 @@ type MetricsCollector struct {
 	StartTime        time.Time        `json:"start_time"`
 	LastRequestTime  time.Time        `json:"last_request_time"`
 	MemoryStats      runtime.MemStats `json:"memory_stats"`
+
+	// Métriques des exécutions du scraper lancées par l'API
+	ScraperRuns            int64 `json:"scraper_runs"`
+	ScraperSuccesses       int64 `json:"scraper_successes"`
+	ScraperFailures        int64 `json:"scraper_failures"`
+	ScraperTotalDurationNs int64 `json:"scraper_total_duration_ns"`
+
+	// Durées par route normalisée ("GET /recette/:id"), alimentées par middleware.LoggingMiddleware
+	routeLatencies map[string]*routeLatency
 }
 
 var (
@@ -67,6 +76,7 @@ func GetMetricsCollector() *MetricsCollector {
 			StatusCodes:      make(map[int]int64),
 			DatabaseOps:      make(map[string]int64),
 			StartTime:        time.Now(),
+			routeLatencies:   make(map[string]*routeLatency),
 		}
 	})
 	return collector
@@ -141,6 +151,30 @@ func LogInfo(message string, extra map[string]interface{}) {
 	logJSON(entry)
 }
 
+// LogWarn enregistre un avertissement (ex: requête lente), sans compter d'erreur dans les métriques
+func LogWarn(message string, extra map[string]interface{}) {
+	entry := LogEntry{
+		Timestamp: time.Now(),
+		Level:     getLevelString(WARN),
+		Message:   message,
+		Service:   "go-api-mongo-scrapper",
+		Extra:     extra,
+	}
+	logJSON(entry)
+}
+
+// LogDebug enregistre un message de debug (ex: traces HTTP détaillées)
+func LogDebug(message string, extra map[string]interface{}) {
+	entry := LogEntry{
+		Timestamp: time.Now(),
+		Level:     getLevelString(DEBUG),
+		Message:   message,
+		Service:   "go-api-mongo-scrapper",
+		Extra:     extra,
+	}
+	logJSON(entry)
+}
+
 // LogError enregistre une erreur
 func LogError(message string, err error, extra map[string]interface{}) {
 	if extra == nil {
@@ -167,6 +201,35 @@ func LogError(message string, err error, extra map[string]interface{}) {
 	logJSON(entry)
 }
 
+// RecordScraperRun enregistre le résultat d'une exécution du scraper
+func RecordScraperRun(success bool, duration time.Duration) {
+	collector := GetMetricsCollector()
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.ScraperRuns++
+	if success {
+		collector.ScraperSuccesses++
+	} else {
+		collector.ScraperFailures++
+	}
+	collector.ScraperTotalDurationNs += duration.Nanoseconds()
+}
+
+// scraperRunMetrics construit le résumé des exécutions du scraper (mutex déjà acquis)
+func (m *MetricsCollector) scraperRunMetrics() map[string]interface{} {
+	avgDurationMs := float64(0)
+	if m.ScraperRuns > 0 {
+		avgDurationMs = float64(m.ScraperTotalDurationNs) / float64(m.ScraperRuns) / 1e6
+	}
+	return map[string]interface{}{
+		"launched":        m.ScraperRuns,
+		"succeeded":       m.ScraperSuccesses,
+		"failed":          m.ScraperFailures,
+		"avg_duration_ms": avgDurationMs,
+	}
+}
+
 // LogMetrics affiche les métriques actuelles
 func LogMetrics() {
 	collector := GetMetricsCollector()
@@ -178,8 +241,10 @@ func LogMetrics() {
 
 	// Calcul des moyennes
 	avgLatencyMs := float64(0)
+	errorRatePercent := float64(0)
 	if collector.TotalRequests > 0 {
 		avgLatencyMs = float64(collector.TotalLatencyNs) / float64(collector.TotalRequests) / 1e6
+		errorRatePercent = float64(collector.ErrorCount) / float64(collector.TotalRequests) * 100
 	}
 
 	uptime := time.Since(collector.StartTime)
@@ -190,7 +255,7 @@ func LogMetrics() {
 		"total_requests":      collector.TotalRequests,
 		"avg_latency_ms":      fmt.Sprintf("%.2f", avgLatencyMs),
 		"error_count":         collector.ErrorCount,
-		"error_rate_percent":  fmt.Sprintf("%.2f", float64(collector.ErrorCount)/float64(collector.TotalRequests)*100),
+		"error_rate_percent":  fmt.Sprintf("%.2f", errorRatePercent),
 		"requests_by_method":  collector.RequestsByMethod,
 		"requests_by_path":    collector.RequestsByPath,
 		"status_codes":        collector.StatusCodes,
@@ -199,6 +264,7 @@ func LogMetrics() {
 		"memory_sys_mb":       fmt.Sprintf("%.2f", float64(collector.MemoryStats.Sys)/1024/1024),
 		"goroutines":          runtime.NumGoroutine(),
 		"last_request":        collector.LastRequestTime,
+		"scraper_runs":        collector.scraperRunMetrics(),
 	}
 
 	entry := LogEntry{
@@ -218,39 +284,79 @@ func GetMetricsJSON() ([]byte, error) {
 	collector.mu.RLock()
 	defer collector.mu.RUnlock()
 
+	return json.MarshalIndent(collector.snapshot(), "", "  ")
+}
+
+// ResetMetrics remet à zéro les compteurs de requêtes, d'opérations et d'exécutions du scraper
+// Retourne les métriques telles qu'elles étaient juste avant la remise à zéro (même format que GetMetricsJSON)
+// StartTime est conservé : uptime_seconds reste celui du processus
+func ResetMetrics() ([]byte, error) {
+	collector := GetMetricsCollector()
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	before, err := json.MarshalIndent(collector.snapshot(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	collector.TotalRequests = 0
+	collector.TotalLatencyNs = 0
+	collector.RequestsByMethod = make(map[string]int64)
+	collector.RequestsByPath = make(map[string]int64)
+	collector.StatusCodes = make(map[int]int64)
+	collector.DatabaseOps = make(map[string]int64)
+	collector.ErrorCount = 0
+	collector.LastRequestTime = time.Time{}
+	collector.ScraperRuns = 0
+	collector.ScraperSuccesses = 0
+	collector.ScraperFailures = 0
+	collector.ScraperTotalDurationNs = 0
+	collector.routeLatencies = make(map[string]*routeLatency)
+	return before, nil
+}
+
+// snapshot construit les métriques exposées par GetMetricsJSON (mutex déjà acquis)
+func (m *MetricsCollector) snapshot() map[string]interface{} {
 	// Mise à jour des stats mémoire
-	runtime.ReadMemStats(&collector.MemoryStats)
+	runtime.ReadMemStats(&m.MemoryStats)
 
 	// Calcul des moyennes
 	avgLatencyMs := float64(0)
-	if collector.TotalRequests > 0 {
-		avgLatencyMs = float64(collector.TotalLatencyNs) / float64(collector.TotalRequests) / 1e6
+	errorRatePercent := float64(0)
+	if m.TotalRequests > 0 {
+		avgLatencyMs = float64(m.TotalLatencyNs) / float64(m.TotalRequests) / 1e6
+		errorRatePercent = float64(m.ErrorCount) / float64(m.TotalRequests) * 100
 	}
 
-	uptime := time.Since(collector.StartTime)
+	uptime := time.Since(m.StartTime)
 
-	metrics := map[string]interface{}{
+	return map[string]interface{}{
 		"timestamp":           time.Now(),
 		"uptime_seconds":      uptime.Seconds(),
-		"total_requests":      collector.TotalRequests,
+		"total_requests":      m.TotalRequests,
 		"avg_latency_ms":      avgLatencyMs,
-		"error_count":         collector.ErrorCount,
-		"error_rate_percent":  float64(collector.ErrorCount) / float64(collector.TotalRequests) * 100,
-		"requests_by_method":  collector.RequestsByMethod,
-		"requests_by_path":    collector.RequestsByPath,
-		"status_codes":        collector.StatusCodes,
-		"database_operations": collector.DatabaseOps,
-		"memory_alloc_mb":     float64(collector.MemoryStats.Alloc) / 1024 / 1024,
-		"memory_sys_mb":       float64(collector.MemoryStats.Sys) / 1024 / 1024,
+		"error_count":         m.ErrorCount,
+		"error_rate_percent":  errorRatePercent,
+		"requests_by_method":  m.RequestsByMethod,
+		"requests_by_path":    m.RequestsByPath,
+		"status_codes":        m.StatusCodes,
+		"database_operations": m.DatabaseOps,
+		"memory_alloc_mb":     float64(m.MemoryStats.Alloc) / 1024 / 1024,
+		"memory_sys_mb":       float64(m.MemoryStats.Sys) / 1024 / 1024,
 		"goroutines":          runtime.NumGoroutine(),
-		"last_request":        collector.LastRequestTime,
+		"last_request":        m.LastRequestTime,
+		"scraper_runs":        m.scraperRunMetrics(),
+		"route_latencies":     m.routeLatencySummaries(),
 	}
-
-	return json.MarshalIndent(metrics, "", "  ")
 }
 
-// logJSON affiche un log au format JSON
+// logJSON affiche un log au format JSON et le conserve dans le buffer des logs récents
+// Les mots de passe des URIs (MongoDB, proxy...) sont masqués avant toute écriture
 func logJSON(entry LogEntry) {
+	entry = redactEntry(entry)
+	recentLogs.Add(entry)
+
 	jsonData, err := json.Marshal(entry)
 	if err != nil {
 		log.Printf("Erreur lors de la sérialisation du log: %v", err)
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test des compteurs d'exécution du scraper
+func TestRecordScraperRun(t *testing.T) {
+	collector := GetMetricsCollector()
+	collector.mu.RLock()
+	runsBefore := collector.ScraperRuns
+	successesBefore := collector.ScraperSuccesses
+	failuresBefore := collector.ScraperFailures
+	collector.mu.RUnlock()
+
+	RecordScraperRun(true, 2*time.Second)
+	RecordScraperRun(false, 1*time.Second)
+
+	collector.mu.RLock()
+	assert.Equal(t, runsBefore+2, collector.ScraperRuns)
+	assert.Equal(t, successesBefore+1, collector.ScraperSuccesses)
+	assert.Equal(t, failuresBefore+1, collector.ScraperFailures)
+	collector.mu.RUnlock()
+
+	// Les compteurs doivent apparaître dans le JSON des métriques
+	metricsJSON, err := GetMetricsJSON()
+	require.NoError(t, err)
+
+	var metrics map[string]interface{}
+	require.NoError(t, json.Unmarshal(metricsJSON, &metrics))
+
+	scraperRuns, ok := metrics["scraper_runs"].(map[string]interface{})
+	require.True(t, ok, "scraper_runs doit être présent dans les métriques")
+	assert.Equal(t, float64(runsBefore+2), scraperRuns["launched"])
+	assert.Equal(t, float64(successesBefore+1), scraperRuns["succeeded"])
+	assert.Equal(t, float64(failuresBefore+1), scraperRuns["failed"])
+	assert.Greater(t, scraperRuns["avg_duration_ms"], float64(0))
+}
+
+// Test de la remise à zéro : les compteurs repartent de zéro, l'instantané d'avant est retourné
+func TestResetMetrics(t *testing.T) {
+	LogRequest(INFO, "Requête", "req-1", "GET", "/recettes", "test", "127.0.0.1", 200, 10*time.Millisecond)
+	LogRequest(ERROR, "Requête", "req-2", "POST", "/recette", "test", "127.0.0.1", 500, 20*time.Millisecond)
+	RecordScraperRun(true, time.Second)
+
+	beforeJSON, err := ResetMetrics()
+	require.NoError(t, err)
+	var before map[string]interface{}
+	require.NoError(t, json.Unmarshal(beforeJSON, &before))
+	assert.GreaterOrEqual(t, before["total_requests"], float64(2))
+	assert.GreaterOrEqual(t, before["error_count"], float64(1))
+	assert.Contains(t, before["requests_by_path"], "/recettes")
+
+	afterJSON, err := GetMetricsJSON()
+	require.NoError(t, err)
+	var after map[string]interface{}
+	require.NoError(t, json.Unmarshal(afterJSON, &after))
+	assert.Equal(t, float64(0), after["total_requests"])
+	assert.Equal(t, float64(0), after["error_count"])
+	assert.Equal(t, float64(0), after["avg_latency_ms"])
+	assert.Empty(t, after["requests_by_method"])
+	assert.Empty(t, after["requests_by_path"])
+	assert.Empty(t, after["status_codes"])
+	assert.Empty(t, after["database_operations"])
+	scraperRuns := after["scraper_runs"].(map[string]interface{})
+	assert.Equal(t, float64(0), scraperRuns["launched"])
+
+	// L'uptime reste celui du processus
+	assert.GreaterOrEqual(t, after["uptime_seconds"], before["uptime_seconds"])
+
+	// Les requêtes suivantes sont de nouveau comptées
+	LogRequest(INFO, "Requête", "req-3", "GET", "/health", "test", "127.0.0.1", 200, time.Millisecond)
+	afterJSON, err = GetMetricsJSON()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(afterJSON, &after))
+	assert.Equal(t, float64(1), after["total_requests"])
+}
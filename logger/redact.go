@@ -0,0 +1,33 @@
+package logger
+
+import "regexp"
+
+// uriCredentials repère la partie utilisateur:motdepasse@ des URIs (mongodb://, mongodb+srv://, http://...)
+// Le mot de passe s'arrête au dernier @ avant le chemin : un @ non échappé dans le mot de passe est masqué aussi
+var uriCredentials = regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.\-]*://)([^\s/?#@:]*):([^\s/?#]*)@`)
+
+// RedactURI remplace le mot de passe de chaque URI du texte par *** (utilisateur et hôte conservés)
+// Accepte une URI seule ou un message d'erreur qui en contient ; utilisée aussi par le scraper pour ses logs
+func RedactURI(raw string) string {
+	return uriCredentials.ReplaceAllString(raw, "${1}${2}:***@")
+}
+
+// redactEntry masque les identifiants du message et des valeurs texte d'une entrée avant qu'elle soit loggée
+func redactEntry(entry LogEntry) LogEntry {
+	entry.Message = RedactURI(entry.Message)
+	if len(entry.Extra) == 0 {
+		return entry
+	}
+	extra := make(map[string]interface{}, len(entry.Extra))
+	for key, value := range entry.Extra {
+		switch v := value.(type) {
+		case string:
+			value = RedactURI(v)
+		case error:
+			value = RedactURI(v.Error())
+		}
+		extra[key] = value
+	}
+	entry.Extra = extra
+	return entry
+}
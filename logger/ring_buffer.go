@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RecentLogsCapacity est le nombre d'entrées conservées en mémoire pour GET /logs
+const RecentLogsCapacity = 1000
+
+// RingBuffer conserve les dernières entrées de log, les plus anciennes étant écrasées
+// Thread-safe : alimenté par tous les appels de log, lu par la route /logs
+type RingBuffer struct {
+	mu      sync.RWMutex
+	entries []LogEntry
+	next    int  // Position de la prochaine écriture
+	full    bool // La capacité a été atteinte au moins une fois
+}
+
+// NewRingBuffer crée un buffer de capacity entrées
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{entries: make([]LogEntry, capacity)}
+}
+
+// Add ajoute une entrée, en écrasant la plus ancienne si le buffer est plein
+func (b *RingBuffer) Add(entry LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Entries retourne les entrées de niveau supérieur ou égal à minLevel, de la plus ancienne à la plus récente
+func (b *RingBuffer) Entries(minLevel LogLevel) []LogEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	start, count := 0, b.next
+	if b.full {
+		start, count = b.next, len(b.entries)
+	}
+
+	result := make([]LogEntry, 0, count)
+	for i := 0; i < count; i++ {
+		entry := b.entries[(start+i)%len(b.entries)]
+		if level, err := ParseLevel(entry.Level); err == nil && level >= minLevel {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// recentLogs reçoit toutes les entrées émises par logJSON
+var recentLogs = NewRingBuffer(RecentLogsCapacity)
+
+// RecentLogs retourne les dernières entrées de log de niveau supérieur ou égal à level (vide = toutes)
+func RecentLogs(level string) ([]LogEntry, error) {
+	minLevel := DEBUG
+	if level != "" {
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		minLevel = parsed
+	}
+	return recentLogs.Entries(minLevel), nil
+}
+
+// ParseLevel convertit un nom de niveau (debug, info, warn, error), insensible à la casse
+func ParseLevel(level string) (LogLevel, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return DEBUG, nil
+	case "INFO":
+		return INFO, nil
+	case "WARN":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	default:
+		return INFO, fmt.Errorf("niveau de log invalide %q: debug, info, warn ou error attendu", level)
+	}
+}
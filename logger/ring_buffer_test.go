@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// messages extrait les messages des entrées, dans l'ordre
+func messages(entries []LogEntry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Message
+	}
+	return result
+}
+
+func TestRingBufferWraparound(t *testing.T) {
+	buffer := NewRingBuffer(3)
+	assert.Empty(t, buffer.Entries(DEBUG))
+
+	buffer.Add(LogEntry{Level: "INFO", Message: "1"})
+	buffer.Add(LogEntry{Level: "INFO", Message: "2"})
+	assert.Equal(t, []string{"1", "2"}, messages(buffer.Entries(DEBUG)))
+
+	// Au-delà de la capacité, les plus anciennes entrées sont écrasées
+	for i := 3; i <= 7; i++ {
+		buffer.Add(LogEntry{Level: "INFO", Message: fmt.Sprint(i)})
+	}
+	assert.Equal(t, []string{"5", "6", "7"}, messages(buffer.Entries(DEBUG)))
+}
+
+func TestRingBufferLevelFilter(t *testing.T) {
+	buffer := NewRingBuffer(10)
+	buffer.Add(LogEntry{Level: "DEBUG", Message: "debug"})
+	buffer.Add(LogEntry{Level: "INFO", Message: "info"})
+	buffer.Add(LogEntry{Level: "WARN", Message: "warn"})
+	buffer.Add(LogEntry{Level: "ERROR", Message: "error"})
+
+	assert.Equal(t, []string{"error"}, messages(buffer.Entries(ERROR)))
+	assert.Equal(t, []string{"warn", "error"}, messages(buffer.Entries(WARN)))
+	assert.Len(t, buffer.Entries(DEBUG), 4)
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	buffer := NewRingBuffer(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				buffer.Add(LogEntry{Level: "INFO"})
+				buffer.Entries(INFO)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, buffer.Entries(DEBUG), 100)
+}
+
+// Les fonctions de log alimentent le buffer exposé par RecentLogs
+func TestRecentLogs(t *testing.T) {
+	LogError("erreur de test du buffer", nil, nil)
+
+	entries, err := RecentLogs("error")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "erreur de test du buffer", entries[len(entries)-1].Message)
+	for _, entry := range entries {
+		assert.Equal(t, "ERROR", entry.Level)
+	}
+
+	_, err = RecentLogs("verbose")
+	assert.Error(t, err)
+}
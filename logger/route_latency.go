@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// maxRouteLatencySamples borne les durées conservées par route pour les centiles (les plus récentes)
+const maxRouteLatencySamples = 1000
+
+// RouteLatency résume les durées des requêtes d'une route
+type RouteLatency struct {
+	Count int64   `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// routeLatency accumule les durées d'une route : compteur et maximum sur toute la durée de vie,
+// centiles sur les maxRouteLatencySamples dernières requêtes
+type routeLatency struct {
+	count   int64
+	max     time.Duration
+	samples []time.Duration // Anneau des dernières durées
+	next    int
+}
+
+func (r *routeLatency) add(latency time.Duration) {
+	r.count++
+	if latency > r.max {
+		r.max = latency
+	}
+	if len(r.samples) < maxRouteLatencySamples {
+		r.samples = append(r.samples, latency)
+		return
+	}
+	r.samples[r.next] = latency
+	r.next = (r.next + 1) % maxRouteLatencySamples
+}
+
+func (r *routeLatency) summary() RouteLatency {
+	sorted := append([]time.Duration(nil), r.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return RouteLatency{
+		Count: r.count,
+		P50Ms: milliseconds(percentile(sorted, 0.50)),
+		P95Ms: milliseconds(percentile(sorted, 0.95)),
+		MaxMs: milliseconds(r.max),
+	}
+}
+
+// percentile retourne le p-ième centile (0 < p <= 1) d'une liste triée, méthode du rang le plus proche
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// routeLatencyKey est la clé d'une route dans les métriques : méthode et route normalisée ("GET /recette/:id")
+func routeLatencyKey(method, route string) string {
+	return method + " " + route
+}
+
+// RecordRouteLatency enregistre la durée d'une requête sous sa route normalisée (ex: /recette/:id, pas /recette/42)
+// Le nombre de clés reste ainsi borné par le nombre de routes déclarées
+func RecordRouteLatency(method, route string, latency time.Duration) {
+	collector := GetMetricsCollector()
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	key := routeLatencyKey(method, route)
+	stats, ok := collector.routeLatencies[key]
+	if !ok {
+		stats = &routeLatency{}
+		collector.routeLatencies[key] = stats
+	}
+	stats.add(latency)
+}
+
+// RouteLatencies retourne le résumé des durées de chaque route
+func RouteLatencies() map[string]RouteLatency {
+	collector := GetMetricsCollector()
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	return collector.routeLatencySummaries()
+}
+
+// routeLatencySummaries construit le résumé par route (mutex déjà acquis)
+func (m *MetricsCollector) routeLatencySummaries() map[string]RouteLatency {
+	summaries := make(map[string]RouteLatency, len(m.routeLatencies))
+	for key, stats := range m.routeLatencies {
+		summaries[key] = stats.summary()
+	}
+	return summaries
+}
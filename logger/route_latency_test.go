@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du résumé par route : compteur, centiles et maximum
+func TestRecordRouteLatency(t *testing.T) {
+	_, err := ResetMetrics()
+	require.NoError(t, err)
+
+	for i := 1; i <= 100; i++ {
+		RecordRouteLatency("GET", "/recette/:id", time.Duration(i)*time.Millisecond)
+	}
+	RecordRouteLatency("DELETE", "/recette/:id", 5*time.Millisecond)
+
+	latencies := RouteLatencies()
+	require.Len(t, latencies, 2)
+	assert.Equal(t, RouteLatency{Count: 100, P50Ms: 50, P95Ms: 95, MaxMs: 100}, latencies["GET /recette/:id"])
+	assert.Equal(t, int64(1), latencies["DELETE /recette/:id"].Count)
+
+	// Les routes apparaissent dans le JSON des métriques et sont remises à zéro avec elles
+	metricsJSON, err := GetMetricsJSON()
+	require.NoError(t, err)
+	var metrics struct {
+		RouteLatencies map[string]RouteLatency `json:"route_latencies"`
+	}
+	require.NoError(t, json.Unmarshal(metricsJSON, &metrics))
+	assert.Equal(t, latencies, metrics.RouteLatencies)
+
+	_, err = ResetMetrics()
+	require.NoError(t, err)
+	assert.Empty(t, RouteLatencies())
+}
+
+// Les centiles portent sur les dernières requêtes, le maximum sur toutes
+func TestRouteLatencySamplesBounded(t *testing.T) {
+	stats := &routeLatency{}
+	stats.add(time.Second)
+	for i := 0; i < maxRouteLatencySamples; i++ {
+		stats.add(time.Millisecond)
+	}
+	assert.Len(t, stats.samples, maxRouteLatencySamples)
+	assert.Equal(t, RouteLatency{Count: maxRouteLatencySamples + 1, P50Ms: 1, P95Ms: 1, MaxMs: 1000}, stats.summary())
+}
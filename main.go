@@ -11,12 +11,15 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
+	"github.com/maxime-louis14/api-golang/controllers"
 	"github.com/maxime-louis14/api-golang/database"
 	"github.com/maxime-louis14/api-golang/logger"
 	"github.com/maxime-louis14/api-golang/middleware"
+	"github.com/maxime-louis14/api-golang/models"
+	"github.com/maxime-louis14/api-golang/responses"
 	"github.com/maxime-louis14/api-golang/routes"
+	"github.com/maxime-louis14/api-golang/tracing"
 )
 
 // Variables de versioning injectées lors du build
@@ -44,21 +47,53 @@ type HealthResponse struct {
 	Database  string    `json:"database"`
 }
 
+// ReadinessResponse structure pour le readiness check
+type ReadinessResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Database  string    `json:"database"`
+	Scraper   string    `json:"scraper"`
+}
+
 // Route d'exposition des métriques
 func metricsHandler(c *fiber.Ctx) error {
 	metricsJSON, err := logger.GetMetricsJSON()
 	if err != nil {
 		logger.LogError("Erreur lors de la récupération des métriques", err, nil)
-		return c.Status(500).JSON(fiber.Map{
-			"error":   true,
-			"message": "Erreur lors de la récupération des métriques",
-		})
+		return responses.SendError(c, 500, responses.CodeMetricsError, "Erreur lors de la récupération des métriques")
+	}
+
+	c.Set("Content-Type", "application/json")
+	return c.Send(metricsJSON)
+}
+
+// Route de remise à zéro des métriques (environnements de test) : renvoie les métriques d'avant la remise à zéro
+func metricsResetHandler(c *fiber.Ctx) error {
+	metricsJSON, err := logger.ResetMetrics()
+	if err != nil {
+		logger.LogError("Erreur lors de la remise à zéro des métriques", err, nil)
+		return responses.SendError(c, 500, responses.CodeMetricsError, "Erreur lors de la remise à zéro des métriques")
 	}
+	logger.LogInfo("Métriques remises à zéro", map[string]interface{}{
+		"request_id": c.Locals("requestID"),
+	})
 
 	c.Set("Content-Type", "application/json")
 	return c.Send(metricsJSON)
 }
 
+// Route d'exposition des derniers logs de l'API (?level=error pour ne garder que les erreurs)
+func logsHandler(c *fiber.Ctx) error {
+	entries, err := logger.RecentLogs(c.Query("level"))
+	if err != nil {
+		return responses.SendError(c, 400, responses.CodeInvalidParameter, err.Error())
+	}
+	return responses.SendJSON(c, 200, fiber.Map{
+		"count":   len(entries),
+		"entries": entries,
+	})
+}
+
 func main() {
 	// Charger les variables d'environnement depuis le fichier .env
 	err := godotenv.Load(".env")
@@ -84,25 +119,36 @@ func main() {
 		"arch":       runtime.GOARCH,
 	})
 
+	// Traces OpenTelemetry, exportées en OTLP si OTEL_EXPORTER_OTLP_ENDPOINT est défini
+	shutdownTracing, err := tracing.Setup(context.Background(), version)
+	if err != nil {
+		logger.LogError("Initialisation des traces OpenTelemetry échouée", err, nil)
+	} else {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.LogError("Export des derniers spans échoué", err, nil)
+			}
+		}()
+		if tracing.Enabled() {
+			logger.LogInfo("Traces OpenTelemetry activées (export OTLP)", nil)
+		}
+	}
+
 	// Initialisation de l'application Fiber avec configuration
 	app := fiber.New(fiber.Config{
 		AppName:      fmt.Sprintf("Go API MongoDB Scrapper v%s", version),
 		ServerHeader: "Go API MongoDB Scrapper",
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error":   true,
-				"message": err.Error(),
-				"version": version,
-			})
-		},
+		ErrorHandler: responses.ErrorHandler(version),
 	})
 
+	// Seul POST /recettes/import accepte un fichier au-delà de la limite par défaut (enveloppe multipart comprise)
+	middleware.SetRouteBodyLimits(app, map[string]int{"POST /recettes/import": models.MaxImportBytes + 1<<20})
+
 	// Middleware
-	app.Use(recover.New())
+	// Paniques : réponse 500 et pile d'appels loggée avec l'ID de requête
+	app.Use(middleware.Recover())
 	app.Use(fiberlogger.New(fiberlogger.Config{
 		Format: "[${time}] ${status} - ${method} ${path} - ${latency}\n",
 	}))
@@ -111,6 +157,20 @@ func main() {
 	// Middleware de logging personnalisé
 	app.Use(middleware.LoggingMiddleware())
 
+	// Span OpenTelemetry par requête, avec l'ID de requête posé par LoggingMiddleware
+	app.Use(middleware.TracingMiddleware())
+
+	// Traces HTTP détaillées (en-têtes et corps), désactivées par défaut
+	if middleware.DebugHTTPEnabled() {
+		app.Use(middleware.DebugHTTPMiddleware())
+		logger.LogInfo("Mode DEBUG_HTTP activé: les corps des requêtes et réponses sont loggés", nil)
+	}
+
+	// Profilage à chaud (net/http/pprof), désactivé par défaut et protégé par la clé API
+	if middleware.RegisterPprof(app) {
+		logger.LogInfo("Mode ENABLE_PPROF activé: profils disponibles sous "+middleware.PprofPrefix, nil)
+	}
+
 	logger.LogInfo("Application Fiber initialisée avec les middlewares", nil)
 
 	// Connexion à MongoDB
@@ -126,6 +186,14 @@ func main() {
 		logger.LogInfo("Connexion MongoDB fermée", nil)
 	}()
 	logger.LogInfo("Connecté à MongoDB", nil)
+	controllers.SetupCollections(client)
+
+	// Index de la collection recettes (recherche par nom insensible à la casse)
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := controllers.EnsureRecetteIndexes(indexCtx); err != nil {
+		logger.LogError("Création des index MongoDB échouée", err, nil)
+	}
+	cancelIndex()
 
 	// Route de health check
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -141,7 +209,7 @@ func main() {
 			logger.LogDatabase(logger.INFO, "Ping MongoDB réussi", "ping", "mongodb", time.Since(time.Now()), nil)
 		}
 
-		return c.JSON(HealthResponse{
+		return responses.SendJSON(c, 200, HealthResponse{
 			Status:    "ok",
 			Timestamp: time.Now(),
 			Build: BuildInfo{
@@ -156,9 +224,47 @@ func main() {
 		})
 	})
 
+	// Route de readiness : MongoDB joignable et binaire scraper disponible
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		ready := true
+		dbStatus := "connected"
+		if err := client.Ping(ctx, nil); err != nil {
+			ready = false
+			dbStatus = "disconnected"
+			logger.LogError("Ping MongoDB échoué (readiness)", err, nil)
+		}
+
+		scraperPath := controllers.GetScraperPath()
+		scraperStatus := "available"
+		if err := controllers.CheckScraperBinary(scraperPath); err != nil {
+			ready = false
+			scraperStatus = "missing"
+			logger.LogError("Binaire scraper indisponible (readiness)", err, map[string]interface{}{
+				"scraper_path": scraperPath,
+			})
+		}
+
+		status := "ready"
+		code := fiber.StatusOK
+		if !ready {
+			status = "not_ready"
+			code = fiber.StatusServiceUnavailable
+		}
+
+		return responses.SendJSON(c, code, ReadinessResponse{
+			Status:    status,
+			Timestamp: time.Now(),
+			Database:  dbStatus,
+			Scraper:   scraperStatus,
+		})
+	})
+
 	// Route d'informations de version
 	app.Get("/version", func(c *fiber.Ctx) error {
-		return c.JSON(BuildInfo{
+		return responses.SendJSON(c, 200, BuildInfo{
 			Version:   version,
 			GitCommit: gitCommit,
 			BuildTime: buildTime,
@@ -170,11 +276,24 @@ func main() {
 
 	// Route pour les métriques
 	app.Get("/metrics", metricsHandler)
+	app.Post("/metrics/reset", middleware.APIKeyAuth(), metricsResetHandler)
+
+	// Route pour les derniers logs conservés en mémoire (IP, user agents, erreurs : protégée par clé d'API)
+	app.Get("/logs", middleware.APIKeyAuth(), logsHandler)
 
 	// Configuration des routes API
 	routes.RecetteRoute(app)
 	logger.LogInfo("Routes configurées", nil)
 
+	// Planificateur des exécutions du scraper (/scraper/schedule)
+	if scraperScheduler, err := controllers.StartScraperScheduler(); err != nil {
+		logger.LogError("Planificateur du scraper non démarré", err, map[string]interface{}{
+			"schedule_path": controllers.GetScraperSchedulePath(),
+		})
+	} else {
+		defer scraperScheduler.Stop()
+	}
+
 	// Démarrage du logger de métriques périodique (toutes les 30 secondes)
 	logger.StartMetricsLogger(30 * time.Second)
 
@@ -187,6 +306,7 @@ func main() {
 	logger.LogInfo("Serveur démarré", map[string]interface{}{
 		"port":        port,
 		"health_url":  "http://localhost:" + port + "/health",
+		"ready_url":   "http://localhost:" + port + "/readyz",
 		"version_url": "http://localhost:" + port + "/version",
 		"metrics_url": "http://localhost:" + port + "/metrics",
 	})
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/maxime-louis14/api-golang/responses"
+)
+
+// APIKeyHeader est l'en-tête portant la clé API des routes protégées
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth protège une route par la clé API de la variable d'environnement API_KEY
+// Sans API_KEY configurée, la route est refusée : une opération sensible n'est jamais ouverte par défaut
+func APIKeyAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		expected := os.Getenv("API_KEY")
+		if expected == "" {
+			return responses.SendError(c, 403, responses.CodeAuthNotConfigured, "Route désactivée : API_KEY n'est pas configurée")
+		}
+
+		provided := c.Get(APIKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			requestID, _ := c.Locals("requestID").(string)
+			logger.LogInfo("Clé API invalide ou absente", map[string]interface{}{
+				"request_id": requestID,
+				"method":     c.Method(),
+				"path":       c.Path(),
+			})
+			return responses.SendError(c, 401, responses.CodeUnauthorized, "Clé API invalide ou absente (en-tête "+APIKeyHeader+")")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProtectedApp() *fiber.App {
+	app := fiber.New()
+	app.Delete("/protected", APIKeyAuth(), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+// deleteWithKey envoie DELETE /protected et retourne le statut et le code d'erreur éventuel
+func deleteWithKey(t *testing.T, app *fiber.App, key string) (int, string) {
+	req := httptest.NewRequest("DELETE", "/protected", nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body responses.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body.Code
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	t.Setenv("API_KEY", "secret-key")
+	app := newProtectedApp()
+
+	status, _ := deleteWithKey(t, app, "secret-key")
+	assert.Equal(t, 200, status)
+
+	status, code := deleteWithKey(t, app, "wrong-key")
+	assert.Equal(t, 401, status)
+	assert.Equal(t, responses.CodeUnauthorized, code)
+
+	status, code = deleteWithKey(t, app, "")
+	assert.Equal(t, 401, status)
+	assert.Equal(t, responses.CodeUnauthorized, code)
+}
+
+// Sans API_KEY, la route protégée est refusée même sans en-tête
+func TestAPIKeyAuthNotConfigured(t *testing.T) {
+	t.Setenv("API_KEY", "")
+	app := newProtectedApp()
+
+	status, code := deleteWithKey(t, app, "")
+	assert.Equal(t, 403, status)
+	assert.Equal(t, responses.CodeAuthNotConfigured, code)
+}
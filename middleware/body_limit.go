@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// SetRouteBodyLimits relève la taille maximale du corps pour certaines routes, clés "MÉTHODE /chemin"
+// (ex: "POST /recettes/import") ; les autres routes gardent le BodyLimit de l'application
+// fasthttp lit le corps avant le routage : la limite est choisie dès la réception des en-têtes
+func SetRouteBodyLimits(app *fiber.App, limits map[string]int) {
+	app.Server().HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		path := string(header.RequestURI())
+		if i := strings.IndexByte(path, '?'); i >= 0 {
+			path = path[:i]
+		}
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		// Une valeur nulle laisse fasthttp appliquer BodyLimit
+		return fasthttp.RequestConfig{MaxRequestBodySize: limits[string(header.Method())+" "+path]}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// Test que seule la route configurée accepte un corps au-delà du BodyLimit de l'application
+func TestSetRouteBodyLimits(t *testing.T) {
+	app := fiber.New(fiber.Config{BodyLimit: 1024})
+	SetRouteBodyLimits(app, map[string]int{"POST /import": 4096})
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	app.Post("/import", ok)
+	app.Post("/other", ok)
+	app.Put("/import", ok)
+
+	// app.Test remonte l'erreur de lecture du corps que le serveur convertit en 413
+	status := func(method, path string, size int) int {
+		req := httptest.NewRequest(method, path, bytes.NewReader(make([]byte, size)))
+		resp, err := app.Test(req)
+		if errors.Is(err, fasthttp.ErrBodyTooLarge) {
+			return fiber.StatusRequestEntityTooLarge
+		}
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, status("POST", "/import", 2048))
+	assert.Equal(t, 200, status("POST", "/import/?dry_run=true", 2048))
+	assert.Equal(t, 413, status("POST", "/import", 8192))
+	assert.Equal(t, 413, status("POST", "/other", 2048))
+	assert.Equal(t, 413, status("PUT", "/import", 2048))
+	assert.Equal(t, 200, status("POST", "/other", 512))
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+)
+
+// maxDebugBodyBytes limite la taille des corps affichés dans les logs de debug
+const maxDebugBodyBytes = 2048
+
+// redactedHeaders liste les en-têtes dont la valeur n'est jamais loggée
+var redactedHeaders = map[string]bool{
+	"x-api-key":     true,
+	"authorization": true,
+}
+
+// DebugHTTPEnabled indique si le mode debug HTTP est activé (DEBUG_HTTP=true)
+func DebugHTTPEnabled() bool {
+	return strings.EqualFold(os.Getenv("DEBUG_HTTP"), "true")
+}
+
+// DebugHTTPMiddleware logge en-têtes et corps (tronqués) des requêtes et réponses
+// Les corps du flux SSE et des téléchargements de fichiers ne sont pas loggés
+func DebugHTTPMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestID").(string)
+
+		logger.LogDebug("Requête HTTP", map[string]interface{}{
+			"request_id": requestID,
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"headers":    redactHeaders(c.GetReqHeaders()),
+			"body":       truncateBody(c.Body()),
+		})
+
+		err := c.Next()
+
+		responseLog := map[string]interface{}{
+			"request_id":  requestID,
+			"method":      c.Method(),
+			"path":        c.Path(),
+			"status_code": c.Response().StatusCode(),
+			"headers":     redactHeaders(c.GetRespHeaders()),
+		}
+		if skipResponseBody(c) {
+			responseLog["body"] = "[non loggé: flux ou téléchargement]"
+		} else {
+			responseLog["body"] = truncateBody(c.Response().Body())
+		}
+		logger.LogDebug("Réponse HTTP", responseLog)
+
+		return err
+	}
+}
+
+// redactHeaders masque les en-têtes sensibles
+func redactHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if redactedHeaders[strings.ToLower(name)] {
+			value = "[REDACTED]"
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// truncateBody tronque un corps à maxDebugBodyBytes
+func truncateBody(body []byte) string {
+	if len(body) > maxDebugBodyBytes {
+		return string(body[:maxDebugBodyBytes]) + "...[tronqué]"
+	}
+	return string(body)
+}
+
+// skipResponseBody détecte les réponses SSE et les téléchargements de fichiers
+func skipResponseBody(c *fiber.Ctx) bool {
+	contentType := string(c.Response().Header.ContentType())
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return true
+	}
+	return len(c.Response().Header.Peek(fiber.HeaderContentDisposition)) > 0
+}
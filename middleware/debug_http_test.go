@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs redirige la sortie du logger standard pendant le test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func newDebugApp() *fiber.App {
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Use(DebugHTTPMiddleware())
+	app.Post("/echo", func(c *fiber.Ctx) error {
+		return c.Status(201).SendString("réponse " + string(c.Body()))
+	})
+	app.Get("/download", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentDisposition, "attachment; filename=data.json")
+		return c.SendString("contenu-du-fichier")
+	})
+	return app
+}
+
+// Test que les corps sont loggés et que X-API-Key est masqué
+func TestDebugHTTPLogsBodiesAndRedacts(t *testing.T) {
+	logs := captureLogs(t)
+	app := newDebugApp()
+
+	req := httptest.NewRequest("POST", "/echo", strings.NewReader("corps-de-requete"))
+	req.Header.Set("X-API-Key", "secret-key")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "réponse corps-de-requete", string(body))
+
+	output := logs.String()
+	assert.Contains(t, output, `"body":"corps-de-requete"`)
+	assert.Contains(t, output, `"body":"réponse corps-de-requete"`)
+	assert.Contains(t, output, "[REDACTED]")
+	assert.NotContains(t, output, "secret-key")
+}
+
+// Test que les téléchargements ne loggent pas leur contenu
+func TestDebugHTTPSkipsDownloads(t *testing.T) {
+	logs := captureLogs(t)
+	app := newDebugApp()
+
+	_, err := app.Test(httptest.NewRequest("GET", "/download", nil))
+	require.NoError(t, err)
+	assert.NotContains(t, logs.String(), "contenu-du-fichier")
+}
+
+// Test de la troncature des corps volumineux
+func TestTruncateBody(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), maxDebugBodyBytes+10)
+	truncated := truncateBody(body)
+	assert.True(t, strings.HasSuffix(truncated, "...[tronqué]"))
+	assert.Len(t, truncated, maxDebugBodyBytes+len("...[tronqué]"))
+}
+
+// Test de l'activation par variable d'environnement (désactivé par défaut)
+func TestDebugHTTPEnabled(t *testing.T) {
+	t.Setenv("DEBUG_HTTP", "")
+	assert.False(t, DebugHTTPEnabled())
+	t.Setenv("DEBUG_HTTP", "true")
+	assert.True(t, DebugHTTPEnabled())
+}
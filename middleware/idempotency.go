@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+)
+
+// IdempotencyKeyHeader est l'en-tête identifiant une requête rejouable sans effet de bord
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader signale une réponse servie depuis le cache d'idempotence
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// DefaultIdempotencyTTL est la durée de conservation d'une réponse associée à une clé
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotentResponse est une réponse mémorisée pour une clé
+// done est fermé quand la première requête est terminée : les doublons concurrents l'attendent
+type idempotentResponse struct {
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// idempotencyStore associe les clés aux réponses, en mémoire, avec expiration
+type idempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time // Horloge (remplaçable dans les tests)
+	entries map[string]*idempotentResponse
+}
+
+func newIdempotencyStore(ttl time.Duration, now func() time.Time) *idempotencyStore {
+	return &idempotencyStore{ttl: ttl, now: now, entries: make(map[string]*idempotentResponse)}
+}
+
+// acquire retourne l'entrée de la clé ; owner indique que l'appelant doit exécuter la requête
+func (s *idempotencyStore) acquire(key string) (entry *idempotentResponse, owner bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, e := range s.entries {
+		if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+
+	if entry, ok := s.entries[key]; ok {
+		return entry, false
+	}
+	entry = &idempotentResponse{done: make(chan struct{})}
+	s.entries[key] = entry
+	return entry, true
+}
+
+// complete mémorise la réponse de la clé jusqu'à expiration et libère les doublons en attente
+func (s *idempotencyStore) complete(entry *idempotentResponse, status int, contentType string, body []byte) {
+	s.mu.Lock()
+	entry.status = status
+	entry.contentType = contentType
+	entry.body = body
+	entry.expiresAt = s.now().Add(s.ttl)
+	s.mu.Unlock()
+	close(entry.done)
+}
+
+// abandon oublie la clé (la requête a échoué sans réponse) ; un doublon pourra la rejouer
+func (s *idempotencyStore) abandon(key string, entry *idempotentResponse) {
+	s.mu.Lock()
+	if s.entries[key] == entry {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	close(entry.done)
+}
+
+// Idempotency rejoue la réponse d'une requête déjà reçue avec le même Idempotency-Key pendant ttl
+// Sans en-tête, la requête est exécutée normalement ; un doublon reçu pendant l'exécution attend son résultat
+// Les réponses 409, 429 et 5xx ne sont pas rejouées
+func Idempotency(ttl time.Duration) fiber.Handler {
+	return idempotent(newIdempotencyStore(ttl, time.Now))
+}
+
+func idempotent(store *idempotencyStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		for {
+			entry, owner := store.acquire(key)
+			if owner {
+				return runIdempotent(c, store, key, entry)
+			}
+
+			<-entry.done
+			store.mu.Lock()
+			completed := !entry.expiresAt.IsZero()
+			store.mu.Unlock()
+			if !completed {
+				continue // Première requête abandonnée : la clé est libre
+			}
+
+			requestID, _ := c.Locals("requestID").(string)
+			logger.LogInfo("Requête rejouée depuis le cache d'idempotence", map[string]interface{}{
+				"request_id":      requestID,
+				"idempotency_key": key,
+				"path":            c.Path(),
+			})
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, entry.contentType)
+			return c.Status(entry.status).Send(entry.body)
+		}
+	}
+}
+
+// runIdempotent exécute la requête propriétaire de la clé et mémorise sa réponse
+// Une panique du handler abandonne la clé avant d'être relancée vers Recover : sans cela,
+// les doublons attendraient indéfiniment une réponse qui ne viendra jamais
+func runIdempotent(c *fiber.Ctx, store *idempotencyStore, key string, entry *idempotentResponse) error {
+	defer func() {
+		if r := recover(); r != nil {
+			store.abandon(key, entry)
+			panic(r)
+		}
+	}()
+
+	if err := c.Next(); err != nil {
+		store.abandon(key, entry)
+		return err
+	}
+	// Un refus temporaire (409 exécution en cours, 429 Retry-After) ou un échec serveur (5xx) n'est pas
+	// mémorisé : la même clé pourra relancer plus tard
+	if !cacheableStatus(c.Response().StatusCode()) {
+		store.abandon(key, entry)
+		return nil
+	}
+	body := append([]byte(nil), c.Response().Body()...)
+	store.complete(entry, c.Response().StatusCode(), string(c.Response().Header.ContentType()), body)
+	return nil
+}
+
+// cacheableStatus indique si une réponse est mémorisée pour sa clé
+func cacheableStatus(status int) bool {
+	return status != fiber.StatusConflict && status != fiber.StatusTooManyRequests && status < fiber.StatusInternalServerError
+}
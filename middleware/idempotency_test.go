@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock est une horloge avancée manuellement
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// newIdempotentApp monte une route comptant ses exécutions derrière le middleware d'idempotence
+func newIdempotentApp(store *idempotencyStore, runs *int64, delay time.Duration) *fiber.App {
+	app := fiber.New()
+	app.Post("/run", idempotent(store), func(c *fiber.Ctx) error {
+		n := atomic.AddInt64(runs, 1)
+		time.Sleep(delay)
+		return c.Status(202).SendString(fmt.Sprintf("run %d", n))
+	})
+	return app
+}
+
+// postWithKey envoie POST /run et retourne le statut, le corps et l'en-tête de rejeu
+func postWithKey(t *testing.T, app *fiber.App, key string) (int, string, string) {
+	req := httptest.NewRequest("POST", "/run", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body), resp.Header.Get(IdempotentReplayedHeader)
+}
+
+func TestIdempotencyWithinTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var runs int64
+	app := newIdempotentApp(newIdempotencyStore(time.Hour, clock.Now), &runs, 0)
+
+	status, body, replayed := postWithKey(t, app, "key-1")
+	assert.Equal(t, 202, status)
+	assert.Equal(t, "run 1", body)
+	assert.Empty(t, replayed)
+
+	// Même clé : même réponse, sans nouvelle exécution
+	clock.Advance(59 * time.Minute)
+	status, body, replayed = postWithKey(t, app, "key-1")
+	assert.Equal(t, 202, status)
+	assert.Equal(t, "run 1", body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&runs))
+
+	// Autre clé ou absence de clé : nouvelle exécution
+	_, body, _ = postWithKey(t, app, "key-2")
+	assert.Equal(t, "run 2", body)
+	_, body, _ = postWithKey(t, app, "")
+	assert.Equal(t, "run 3", body)
+}
+
+func TestIdempotencyAfterTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var runs int64
+	app := newIdempotentApp(newIdempotencyStore(time.Hour, clock.Now), &runs, 0)
+
+	_, body, _ := postWithKey(t, app, "key-1")
+	assert.Equal(t, "run 1", body)
+
+	// Après expiration, la clé relance une exécution
+	clock.Advance(time.Hour + time.Second)
+	_, body, replayed := postWithKey(t, app, "key-1")
+	assert.Equal(t, "run 2", body)
+	assert.Empty(t, replayed)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&runs))
+}
+
+// Un refus 429 (intervalle minimal entre exécutions) n'est pas mémorisé : la même clé relance ensuite
+func TestIdempotencyTooManyRequestsNotReplayed(t *testing.T) {
+	var runs int64
+	app := fiber.New()
+	app.Post("/run", idempotent(newIdempotencyStore(time.Hour, time.Now)), func(c *fiber.Ctx) error {
+		if atomic.AddInt64(&runs, 1) == 1 {
+			c.Set(fiber.HeaderRetryAfter, "60")
+			return c.Status(fiber.StatusTooManyRequests).SendString("trop tôt")
+		}
+		return c.Status(202).SendString("lancé")
+	})
+
+	status, body, _ := postWithKey(t, app, "key-1")
+	assert.Equal(t, fiber.StatusTooManyRequests, status)
+	assert.Equal(t, "trop tôt", body)
+
+	status, body, replayed := postWithKey(t, app, "key-1")
+	assert.Equal(t, 202, status)
+	assert.Equal(t, "lancé", body)
+	assert.Empty(t, replayed)
+
+	// La réponse acceptée est, elle, rejouée
+	_, body, replayed = postWithKey(t, app, "key-1")
+	assert.Equal(t, "lancé", body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&runs))
+}
+
+// Un refus 409 ou un échec 5xx n'est pas mémorisé, une erreur 4xx l'est
+func TestIdempotencyCacheableStatus(t *testing.T) {
+	for _, status := range []int{fiber.StatusConflict, fiber.StatusInternalServerError, fiber.StatusServiceUnavailable} {
+		var runs int64
+		app := fiber.New()
+		app.Post("/run", idempotent(newIdempotencyStore(time.Hour, time.Now)), func(c *fiber.Ctx) error {
+			if atomic.AddInt64(&runs, 1) == 1 {
+				return c.Status(status).SendString("refusé")
+			}
+			return c.Status(202).SendString("lancé")
+		})
+
+		postWithKey(t, app, "key-1")
+		got, body, replayed := postWithKey(t, app, "key-1")
+		assert.Equal(t, 202, got, status)
+		assert.Equal(t, "lancé", body, status)
+		assert.Empty(t, replayed, status)
+	}
+
+	var runs int64
+	app := fiber.New()
+	app.Post("/run", idempotent(newIdempotencyStore(time.Hour, time.Now)), func(c *fiber.Ctx) error {
+		atomic.AddInt64(&runs, 1)
+		return c.Status(fiber.StatusBadRequest).SendString("invalide")
+	})
+	postWithKey(t, app, "key-1")
+	status, _, replayed := postWithKey(t, app, "key-1")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&runs))
+}
+
+// Une panique du handler libère la clé : la requête suivante avec la même clé est exécutée, sans attente
+func TestIdempotencyPanicAbandonsKey(t *testing.T) {
+	var runs int64
+	app := fiber.New()
+	app.Use(Recover())
+	app.Post("/run", idempotent(newIdempotencyStore(time.Hour, time.Now)), func(c *fiber.Ctx) error {
+		if atomic.AddInt64(&runs, 1) == 1 {
+			panic("handler en échec")
+		}
+		return c.Status(202).SendString("lancé")
+	})
+
+	status, _, _ := postWithKey(t, app, "key-1")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		status, body, replayed := postWithKey(t, app, "key-1")
+		assert.Equal(t, 202, status)
+		assert.Equal(t, "lancé", body)
+		assert.Empty(t, replayed)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("la requête suivante attend la clé abandonnée")
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&runs))
+}
+
+func TestIdempotencyConcurrentDuplicates(t *testing.T) {
+	var runs int64
+	app := newIdempotentApp(newIdempotencyStore(time.Hour, time.Now), &runs, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, bodies[i], _ = postWithKey(t, app, "same-key")
+		}(i)
+	}
+	wg.Wait()
+
+	// Les doublons reçus pendant l'exécution attendent son résultat
+	assert.Equal(t, int64(1), atomic.LoadInt64(&runs))
+	for _, body := range bodies {
+		assert.Equal(t, "run 1", body)
+	}
+}
@@ -3,6 +3,9 @@ package middleware
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,14 +19,86 @@ func generateRequestID() string {
 	return hex.EncodeToString(bytes)
 }
 
+// DefaultRequestIDHeader est l'en-tête portant l'ID de requête si REQUEST_ID_HEADER n'est pas défini
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength borne la longueur d'un ID de requête reçu d'un client ou d'un proxy
+const maxRequestIDLength = 128
+
+// RequestIDHeader retourne l'en-tête de l'ID de requête (REQUEST_ID_HEADER, ex: X-Correlation-ID)
+func RequestIDHeader() string {
+	if header := strings.TrimSpace(os.Getenv("REQUEST_ID_HEADER")); header != "" {
+		return header
+	}
+	return DefaultRequestIDHeader
+}
+
+// DefaultSlowRequestThreshold est la durée au-delà de laquelle une requête est signalée si SLOW_REQUEST_MS n'est pas défini
+const DefaultSlowRequestThreshold = time.Second
+
+// SlowRequestThreshold retourne la durée au-delà de laquelle une requête est loggée en WARN (SLOW_REQUEST_MS)
+// 0 désactive l'avertissement ; une valeur invalide garde le défaut
+func SlowRequestThreshold() time.Duration {
+	value := strings.TrimSpace(os.Getenv("SLOW_REQUEST_MS"))
+	if value == "" {
+		return DefaultSlowRequestThreshold
+	}
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		logger.LogWarn("SLOW_REQUEST_MS invalide, seuil par défaut conservé", map[string]interface{}{
+			"value":        value,
+			"threshold_ms": DefaultSlowRequestThreshold.Milliseconds(),
+		})
+		return DefaultSlowRequestThreshold
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// validRequestID accepte un ID reçu s'il est court et en ASCII imprimable sans espace,
+// pour qu'il ne puisse pas polluer les logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// unmatchedRoute regroupe les requêtes sans route (404) dans les métriques par route
+const unmatchedRoute = "(non routée)"
+
+// routeKey retourne la route Fiber ayant traité la requête (/recette/:id) plutôt que le chemin reçu,
+// pour que les métriques par route gardent un nombre de clés borné
+// Sans route correspondante, Fiber n'a exécuté que les middlewares globaux (route "/")
+func routeKey(c *fiber.Ctx) string {
+	route := c.Route()
+	if route == nil || (route.Path == "/" && c.Path() != "/") {
+		return unmatchedRoute
+	}
+	return route.Path
+}
+
 // LoggingMiddleware middleware de logging détaillé
+// L'ID de requête est repris de l'en-tête RequestIDHeader s'il est présent (proxy, service appelant),
+// généré sinon, et renvoyé dans le même en-tête de réponse
+// Une requête plus longue que SlowRequestThreshold est en plus signalée par un log WARN
 func LoggingMiddleware() fiber.Handler {
+	header := RequestIDHeader()
+	slowThreshold := SlowRequestThreshold()
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
-		requestID := generateRequestID()
+		requestID := c.Get(header)
+		if !validRequestID(requestID) {
+			requestID = generateRequestID()
+		}
 
-		// Ajouter l'ID de requête au contexte
+		// Ajouter l'ID de requête au contexte et à la réponse
 		c.Locals("requestID", requestID)
+		c.Set(header, requestID)
 
 		// Log de début de requête
 		logger.LogRequest(
@@ -41,8 +116,9 @@ func LoggingMiddleware() fiber.Handler {
 		// Exécuter la requête
 		err := c.Next()
 
-		// Calculer la latence totale
+		// Calculer la latence totale, comptée aussi sous la route normalisée
 		latency := time.Since(start)
+		logger.RecordRouteLatency(c.Method(), routeKey(c), latency)
 
 		// Log de fin de requête
 		logger.LogRequest(
@@ -57,6 +133,19 @@ func LoggingMiddleware() fiber.Handler {
 			latency,
 		)
 
+		if slowThreshold > 0 && latency > slowThreshold {
+			logger.LogWarn("Requête lente", map[string]interface{}{
+				"request_id":   requestID,
+				"method":       c.Method(),
+				"path":         c.Path(),
+				"route":        routeKey(c),
+				"status_code":  c.Response().StatusCode(),
+				"duration":     latency.String(),
+				"duration_ms":  latency.Milliseconds(),
+				"threshold_ms": slowThreshold.Milliseconds(),
+			})
+		}
+
 		return err
 	}
 }
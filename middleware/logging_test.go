@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getRequestID appelle une route renvoyant l'ID de requête vu par le handler
+// Retourne cet ID et les en-têtes de la réponse
+func getRequestID(t *testing.T, headers map[string]string) (string, http.Header) {
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Get("/id", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("requestID").(string))
+	})
+
+	req := httptest.NewRequest("GET", "/id", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp.Header
+}
+
+// Test que l'ID reçu dans X-Request-ID est repris et renvoyé
+func TestRequestIDDefaultHeader(t *testing.T) {
+	id, headers := getRequestID(t, map[string]string{"X-Request-ID": "abc-123"})
+	assert.Equal(t, "abc-123", id)
+	assert.Equal(t, "abc-123", headers.Get("X-Request-ID"))
+
+	// Sans en-tête, un ID est généré et renvoyé
+	id, headers = getRequestID(t, nil)
+	assert.Len(t, id, 16)
+	assert.Equal(t, id, headers.Get("X-Request-ID"))
+}
+
+// Test que REQUEST_ID_HEADER est respecté en lecture et en écriture
+func TestRequestIDCustomHeader(t *testing.T) {
+	t.Setenv("REQUEST_ID_HEADER", "X-Correlation-ID")
+
+	id, headers := getRequestID(t, map[string]string{
+		"X-Correlation-ID": "corr-42",
+		"X-Request-ID":     "ignored",
+	})
+	assert.Equal(t, "corr-42", id)
+	assert.Equal(t, "corr-42", headers.Get("X-Correlation-ID"))
+	assert.Empty(t, headers.Get("X-Request-ID"))
+}
+
+// Test qu'un ID reçu invalide est remplacé par un ID généré
+func TestRequestIDRejectsInvalid(t *testing.T) {
+	for _, invalid := range []string{"avec espace", strings.Repeat("a", maxRequestIDLength+1)} {
+		id, headers := getRequestID(t, map[string]string{"X-Request-ID": invalid})
+		assert.NotEqual(t, invalid, id)
+		assert.Len(t, id, 16)
+		assert.Equal(t, id, headers.Get("X-Request-ID"))
+	}
+}
+
+// Test que les durées sont regroupées sous la route normalisée, pas sous le chemin reçu
+func TestLoggingMiddlewareRouteLatency(t *testing.T) {
+	_, err := logger.ResetMetrics()
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Get("/recette/:id", func(c *fiber.Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+
+	for _, path := range []string{"/recette/1", "/recette/2", "/recette/abc", "/inconnue/1", "/inconnue/2"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	latencies := logger.RouteLatencies()
+	assert.Len(t, latencies, 2)
+	assert.Equal(t, int64(3), latencies["GET /recette/:id"].Count)
+	assert.Equal(t, int64(2), latencies["GET "+unmatchedRoute].Count)
+}
+
+// slowRequestWarnings retourne les logs « Requête lente » émis pour un ID de requête
+func slowRequestWarnings(t *testing.T, requestID string) []logger.LogEntry {
+	entries, err := logger.RecentLogs("warn")
+	require.NoError(t, err)
+	var warnings []logger.LogEntry
+	for _, entry := range entries {
+		if entry.Message == "Requête lente" && entry.Extra["request_id"] == requestID {
+			warnings = append(warnings, entry)
+		}
+	}
+	return warnings
+}
+
+// Test qu'une requête plus longue que SLOW_REQUEST_MS est signalée en WARN, et une requête rapide non
+func TestLoggingMiddlewareSlowRequest(t *testing.T) {
+	t.Setenv("SLOW_REQUEST_MS", "20")
+
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Get("/lente/:id", func(c *fiber.Ctx) error {
+		time.Sleep(50 * time.Millisecond)
+		return c.SendString("ok")
+	})
+	app.Get("/rapide", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for _, path := range []string{"/lente/1", "/rapide"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Request-ID", "slow-test"+strings.ReplaceAll(path, "/", "-"))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	warnings := slowRequestWarnings(t, "slow-test-lente-1")
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "WARN", warnings[0].Level)
+	assert.Equal(t, "GET", warnings[0].Extra["method"])
+	assert.Equal(t, "/lente/1", warnings[0].Extra["path"])
+	assert.Equal(t, "/lente/:id", warnings[0].Extra["route"])
+	assert.GreaterOrEqual(t, warnings[0].Extra["duration_ms"], int64(50))
+	assert.Equal(t, int64(20), warnings[0].Extra["threshold_ms"])
+
+	assert.Empty(t, slowRequestWarnings(t, "slow-test-rapide"))
+}
+
+func TestSlowRequestThreshold(t *testing.T) {
+	t.Setenv("SLOW_REQUEST_MS", "")
+	assert.Equal(t, DefaultSlowRequestThreshold, SlowRequestThreshold())
+
+	t.Setenv("SLOW_REQUEST_MS", "250")
+	assert.Equal(t, 250*time.Millisecond, SlowRequestThreshold())
+
+	t.Setenv("SLOW_REQUEST_MS", "0")
+	assert.Equal(t, time.Duration(0), SlowRequestThreshold())
+
+	t.Setenv("SLOW_REQUEST_MS", "lent")
+	assert.Equal(t, DefaultSlowRequestThreshold, SlowRequestThreshold())
+}
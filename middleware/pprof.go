@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+)
+
+// PprofPrefix est le préfixe des endpoints de profilage (net/http/pprof)
+const PprofPrefix = "/debug/pprof"
+
+// PprofEnabled indique si les endpoints de profilage sont activés (ENABLE_PPROF=true)
+func PprofEnabled() bool {
+	return strings.EqualFold(os.Getenv("ENABLE_PPROF"), "true")
+}
+
+// RegisterPprof monte les profils CPU, mémoire, goroutines... sous /debug/pprof si ENABLE_PPROF=true
+// Les endpoints sont protégés par la clé API ; retourne false (rien n'est monté) si le profilage est désactivé
+func RegisterPprof(app *fiber.App) bool {
+	if !PprofEnabled() {
+		return false
+	}
+	app.Use(PprofPrefix, APIKeyAuth())
+	app.Use(pprof.New())
+	return true
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPprofApp() *fiber.App {
+	app := fiber.New()
+	RegisterPprof(app)
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	return app
+}
+
+func pprofStatus(t *testing.T, app *fiber.App, path, key string) int {
+	req := httptest.NewRequest("GET", path, nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+// Test des endpoints de profilage activés : clé API requise
+func TestPprofEnabled(t *testing.T) {
+	t.Setenv("ENABLE_PPROF", "true")
+	t.Setenv("API_KEY", "secret")
+	app := newPprofApp()
+
+	assert.Equal(t, 200, pprofStatus(t, app, "/debug/pprof/", "secret"))
+	assert.Equal(t, 200, pprofStatus(t, app, "/debug/pprof/goroutine?debug=1", "secret"))
+	assert.Equal(t, 401, pprofStatus(t, app, "/debug/pprof/heap", ""))
+	assert.Equal(t, 200, pprofStatus(t, app, "/health", ""))
+}
+
+// Test des endpoints de profilage désactivés par défaut
+func TestPprofDisabled(t *testing.T) {
+	t.Setenv("ENABLE_PPROF", "")
+	t.Setenv("API_KEY", "secret")
+	app := newPprofApp()
+
+	assert.Equal(t, 404, pprofStatus(t, app, "/debug/pprof/", "secret"))
+	assert.Equal(t, 404, pprofStatus(t, app, "/debug/pprof/heap", "secret"))
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/maxime-louis14/api-golang/logger"
+)
+
+// Recover transforme une panique d'un handler en erreur 500 et la logge avec sa pile d'appels
+// À enregistrer avant LoggingMiddleware : l'ID de requête est alors déjà connu quand la panique remonte
+func Recover() fiber.Handler {
+	return recover.New(recover.Config{
+		EnableStackTrace:  true,
+		StackTraceHandler: logPanic,
+	})
+}
+
+// logPanic logge la valeur récupérée et la pile de la goroutine qui a paniqué
+func logPanic(c *fiber.Ctx, recovered interface{}) {
+	requestID, _ := c.Locals("requestID").(string)
+	logger.LogError("Panique récupérée", fmt.Errorf("%v", recovered), map[string]interface{}{
+		"request_id": requestID,
+		"method":     c.Method(),
+		"path":       c.Path(),
+		"stack":      string(debug.Stack()),
+	})
+}
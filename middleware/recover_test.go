@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingHandler panique toujours, pour vérifier que la pile loggée la contient
+func panickingHandler(c *fiber.Ctx) error {
+	panic("handler en panique")
+}
+
+// Test qu'une panique renvoie 500 et que le logger reçoit la pile et l'ID de requête
+func TestRecoverLogsStack(t *testing.T) {
+	captureLogs(t)
+	app := fiber.New()
+	app.Use(Recover())
+	app.Use(LoggingMiddleware())
+	app.Get("/panic", panickingHandler)
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set("X-Request-ID", "panic-req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	entries, err := logger.RecentLogs("error")
+	require.NoError(t, err)
+	var found *logger.LogEntry
+	for i := range entries {
+		if entries[i].Message == "Panique récupérée" && entries[i].Extra["request_id"] == "panic-req-1" {
+			found = &entries[i]
+		}
+	}
+	require.NotNil(t, found, "panique non loggée")
+	assert.Equal(t, "handler en panique", found.Extra["error"])
+	assert.Equal(t, "/panic", found.Extra["path"])
+	assert.Contains(t, found.Extra["stack"], "panickingHandler")
+}
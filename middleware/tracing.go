@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier expose les en-têtes de la requête Fiber au propagateur OpenTelemetry (traceparent entrant)
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// TracingMiddleware ouvre un span serveur par requête, enfant du traceparent reçu s'il y en a un
+// Le span porte l'ID de requête : le middleware doit donc suivre LoggingMiddleware
+// Le contexte du span est placé dans c.UserContext(), que les handlers passent à MongoDB et au scraper
+func TracingMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestID").(string)
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		ctx = tracing.ContextWithRequestID(ctx, requestID)
+
+		// Le nom définitif (méthode et route) n'est connu qu'après le routage
+		ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+				semconv.ClientAddress(c.IP()),
+				tracing.RequestIDKey.String(requestID),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// Une erreur retournée n'est convertie en réponse que par l'ErrorHandler, après ce middleware
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+			span.RecordError(err)
+		}
+
+		route := routeKey(c)
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return err
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/maxime-louis14/api-golang/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useInMemoryExporter installe un fournisseur global enregistrant les spans en mémoire le temps du test
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+// spanAttributes indexe les attributs d'un span par nom
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+// Une requête produit un span serveur nommé par sa route, enfant du traceparent reçu, avec l'ID de requête
+// Le span d'une opération du handler (MongoDB, scraper) en est l'enfant
+func TestTracingMiddlewareRecordsRequestSpan(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Use(TracingMiddleware())
+	app.Get("/recette/:id", func(c *fiber.Ctx) error {
+		_, span := tracing.Start(c.UserContext(), "find recettes", "")
+		span.End()
+		return c.SendString("ok")
+	})
+
+	req := httptest.NewRequest("GET", "/recette/42", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /recette/:id", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	attrs := spanAttributes(server)
+	assert.Equal(t, "req-42", attrs[tracing.RequestIDKey].AsString())
+	assert.Equal(t, "/recette/:id", attrs["http.route"].AsString())
+	assert.Equal(t, "/recette/42", attrs["url.path"].AsString())
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, server.Status.Code)
+
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, "req-42", spanAttributes(child)[tracing.RequestIDKey].AsString())
+}
+
+// Une erreur 5xx retournée par le handler marque le span en erreur
+func TestTracingMiddlewareMarksServerErrors(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.Use(TracingMiddleware())
+	app.Get("/panne", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "indisponible")
+	})
+	app.Get("/absente", func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	for _, path := range []string{"/panne", "/absente"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, int64(503), spanAttributes(spans[0])["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, int64(404), spanAttributes(spans[1])["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+}
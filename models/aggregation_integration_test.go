@@ -0,0 +1,225 @@
+//go:build integration
+
+// Tests d'intégration des agrégations contre un vrai mongod, désigné par MONGO_URI :
+//
+//	MONGO_URI=mongodb://localhost:27017 go test -tags integration ./models/
+//
+// Chaque test travaille dans une base jetable, supprimée à la fin ; sans MONGO_URI, les tests sont ignorés
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/database/mongoconfig"
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seedCollection insère les recettes dans la collection d'une base jetable et la retourne
+func seedCollection(t *testing.T, recettes []Recette) *mongo.Collection {
+	uri, _ := mongoconfig.URIFromEnv()
+	if uri == "" {
+		t.Skip("MONGO_URI non définie : tests d'intégration MongoDB ignorés")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil))
+	db := client.Database(fmt.Sprintf("recettes_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+
+	collection := db.Collection("recettes")
+	if len(recettes) > 0 {
+		docs := make([]interface{}, 0, len(recettes))
+		for _, recette := range recettes {
+			docs = append(docs, recette)
+		}
+		_, err = collection.InsertMany(ctx, docs)
+		require.NoError(t, err)
+	}
+	return collection
+}
+
+// runPipeline exécute l'agrégation sur la collection et décode le résultat dans out
+func runPipeline(t *testing.T, collection *mongo.Collection, pipeline []bson.D, out interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, out))
+}
+
+// Fréquences des ingrédients, nom affiché en minuscules pour une recette importée avant la normalisation
+func TestTopIngredientsPipelineMongo(t *testing.T) {
+	// Fréquences attendues : salt 4, butter 3, egg 2, puis une recette chacun
+	recettes := []Recette{
+		recetteWith("1 cup all-purpose flour", "2 Eggs", "Salt", "1 cup Butter"),
+		recetteWith("flour", "egg", "salt", "salt, to taste"), // salt répété : compté une fois
+		recetteWith("butter", "Sugar", "1 teaspoon salt"),
+		recetteWith("Butter"),
+		// Recette importée avant la normalisation : nom affiché en minuscules
+		{Name: "Ancienne", Ingredients: []Ingredient{{Quantity: "1", Name: "SALT"}, {Quantity: "1", Name: "Vanilla Extract"}}},
+		// Ingrédient sans nom (quantité seule) : ignoré
+		{Name: "Quantités", Ingredients: []Ingredient{{Quantity: "2 cups"}}},
+		{Name: "Sans ingrédient"},
+	}
+
+	collection := seedCollection(t, recettes)
+	var top []IngredientCount
+	runPipeline(t, collection, TopIngredientsPipeline(DefaultTopIngredientsLimit), &top)
+	assert.Equal(t, []IngredientCount{
+		{Name: "salt", Count: 4},
+		{Name: "butter", Count: 3},
+		{Name: "egg", Count: 2},
+		{Name: "all-purpose flour", Count: 1},
+		{Name: "flour", Count: 1},
+		{Name: "sugar", Count: 1},
+		{Name: "vanilla extract", Count: 1},
+	}, top)
+
+	// ?limit : les plus fréquents d'abord
+	top = nil
+	runPipeline(t, collection, TopIngredientsPipeline(3), &top)
+	assert.Equal(t, []IngredientCount{{Name: "salt", Count: 4}, {Name: "butter", Count: 3}, {Name: "egg", Count: 2}}, top)
+
+	// Collection vide : liste vide
+	top = nil
+	runPipeline(t, seedCollection(t, nil), TopIngredientsPipeline(DefaultTopIngredientsLimit), &top)
+	assert.Empty(t, top)
+}
+
+// La recette partageant le plus d'ingrédients arrive en tête ; la recette elle-même et celles
+// sans ingrédient commun sont exclues
+func TestSimilarPipelineMongo(t *testing.T) {
+	source := recetteWith("flour", "egg", "butter", "sugar", "salt")
+	source.ID = primitive.NewObjectID()
+
+	closest := recetteWith("flour", "egg", "butter", "sugar", "milk")
+	closest.Name = "Crêpes"
+	second := recetteWith("flour", "egg", "butter", "2 eggs") // egg répété : compté une fois
+	second.Name = "Pâte brisée"
+	tie := recetteWith("salt", "sugar", "lemon")
+	tie.Name = "Citronnade"
+	other := recetteWith("tomato", "basil")
+	other.Name = "Salade"
+	// Recette importée avant la normalisation : comparée sur ses noms affichés en minuscules
+	older := Recette{Name: "Biscuits", Ingredients: []Ingredient{{Quantity: "1", Name: "Flour"}, {Quantity: "1", Name: "SUGAR"}}}
+
+	collection := seedCollection(t, []Recette{other, tie, second, source, closest, older})
+	names := IngredientNames(source)
+
+	var similar []SimilarRecette
+	runPipeline(t, collection, SimilarPipeline(source.ID, names, DefaultSimilarLimit), &similar)
+	require.Len(t, similar, 4)
+
+	assert.Equal(t, "Crêpes", similar[0].Name)
+	assert.Equal(t, 4, similar[0].Score)
+	assert.ElementsMatch(t, []string{"flour", "egg", "butter", "sugar"}, similar[0].SharedIngredients)
+	assert.Equal(t, 5, similar[0].IngredientCount)
+	assert.Equal(t, "Pâte brisée", similar[1].Name)
+	assert.Equal(t, 3, similar[1].Score)
+	assert.Equal(t, "Biscuits", similar[2].Name)
+	assert.Equal(t, 2, similar[2].Score)
+	assert.ElementsMatch(t, []string{"flour", "sugar"}, similar[2].SharedIngredients)
+	assert.Equal(t, "Citronnade", similar[3].Name)
+	assert.Equal(t, 2, similar[3].Score)
+	for _, recette := range similar {
+		assert.NotEqual(t, source.ID, recette.ID)
+	}
+
+	// ?limit
+	similar = nil
+	runPipeline(t, collection, SimilarPipeline(source.ID, names, 1), &similar)
+	require.Len(t, similar, 1)
+	assert.Equal(t, "Crêpes", similar[0].Name)
+}
+
+// incompleteRecettes couvre chaque cas d'absence : champ absent (nil), vide, et recette complète
+func incompleteRecettes() []Recette {
+	complete := func(name string) Recette {
+		return Recette{
+			Name:         name,
+			Page:         "https://www.allrecipes.com/recipe/" + name,
+			Image:        "https://img.example/" + name + ".jpg",
+			Ingredients:  []Ingredient{{Quantity: "2", Name: "carottes"}},
+			Instructions: []Instruction{{Number: "1", Description: "Cuire."}},
+			Rating:       &recipe.Rating{Value: 4.5, Count: 12},
+			Nutrition:    &recipe.Nutrition{Calories: "120"},
+		}
+	}
+
+	noIngredients := complete("sans-ingredients")
+	noIngredients.Ingredients = nil
+	emptyIngredients := complete("ingredients-vides")
+	emptyIngredients.Ingredients = []Ingredient{}
+	noInstructions := complete("sans-instructions")
+	noInstructions.Instructions = []Instruction{}
+	noImage := complete("sans-image")
+	noImage.Image = ""
+	noNutrition := complete("sans-nutrition")
+	noNutrition.Nutrition = nil
+	emptyNutrition := complete("nutrition-vide")
+	emptyNutrition.Nutrition = &recipe.Nutrition{}
+	noRating := complete("sans-note")
+	noRating.Rating = nil
+	noReview := complete("sans-avis")
+	noReview.Rating = &recipe.Rating{}
+
+	return []Recette{
+		complete("complete"), noIngredients, emptyIngredients, noInstructions,
+		noImage, noNutrition, emptyNutrition, noRating, noReview,
+	}
+}
+
+// incompleteNames exécute la liste paginée des recettes auxquelles manque field et retourne leurs noms
+func incompleteNames(t *testing.T, collection *mongo.Collection, field string, query ListQuery) []string {
+	filter, err := MissingFilter(field)
+	require.NoError(t, err)
+	var summaries []RecetteSummary
+	runPipeline(t, collection, query.Pipeline(filter), &summaries)
+	names := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		names = append(names, summary.Name)
+	}
+	return names
+}
+
+func TestMissingFilterMongo(t *testing.T) {
+	collection := seedCollection(t, incompleteRecettes())
+	query := ListQuery{Fields: FieldsSummary, Page: 1, Limit: DefaultIncompleteLimit}
+
+	assert.Equal(t, []string{"sans-ingredients", "ingredients-vides"}, incompleteNames(t, collection, MissingIngredients, query))
+	assert.Equal(t, []string{"sans-instructions"}, incompleteNames(t, collection, MissingInstructions, query))
+	assert.Equal(t, []string{"sans-image"}, incompleteNames(t, collection, MissingImage, query))
+	assert.Equal(t, []string{"sans-nutrition", "nutrition-vide"}, incompleteNames(t, collection, MissingNutrition, query))
+	assert.Equal(t, []string{"sans-note", "sans-avis"}, incompleteNames(t, collection, MissingRating, query))
+}
+
+// Les recettes incomplètes sont paginées comme les autres listes
+func TestMissingFilterPaginationMongo(t *testing.T) {
+	collection := seedCollection(t, incompleteRecettes())
+
+	query := ListQuery{Fields: FieldsSummary, Sort: SortName, Page: 1, Limit: 1}
+	assert.Equal(t, []string{"ingredients-vides"}, incompleteNames(t, collection, MissingIngredients, query))
+	query.Page = 2
+	assert.Equal(t, []string{"sans-ingredients"}, incompleteNames(t, collection, MissingIngredients, query))
+	query.Page = 3
+	assert.Empty(t, incompleteNames(t, collection, MissingIngredients, query))
+}
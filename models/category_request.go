@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// DefaultCategoryPages est la valeur de max_pages pour POST /scraper/category quand elle est omise
+// (borne haute : scraperspec.MaxCategoryPages, commune avec -category-pages du scraper)
+const DefaultCategoryPages = 3
+
+// CategoryHosts sont les sites dont le scraper sait lire les pages de catégories et de recettes
+var CategoryHosts = []string{"www.allrecipes.com", "allrecipes.com"}
+
+// CategoryRequest est le corps attendu par POST /scraper/category
+type CategoryRequest struct {
+	URL      string `json:"url"`       // Page de catégorie AllRecipes
+	MaxPages int    `json:"max_pages"` // Pages parcourues, première comprise (0 = DefaultCategoryPages)
+	Upsert   bool   `json:"upsert"`    // Enregistrer les recettes (upsert par page) au lieu de seulement les renvoyer
+}
+
+// Validate vérifie l'URL (http(s), hôte de CategoryHosts) et applique la valeur par défaut de max_pages
+func (r *CategoryRequest) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL invalide %q: URL http(s) absolue attendue", r.URL)
+	}
+	if !allowedCategoryHost(u.Hostname()) {
+		return fmt.Errorf("hôte non pris en charge %q: %s attendu", u.Hostname(), strings.Join(CategoryHosts, " ou "))
+	}
+	if r.MaxPages == 0 {
+		r.MaxPages = DefaultCategoryPages
+	}
+	if r.MaxPages < 1 || r.MaxPages > scraperspec.MaxCategoryPages {
+		return fmt.Errorf("max_pages invalide %d: entier entre 1 et %d attendu", r.MaxPages, scraperspec.MaxCategoryPages)
+	}
+	return nil
+}
+
+// allowedCategoryHost indique si l'hôte fait partie de CategoryHosts, sans tenir compte de la casse
+func allowedCategoryHost(host string) bool {
+	for _, allowed := range CategoryHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRequestValidate(t *testing.T) {
+	request := CategoryRequest{URL: "https://www.allrecipes.com/recipes/79/desserts/"}
+	require.NoError(t, request.Validate())
+	assert.Equal(t, DefaultCategoryPages, request.MaxPages)
+
+	request = CategoryRequest{URL: "https://AllRecipes.com/recipes/79/desserts/", MaxPages: 5}
+	require.NoError(t, request.Validate())
+	assert.Equal(t, 5, request.MaxPages)
+
+	for _, invalid := range []CategoryRequest{
+		{URL: ""},
+		{URL: "/recipes/79/desserts/"},
+		{URL: "ftp://www.allrecipes.com/recipes/79/"},
+		{URL: "https://evil.example.com/recipes/79/"},
+		{URL: "https://www.allrecipes.com.evil.example/recipes/79/"},
+		{URL: "https://www.allrecipes.com/recipes/79/", MaxPages: -1},
+		{URL: "https://www.allrecipes.com/recipes/79/", MaxPages: scraperspec.MaxCategoryPages + 1},
+	} {
+		assert.Error(t, invalid.Validate(), invalid.URL)
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/maxime-louis14/api-golang/recipe"
+
+// SkipUnchanged retire d'un lot les recettes dont l'empreinte est celle déjà enregistrée sous leur recipeId
+// stored associe un recipeId (recipe.ID de la page) à l'empreinte en base ; le nombre de recettes retirées est retourné
+func SkipUnchanged(recettes []Recette, stored map[string]string) ([]Recette, int) {
+	changed := recettes[:0]
+	unchanged := 0
+	for _, recette := range recettes {
+		if hash, ok := stored[recipe.ID(recette.Page)]; ok && hash != "" && hash == recipe.ContentHash(recette) {
+			unchanged++
+			continue
+		}
+		changed = append(changed, recette)
+	}
+	return changed, unchanged
+}
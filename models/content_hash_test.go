@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/stretchr/testify/assert"
+)
+
+func soupeRecette() Recette {
+	return Recette{
+		Name:         "Soupe de légumes",
+		Page:         "https://example.com/soupe",
+		Ingredients:  []Ingredient{{Quantity: "2 carottes", Name: "carottes"}, {Quantity: "1 oignon", Name: "oignon"}},
+		Instructions: []Instruction{{Number: "1", Description: "Éplucher les légumes."}, {Number: "2", Description: "Cuire 20 minutes."}},
+	}
+}
+
+func TestSkipUnchanged(t *testing.T) {
+	soupe := soupeRecette()
+	tarte := Recette{Name: "Tarte", Page: "https://example.com/tarte"}
+	nouvelle := Recette{Name: "Nouvelle", Page: "https://example.com/nouvelle"}
+	stored := map[string]string{
+		recipe.ID(soupe.Page): recipe.ContentHash(soupe),
+		recipe.ID(tarte.Page): "ancienne",
+	}
+
+	// Même recette sous une variante de son URL : reconnue par son recipeId
+	variante := soupeRecette()
+	variante.Page = soupe.Page + "/?utm_source=newsletter"
+	changed, unchanged := SkipUnchanged([]Recette{soupe, tarte, nouvelle, variante}, stored)
+	assert.Equal(t, 2, unchanged)
+	assert.Equal(t, []Recette{tarte, nouvelle}, changed)
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LatestDataFile retourne le fichier de recettes le plus récent parmi les emplacements paths, par ordre de priorité
+// Dans le répertoire de chaque emplacement, le fichier lui-même et ceux correspondant à pattern sont comparés
+// par date de modification (à égalité, le nom le plus grand, donc l'horodatage le plus récent, l'emporte)
+// Le premier répertoire contenant au moins un fichier est retenu
+func LatestDataFile(paths []string, pattern string) (string, bool) {
+	for _, path := range paths {
+		candidates := []string{path}
+		if matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), pattern)); err == nil {
+			candidates = append(candidates, matches...)
+		}
+
+		latest := ""
+		var latestInfo os.FileInfo
+		for _, candidate := range candidates {
+			info, err := os.Stat(candidate)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) ||
+				(info.ModTime().Equal(latestInfo.ModTime()) && candidate > latest) {
+				latest, latestInfo = candidate, info
+			}
+		}
+		if latestInfo != nil {
+			return latest, true
+		}
+	}
+	return "", false
+}
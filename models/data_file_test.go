@@ -0,0 +1,67 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// touch crée name dans dir, modifié à modifiedAt
+func touch(t *testing.T, dir, name string, modifiedAt time.Time) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+	require.NoError(t, os.Chtimes(path, modifiedAt, modifiedAt))
+	return path
+}
+
+// Le fichier horodaté le plus récent l'emporte sur data.json et les exécutions précédentes
+func TestLatestDataFile(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	touch(t, dir, "data.json", now.Add(-3*time.Hour))
+	touch(t, dir, "data-20240301-000000.json", now.Add(-2*time.Hour))
+	latest := touch(t, dir, "data-20240301-060000.json", now.Add(-time.Hour))
+	touch(t, dir, "stats.json", now)
+
+	path, found := LatestDataFile([]string{filepath.Join(dir, "data.json")}, scraperspec.TimestampedDataPattern)
+	require.True(t, found)
+	assert.Equal(t, latest, path)
+
+	// data.json réécrit depuis redevient le plus récent
+	current := touch(t, dir, "data.json", now)
+	path, _ = LatestDataFile([]string{filepath.Join(dir, "data.json")}, scraperspec.TimestampedDataPattern)
+	assert.Equal(t, current, path)
+}
+
+// À date de modification égale, le nom le plus grand (horodatage le plus récent) l'emporte
+func TestLatestDataFileSameModTime(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Now().Truncate(time.Second)
+	touch(t, dir, "data-20240301-000000.json", at)
+	latest := touch(t, dir, "data-20240301-060000.json", at)
+
+	path, found := LatestDataFile([]string{filepath.Join(dir, "data.json")}, scraperspec.TimestampedDataPattern)
+	require.True(t, found)
+	assert.Equal(t, latest, path)
+}
+
+// Les emplacements sont essayés dans l'ordre : le premier répertoire contenant un fichier est retenu
+func TestLatestDataFilePriority(t *testing.T) {
+	empty, first, second := t.TempDir(), t.TempDir(), t.TempDir()
+	now := time.Now()
+	expected := touch(t, first, "data-20240301-000000.json", now.Add(-time.Hour))
+	touch(t, second, "data.json", now)
+
+	paths := []string{filepath.Join(empty, "data.json"), filepath.Join(first, "data.json"), filepath.Join(second, "data.json")}
+	path, found := LatestDataFile(paths, scraperspec.TimestampedDataPattern)
+	require.True(t, found)
+	assert.Equal(t, expected, path)
+
+	_, found = LatestDataFile([]string{filepath.Join(empty, "data.json")}, scraperspec.TimestampedDataPattern)
+	assert.False(t, found)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultDataMaxAge est l'âge au-delà duquel data.json est périmé quand ?max_age n'est pas fourni
+const DefaultDataMaxAge = 24 * time.Hour
+
+// DataFreshness indique si le fichier produit par le scraper est assez récent
+type DataFreshness struct {
+	Path       string     `json:"path"`
+	Exists     bool       `json:"exists"`
+	ModifiedAt *time.Time `json:"modified_at,omitempty"`
+	Size       int64      `json:"size"`
+	AgeSeconds float64    `json:"age_seconds"`
+	MaxAge     string     `json:"max_age"`
+	Stale      bool       `json:"stale"` // Plus vieux que max_age, ou absent
+}
+
+// ParseMaxAge valide le paramètre ?max_age= (durée Go, ex: 6h, 90m ; vide = DefaultDataMaxAge)
+func ParseMaxAge(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultDataMaxAge, nil
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil || maxAge <= 0 {
+		return 0, fmt.Errorf("max_age invalide %q: durée positive attendue (ex: 6h, 90m)", value)
+	}
+	return maxAge, nil
+}
+
+// CheckDataFreshness compare la date de modification de path à maxAge, à l'instant now
+// Un fichier absent est périmé (le scraper n'a jamais tourné) ; les autres erreurs de stat sont retournées
+func CheckDataFreshness(path string, maxAge time.Duration, now time.Time) (DataFreshness, error) {
+	freshness := DataFreshness{Path: path, MaxAge: maxAge.String(), Stale: true}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return freshness, nil
+	}
+	if err != nil {
+		return DataFreshness{}, err
+	}
+
+	modifiedAt := info.ModTime()
+	age := now.Sub(modifiedAt)
+	freshness.Exists = true
+	freshness.ModifiedAt = &modifiedAt
+	freshness.Size = info.Size()
+	freshness.AgeSeconds = age.Seconds()
+	freshness.Stale = age > maxAge
+	return freshness, nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDataFile crée un data.json modifié à modifiedAt
+func writeDataFile(t *testing.T, modifiedAt time.Time) string {
+	path := filepath.Join(t.TempDir(), "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+	require.NoError(t, os.Chtimes(path, modifiedAt, modifiedAt))
+	return path
+}
+
+func TestCheckDataFreshnessFresh(t *testing.T) {
+	now := time.Now()
+	path := writeDataFile(t, now.Add(-2*time.Hour))
+
+	freshness, err := CheckDataFreshness(path, 6*time.Hour, now)
+	require.NoError(t, err)
+	assert.True(t, freshness.Exists)
+	assert.False(t, freshness.Stale)
+	assert.Equal(t, int64(2), freshness.Size)
+	assert.InDelta(t, (2 * time.Hour).Seconds(), freshness.AgeSeconds, 1)
+	assert.Equal(t, "6h0m0s", freshness.MaxAge)
+}
+
+func TestCheckDataFreshnessStale(t *testing.T) {
+	now := time.Now()
+	path := writeDataFile(t, now.Add(-7*time.Hour))
+
+	freshness, err := CheckDataFreshness(path, 6*time.Hour, now)
+	require.NoError(t, err)
+	assert.True(t, freshness.Exists)
+	assert.True(t, freshness.Stale)
+	require.NotNil(t, freshness.ModifiedAt)
+}
+
+func TestCheckDataFreshnessMissing(t *testing.T) {
+	freshness, err := CheckDataFreshness(filepath.Join(t.TempDir(), "data.json"), time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.False(t, freshness.Exists)
+	assert.True(t, freshness.Stale)
+	assert.Nil(t, freshness.ModifiedAt)
+}
+
+func TestParseMaxAge(t *testing.T) {
+	maxAge, err := ParseMaxAge("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDataMaxAge, maxAge)
+
+	maxAge, err = ParseMaxAge("6h")
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, maxAge)
+
+	for _, invalid := range []string{"6", "-1h", "0s", "demain"} {
+		_, err := ParseMaxAge(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrEmptyDeleteFilter est renvoyée quand aucun critère n'est fourni à une suppression en masse
+var ErrEmptyDeleteFilter = errors.New("au moins un filtre est requis (category, ingredient ou empty_instructions=true)")
+
+// DeleteParams contient les paramètres bruts de DELETE /recettes
+type DeleteParams struct {
+	Category          string // ?category= (catégorie d'origine, ex: desserts)
+	Ingredient        string // ?ingredient= (même recherche que /recette/ingredient/:ingredient)
+	EmptyInstructions string // ?empty_instructions=true (recettes sans instructions)
+}
+
+// Filter construit le filtre de suppression ; les critères fournis sont combinés (ET)
+// Un filtre vide est refusé pour ne jamais vider toute la collection par accident
+func (p DeleteParams) Filter() (bson.M, error) {
+	var conditions bson.A
+
+	if category := strings.ToLower(strings.TrimSpace(p.Category)); category != "" {
+		conditions = append(conditions, bson.M{"category": category})
+	}
+	if ingredient := strings.TrimSpace(p.Ingredient); ingredient != "" {
+		conditions = append(conditions, IngredientFilter(ingredient))
+	}
+	if p.EmptyInstructions != "" {
+		empty, err := strconv.ParseBool(p.EmptyInstructions)
+		if err != nil {
+			return nil, fmt.Errorf("valeur de empty_instructions invalide %q: true ou false attendu", p.EmptyInstructions)
+		}
+		if empty {
+			// Champ absent, null ou tableau vide
+			conditions = append(conditions, emptyArrayFilter("instructions"))
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+		return nil, ErrEmptyDeleteFilter
+	case 1:
+		return conditions[0].(bson.M), nil
+	default:
+		return bson.M{"$and": conditions}, nil
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDeleteParamsFilter(t *testing.T) {
+	filter, err := DeleteParams{Category: " Desserts "}.Filter()
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"category": "desserts"}, filter)
+
+	filter, err = DeleteParams{Ingredient: "Tomatoes"}.Filter()
+	require.NoError(t, err)
+	assert.Equal(t, IngredientFilter("Tomatoes"), filter)
+
+	// Plusieurs critères sont combinés
+	filter, err = DeleteParams{Category: "soup", EmptyInstructions: "true"}.Filter()
+	require.NoError(t, err)
+	conditions, ok := filter["$and"].(bson.A)
+	require.True(t, ok)
+	assert.Len(t, conditions, 2)
+	assert.Equal(t, bson.M{"category": "soup"}, conditions[0])
+}
+
+func TestDeleteParamsEmptyFilterRejected(t *testing.T) {
+	for _, params := range []DeleteParams{
+		{},
+		{Category: "  "},
+		{EmptyInstructions: "false"},
+	} {
+		_, err := params.Filter()
+		assert.ErrorIs(t, err, ErrEmptyDeleteFilter, "%+v", params)
+	}
+
+	_, err := DeleteParams{EmptyInstructions: "oui"}.Filter()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrEmptyDeleteFilter)
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+)
+
+// ValueChange est un élément présent dans les deux versions avec un contenu différent
+type ValueChange struct {
+	Key    string `json:"key"`    // Nom normalisé de l'ingrédient ou numéro de l'étape
+	Before string `json:"before"` // Version enregistrée
+	After  string `json:"after"`  // Version fraîchement scrapée
+}
+
+// ListDiff liste les différences d'une liste d'ingrédients ou d'instructions
+type ListDiff struct {
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+	Changed []ValueChange `json:"changed"`
+}
+
+// RecetteDiff compare la version enregistrée d'une recette à une version fraîchement scrapée
+type RecetteDiff struct {
+	Page         string   `json:"page"`
+	Changed      bool     `json:"changed"` // Empreintes de contenu différentes
+	StoredHash   string   `json:"storedHash"`
+	FreshHash    string   `json:"freshHash"`
+	Name         []string `json:"name,omitempty"` // [avant, après] si le nom a changé
+	Ingredients  ListDiff `json:"ingredients"`
+	Instructions ListDiff `json:"instructions"`
+}
+
+// DiffRecettes compare stored (base) et fresh (scrape) : les ingrédients sont appariés par nom normalisé,
+// les instructions par position
+func DiffRecettes(stored, fresh Recette) RecetteDiff {
+	diff := RecetteDiff{
+		Page:         fresh.Page,
+		StoredHash:   recipe.ContentHash(stored),
+		FreshHash:    recipe.ContentHash(fresh),
+		Ingredients:  diffIngredients(stored.Ingredients, fresh.Ingredients),
+		Instructions: diffInstructions(stored.Instructions, fresh.Instructions),
+	}
+	diff.Changed = diff.StoredHash != diff.FreshHash
+	if normalizeSpaces(stored.Name) != normalizeSpaces(fresh.Name) {
+		diff.Name = []string{stored.Name, fresh.Name}
+	}
+	return diff
+}
+
+// ingredientText est le texte affiché d'un ingrédient (le scraper met déjà le nom dans la quantité)
+func ingredientText(ingredient Ingredient) string {
+	text := strings.TrimSpace(strings.Join([]string{ingredient.Quantity, ingredient.Unit}, " "))
+	if ingredient.Name != "" && !strings.Contains(strings.ToLower(text), strings.ToLower(ingredient.Name)) {
+		text = strings.TrimSpace(text + " " + ingredient.Name)
+	}
+	return normalizeSpaces(text)
+}
+
+// ingredientKey est la clé d'appariement d'un ingrédient entre les deux versions
+func ingredientKey(ingredient Ingredient) string {
+	if ingredient.Name != "" {
+		return NormalizeIngredientName(ingredient.Name)
+	}
+	return NormalizeIngredientName(ingredient.Quantity)
+}
+
+func diffIngredients(before, after []Ingredient) ListDiff {
+	diff := ListDiff{Added: []string{}, Removed: []string{}, Changed: []ValueChange{}}
+
+	stored := make(map[string]string, len(before))
+	for _, ingredient := range before {
+		stored[ingredientKey(ingredient)] = ingredientText(ingredient)
+	}
+
+	seen := make(map[string]bool, len(after))
+	for _, ingredient := range after {
+		key := ingredientKey(ingredient)
+		seen[key] = true
+		text := ingredientText(ingredient)
+		previous, ok := stored[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, text)
+		case !strings.EqualFold(previous, text):
+			diff.Changed = append(diff.Changed, ValueChange{Key: key, Before: previous, After: text})
+		}
+	}
+	for _, ingredient := range before {
+		if !seen[ingredientKey(ingredient)] {
+			diff.Removed = append(diff.Removed, ingredientText(ingredient))
+		}
+	}
+	return diff
+}
+
+func diffInstructions(before, after []Instruction) ListDiff {
+	diff := ListDiff{Added: []string{}, Removed: []string{}, Changed: []ValueChange{}}
+	for i := 0; i < len(before) || i < len(after); i++ {
+		switch {
+		case i >= len(before):
+			diff.Added = append(diff.Added, normalizeSpaces(after[i].Description))
+		case i >= len(after):
+			diff.Removed = append(diff.Removed, normalizeSpaces(before[i].Description))
+		default:
+			previous, text := normalizeSpaces(before[i].Description), normalizeSpaces(after[i].Description)
+			if previous != text {
+				diff.Changed = append(diff.Changed, ValueChange{Key: strconv.Itoa(i + 1), Before: previous, After: text})
+			}
+		}
+	}
+	return diff
+}
+
+// normalizeSpaces supprime les espaces superflus
+func normalizeSpaces(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
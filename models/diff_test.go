@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRecettes(t *testing.T) {
+	stored := soupeRecette()
+	stored.Ingredients = append(stored.Ingredients, Ingredient{Quantity: "1 pincée de sel", Name: "sel"})
+
+	// Page modifiée : quantité de carottes, sel retiré, eau ajoutée, étape 2 reformulée, étape 3 ajoutée
+	fresh := soupeRecette()
+	fresh.Ingredients = []Ingredient{
+		{Quantity: "3 carottes", Name: "carottes"},
+		{Quantity: "1 oignon", Name: "oignon"},
+		{Quantity: "1 l d'eau", Name: "eau"},
+	}
+	fresh.Instructions = append(fresh.Instructions, Instruction{Number: "3", Description: "Mixer."})
+	fresh.Instructions[1].Description = "Cuire  25 minutes."
+
+	diff := DiffRecettes(stored, fresh)
+	assert.True(t, diff.Changed)
+	assert.Equal(t, recipe.ContentHash(stored), diff.StoredHash)
+	assert.Nil(t, diff.Name)
+
+	assert.Equal(t, []string{"1 l d'eau"}, diff.Ingredients.Added)
+	assert.Equal(t, []string{"1 pincée de sel"}, diff.Ingredients.Removed)
+	assert.Equal(t, []ValueChange{{Key: "carotte", Before: "2 carottes", After: "3 carottes"}}, diff.Ingredients.Changed)
+
+	assert.Equal(t, []string{"Mixer."}, diff.Instructions.Added)
+	assert.Empty(t, diff.Instructions.Removed)
+	assert.Equal(t, []ValueChange{{Key: "2", Before: "Cuire 20 minutes.", After: "Cuire 25 minutes."}}, diff.Instructions.Changed)
+}
+
+func TestDiffRecettesName(t *testing.T) {
+	stored := soupeRecette()
+	stored.Ingredients = []Ingredient{{Quantity: "2", Unit: "", Name: "carottes"}, {Quantity: "1 oignon"}}
+	fresh := stored
+	fresh.Name = "Velouté"
+
+	diff := DiffRecettes(stored, fresh)
+	assert.True(t, diff.Changed)
+	assert.Equal(t, []string{"Soupe de légumes", "Velouté"}, diff.Name)
+	assert.Empty(t, diff.Ingredients.Added)
+	assert.Empty(t, diff.Ingredients.Changed)
+	assert.Empty(t, diff.Instructions.Changed)
+
+	diff = DiffRecettes(stored, stored)
+	assert.False(t, diff.Changed)
+}
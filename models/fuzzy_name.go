@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// FuzzyThreshold est la similarité minimale (0 à 1) d'un nom retenu par ?fuzzy=true
+	FuzzyThreshold = 0.7
+	// MaxFuzzyResults borne le nombre de recettes renvoyées par ?fuzzy=true
+	MaxFuzzyResults = 10
+	// MaxFuzzyCandidates borne le nombre de recettes lues en base avant le calcul des scores
+	MaxFuzzyCandidates = 500
+)
+
+// FuzzyMatch est une recette dont le nom est proche de la recherche
+type FuzzyMatch struct {
+	Score   float64 `json:"score"` // Similarité du nom, 1 = identique à la casse près
+	Recette Recette `json:"recette"`
+}
+
+// ParseFuzzy valide le paramètre ?fuzzy= de /recette/name/:name (vide = recherche exacte)
+func ParseFuzzy(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	fuzzy, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("valeur de fuzzy invalide %q: true ou false attendu", value)
+	}
+	return fuzzy, nil
+}
+
+// FuzzyCandidateFilter présélectionne les recettes partageant au moins un trigramme avec le nom cherché
+// Une faute de frappe ne touche que quelques trigrammes : le bon nom reste parmi les candidats,
+// sans lire toute la collection
+func FuzzyCandidateFilter(name string) bson.M {
+	grams := trigrams(name)
+	if len(grams) == 0 {
+		// Nom de moins de 3 caractères : recherche par sous-chaîne
+		return bson.M{"name": bson.M{"$regex": regexp.QuoteMeta(normalizeName(name)), "$options": "i"}}
+	}
+	clauses := make([]bson.M, 0, len(grams))
+	for _, gram := range grams {
+		clauses = append(clauses, bson.M{"name": bson.M{"$regex": regexp.QuoteMeta(gram), "$options": "i"}})
+	}
+	return bson.M{"$or": clauses}
+}
+
+// RankByName garde les recettes dont le nom atteint le seuil de similarité, de la plus proche
+// à la plus éloignée (puis par nom), au plus limit
+func RankByName(name string, candidates []Recette, threshold float64, limit int) []FuzzyMatch {
+	matches := make([]FuzzyMatch, 0)
+	for _, candidate := range candidates {
+		if score := NameSimilarity(name, candidate.Name); score >= threshold {
+			matches = append(matches, FuzzyMatch{Score: score, Recette: candidate})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Recette.Name < matches[j].Recette.Name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// NameSimilarity vaut 1 - distance de Levenshtein / longueur du plus long nom, sans tenir compte
+// de la casse ni des espaces superflus : "choclate cake" et "Chocolate Cake" valent 0.93
+func NameSimilarity(a, b string) float64 {
+	a, b = normalizeName(a), normalizeName(b)
+	longest := utf8.RuneCountInString(a)
+	if n := utf8.RuneCountInString(b); n > longest {
+		longest = n
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein compte les insertions, suppressions et substitutions de caractères entre a et b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// trigrams retourne les trigrammes distincts de chaque mot du nom (mots de 3 caractères et plus)
+func trigrams(name string) []string {
+	seen := make(map[string]bool)
+	var grams []string
+	for _, word := range strings.Fields(normalizeName(name)) {
+		runes := []rune(word)
+		for i := 0; i+3 <= len(runes); i++ {
+			gram := string(runes[i : i+3])
+			if !seen[gram] {
+				seen[gram] = true
+				grams = append(grams, gram)
+			}
+		}
+	}
+	return grams
+}
+
+// normalizeName met le nom en minuscules et réduit les espaces
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
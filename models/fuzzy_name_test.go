@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func fuzzyCandidates() []Recette {
+	return []Recette{
+		{Name: "Chocolate Chip Cookies"},
+		{Name: "Chocolate Cake"},
+		{Name: "Chicken Soup"},
+		{Name: "Pasta Carbonara"},
+	}
+}
+
+func TestRankByNameFindsMisspelledRecipe(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"choclate chip cookies", "Chocolate Chip Cookies"},
+		{"Chiken Soup", "Chicken Soup"},
+		{"pasta carbonera", "Pasta Carbonara"},
+		{"CHOCOLATE CAKE", "Chocolate Cake"},
+	}
+	for _, tt := range tests {
+		matches := RankByName(tt.query, fuzzyCandidates(), FuzzyThreshold, MaxFuzzyResults)
+
+		require.NotEmpty(t, matches, tt.query)
+		assert.Equal(t, tt.want, matches[0].Recette.Name, tt.query)
+		assert.GreaterOrEqual(t, matches[0].Score, FuzzyThreshold, tt.query)
+	}
+}
+
+func TestRankByNameOrdersAndCaps(t *testing.T) {
+	// "Chocolate Cake" est identique à la casse près, "Chocolate Chip Cookies" trop éloigné
+	matches := RankByName("chocolate cake", fuzzyCandidates(), 0.3, MaxFuzzyResults)
+	require.NotEmpty(t, matches)
+	assert.Equal(t, "Chocolate Cake", matches[0].Recette.Name)
+	assert.Equal(t, 1.0, matches[0].Score)
+	for i := 1; i < len(matches); i++ {
+		assert.GreaterOrEqual(t, matches[i-1].Score, matches[i].Score)
+	}
+
+	assert.Len(t, RankByName("chocolate cake", fuzzyCandidates(), 0, 2), 2)
+	assert.Empty(t, RankByName("beef wellington", fuzzyCandidates(), FuzzyThreshold, MaxFuzzyResults))
+}
+
+func TestNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, NameSimilarity("  Chicken   Soup", "chicken soup"))
+	assert.InDelta(t, 0.93, NameSimilarity("choclate cake", "Chocolate Cake"), 0.01)
+	assert.Equal(t, 0.0, NameSimilarity("abc", "xyz"))
+	assert.Equal(t, 1.0, NameSimilarity("", ""))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 1, levenshtein("crème", "creme"))
+}
+
+func TestFuzzyCandidateFilter(t *testing.T) {
+	// Chaque trigramme devient une clause : "chiken" partage "chi" avec "Chicken"
+	filter := FuzzyCandidateFilter("Chiken")
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"name": bson.M{"$regex": "chi", "$options": "i"}},
+		{"name": bson.M{"$regex": "hik", "$options": "i"}},
+		{"name": bson.M{"$regex": "ike", "$options": "i"}},
+		{"name": bson.M{"$regex": "ken", "$options": "i"}},
+	}}, filter)
+
+	// Nom trop court pour un trigramme, caractères spéciaux échappés
+	assert.Equal(t, bson.M{"name": bson.M{"$regex": `a\.`, "$options": "i"}}, FuzzyCandidateFilter("A."))
+}
+
+func TestParseFuzzy(t *testing.T) {
+	fuzzy, err := ParseFuzzy("")
+	require.NoError(t, err)
+	assert.False(t, fuzzy)
+
+	fuzzy, err = ParseFuzzy("true")
+	require.NoError(t, err)
+	assert.True(t, fuzzy)
+
+	_, err = ParseFuzzy("maybe")
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MaxImportBytes borne la taille d'un fichier envoyé à POST /recettes/import
+const MaxImportBytes = 32 << 20
+
+// Formats de fichier acceptés par l'import, détectés sur le premier caractère significatif
+const (
+	ImportFormatJSON   = "json"   // Tableau JSON de recettes ("[")
+	ImportFormatNDJSON = "ndjson" // Une recette JSON par ligne ("{"), format de /recettes/export
+)
+
+// ImportBatchSize est le nombre de recettes envoyées par BulkWrite
+const ImportBatchSize = 500
+
+// ErrUnknownImportFormat signale un fichier qui n'est ni un tableau JSON ni du NDJSON
+var ErrUnknownImportFormat = errors.New("format de fichier non reconnu: tableau JSON ou NDJSON attendu")
+
+// ReadRecettes décode un fichier d'import au fil de l'eau et appelle handle pour chaque recette valide
+// Une recette illisible ou invalide (ValidateRecette) est signalée à invalid (numéro d'enregistrement à partir de 1) et ignorée
+// Une erreur de syntaxe dans un tableau JSON empêche de lire la suite : elle est retournée
+func ReadRecettes(r io.Reader, handle func(Recette) error, invalid func(record int, err error)) (string, error) {
+	reader := bufio.NewReader(r)
+	first, err := firstSignificantByte(reader)
+	if err == io.EOF {
+		return "", errors.New("fichier vide")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch first {
+	case '[':
+		return ImportFormatJSON, readJSONArray(reader, handle, invalid)
+	case '{':
+		return ImportFormatNDJSON, readNDJSON(reader, handle, invalid)
+	default:
+		return "", ErrUnknownImportFormat
+	}
+}
+
+// firstSignificantByte retourne le premier caractère non blanc sans le consommer (BOM UTF-8 ignoré)
+func firstSignificantByte(reader *bufio.Reader) (byte, error) {
+	if bom, _ := reader.Peek(3); bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		reader.Discard(3)
+	}
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, reader.UnreadByte()
+		}
+	}
+}
+
+// checkImported vérifie qu'une recette décodée peut être importée (la page sert de clé d'upsert)
+// L'erreur est une *ValidationError détaillant les champs invalides
+func checkImported(recette Recette) error {
+	return ValidateRecette(recette)
+}
+
+func readJSONArray(reader io.Reader, handle func(Recette) error, invalid func(int, error)) error {
+	decoder := json.NewDecoder(reader)
+	if _, err := decoder.Token(); err != nil { // "["
+		return err
+	}
+
+	for record := 1; decoder.More(); record++ {
+		var recette Recette
+		err := decoder.Decode(&recette)
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			// Valeur de mauvais type : l'élément a été consommé, la lecture peut continuer
+			invalid(record, err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("enregistrement %d: %w", record, err)
+		}
+		if err := checkImported(recette); err != nil {
+			invalid(record, err)
+			continue
+		}
+		if err := handle(recette); err != nil {
+			return err
+		}
+	}
+
+	_, err := decoder.Token() // "]"
+	return err
+}
+
+func readNDJSON(reader *bufio.Reader, handle func(Recette) error, invalid func(int, error)) error {
+	for record := 1; ; {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var recette Recette
+			if decodeErr := json.Unmarshal(line, &recette); decodeErr != nil {
+				invalid(record, decodeErr)
+			} else if checkErr := checkImported(recette); checkErr != nil {
+				invalid(record, checkErr)
+			} else if handleErr := handle(recette); handleErr != nil {
+				return handleErr
+			}
+			record++
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// UpsertByPage construit l'upsert d'une recette importée, identifiée par son recipeId (recipe.ID de sa page)
+// ou, pour les recettes enregistrées avant l'identifiant, par sa page exacte
+// createdAt n'est posé qu'à l'insertion : une recette mise à jour garde sa date d'origine
+// L'empreinte de contenu est recalculée pour que les imports suivants puissent ignorer la recette inchangée
+// L'identifiant d'un export réimporté est ignoré : _id ne peut pas être modifié par $set
+func UpsertByPage(recette Recette, now time.Time) mongo.WriteModel {
+	recette.ID = primitive.NilObjectID
+	recette.CreatedAt = nil
+	recette.ContentHash = recipe.ContentHash(recette)
+	recette.RecipeID = recipe.ID(recette.Page)
+	NormalizeIngredients(&recette)
+	return mongo.NewUpdateOneModel().
+		SetFilter(bson.M{"$or": bson.A{
+			bson.M{"recipeId": recette.RecipeID},
+			bson.M{"page": recette.Page},
+		}}).
+		SetUpdate(bson.M{
+			"$set":         recette,
+			"$setOnInsert": bson.M{"createdAt": now},
+		}).
+		SetUpsert(true)
+}
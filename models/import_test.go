@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// readAll importe content et retourne le format, les pages lues, les enregistrements invalides et l'erreur
+func readAll(content string) (string, []string, []int, error) {
+	var pages []string
+	var invalid []int
+	format, err := ReadRecettes(strings.NewReader(content), func(r Recette) error {
+		pages = append(pages, r.Page)
+		return nil
+	}, func(record int, err error) {
+		invalid = append(invalid, record)
+	})
+	return format, pages, invalid, err
+}
+
+func TestReadRecettesJSONArray(t *testing.T) {
+	content := "\ufeff\n  [\n" +
+		`{"name": "Soupe", "page": "https://example.com/soupe", "ingredients": [{"quantity": "2", "unit": "cups"}]},` +
+		`{"name": 42, "page": "https://example.com/type"},` +
+		`{"name": "Sans page"},` +
+		`{"name": "Tarte", "page": "https://example.com/tarte", "ingredients": [{"quantity": "1", "unit": "cup"}]},` +
+		`{"name": "Sans ingrédient", "page": "https://example.com/vide"}` +
+		"]\n"
+
+	format, pages, invalid, err := readAll(content)
+	require.NoError(t, err)
+	assert.Equal(t, ImportFormatJSON, format)
+	assert.Equal(t, []string{"https://example.com/soupe", "https://example.com/tarte"}, pages)
+	assert.Equal(t, []int{2, 3, 5}, invalid)
+}
+
+func TestReadRecettesNDJSON(t *testing.T) {
+	content := `{"name": "Soupe", "page": "https://example.com/soupe", "ingredients": [{"quantity": "2", "unit": "cups"}]}` + "\n" +
+		"\n" +
+		`{"name": "Tronquée", "page": ` + "\n" +
+		`{"name": "Tarte", "page": "https://example.com/tarte", "ingredients": [{"quantity": "1", "unit": "cup"}]}` // Dernière ligne sans retour à la ligne
+
+	format, pages, invalid, err := readAll(content)
+	require.NoError(t, err)
+	assert.Equal(t, ImportFormatNDJSON, format)
+	assert.Equal(t, []string{"https://example.com/soupe", "https://example.com/tarte"}, pages)
+	assert.Equal(t, []int{2}, invalid)
+}
+
+func TestReadRecettesMalformed(t *testing.T) {
+	// Erreur de syntaxe dans un tableau : la suite ne peut pas être lue
+	_, pages, _, err := readAll(`[{"name": "Soupe", "page": "https://example.com/soupe", "ingredients": [{"quantity": "2"}]}, {"name": ]`)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"https://example.com/soupe"}, pages)
+
+	_, _, _, err = readAll("name,page\nSoupe,https://example.com/soupe\n")
+	assert.ErrorIs(t, err, ErrUnknownImportFormat)
+
+	_, _, _, err = readAll("   \n")
+	assert.Error(t, err)
+}
+
+func TestUpsertByPage(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	recette := Recette{
+		ID:          primitive.NewObjectID(),
+		Name:        "Soupe",
+		Page:        "https://example.com/soupe",
+		Ingredients: []Ingredient{{Name: "Diced Tomatoes"}},
+		CreatedAt:   &created,
+	}
+
+	model, ok := UpsertByPage(recette, now).(*mongo.UpdateOneModel)
+	require.True(t, ok)
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"recipeId": recipe.ID("https://example.com/soupe")},
+		bson.M{"page": "https://example.com/soupe"},
+	}}, model.Filter)
+	require.NotNil(t, model.Upsert)
+	assert.True(t, *model.Upsert)
+
+	update := model.Update.(bson.M)
+	set := update["$set"].(Recette)
+	assert.Nil(t, set.CreatedAt, "createdAt ne doit pas être écrasé")
+	assert.True(t, set.ID.IsZero(), "_id ne doit pas être modifié")
+	assert.Equal(t, "diced tomato", set.Ingredients[0].NameNormalized)
+	assert.Equal(t, recipe.ContentHash(recette), set.ContentHash)
+	assert.Equal(t, recipe.ID(recette.Page), set.RecipeID)
+	assert.Equal(t, bson.M{"createdAt": now}, update["$setOnInsert"])
+	assert.NotNil(t, recette.CreatedAt, "la recette d'origine n'est pas modifiée")
+}
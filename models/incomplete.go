@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Champs acceptés par ?missing= de /recettes/incomplete
+const (
+	MissingIngredients  = "ingredients"
+	MissingInstructions = "instructions"
+	MissingImage        = "image"
+	MissingNutrition    = "nutrition"
+	MissingRating       = "rating"
+)
+
+// DefaultIncompleteLimit est la taille de page de /recettes/incomplete sans ?limit
+// Contrairement à /recettes, la liste est toujours paginée : elle peut couvrir toute la collection
+const DefaultIncompleteLimit = 50
+
+// MissingFilter retourne le filtre MongoDB des recettes dont le champ est absent ou vide (?missing=)
+// Tableaux (ingredients, instructions) : absents, null ou vides ; image : absente, null ou "" ;
+// nutrition : absente, null ou sans aucune valeur ; rating : absente, null ou sans avis (count à 0)
+func MissingFilter(field string) (bson.M, error) {
+	switch field {
+	case MissingIngredients, MissingInstructions:
+		return emptyArrayFilter(field), nil
+	case MissingImage:
+		return bson.M{"$or": bson.A{
+			bson.M{"image": bson.M{"$exists": false}},
+			bson.M{"image": nil},
+			bson.M{"image": ""},
+		}}, nil
+	case MissingNutrition:
+		return bson.M{"$or": bson.A{
+			bson.M{"nutrition": bson.M{"$exists": false}},
+			bson.M{"nutrition": nil},
+			bson.M{"nutrition": bson.M{}},
+		}}, nil
+	case MissingRating:
+		return bson.M{"$or": bson.A{
+			bson.M{"rating": bson.M{"$exists": false}},
+			bson.M{"rating": nil},
+			bson.M{"rating.count": 0},
+		}}, nil
+	case "":
+		return nil, fmt.Errorf("paramètre missing requis: %q, %q, %q, %q ou %q attendu",
+			MissingIngredients, MissingInstructions, MissingImage, MissingNutrition, MissingRating)
+	default:
+		return nil, fmt.Errorf("valeur de missing invalide %q: %q, %q, %q, %q ou %q attendu", field,
+			MissingIngredients, MissingInstructions, MissingImage, MissingNutrition, MissingRating)
+	}
+}
+
+// emptyArrayFilter retourne le filtre des documents dont le tableau field est absent, null ou vide
+func emptyArrayFilter(field string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$exists": false}},
+		bson.M{field: nil},
+		bson.M{field: bson.M{"$size": 0}},
+	}}
+}
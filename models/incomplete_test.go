@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Chaque champ est manquant s'il est absent, null ou vide ; l'exécution sur un vrai mongod est couverte
+// par les tests d'intégration (go test -tags integration)
+func TestMissingFilter(t *testing.T) {
+	for field, empty := range map[string]bson.M{
+		MissingIngredients:  {"ingredients": bson.M{"$size": 0}},
+		MissingInstructions: {"instructions": bson.M{"$size": 0}},
+		MissingImage:        {"image": ""},
+		MissingNutrition:    {"nutrition": bson.M{}},
+		MissingRating:       {"rating.count": 0},
+	} {
+		filter, err := MissingFilter(field)
+		require.NoError(t, err, field)
+		assert.ElementsMatch(t, bson.A{
+			bson.M{field: bson.M{"$exists": false}},
+			bson.M{field: nil},
+			empty,
+		}, filter["$or"], field)
+	}
+}
+
+// Les recettes incomplètes sont paginées comme les autres listes
+func TestMissingFilterPagination(t *testing.T) {
+	filter, err := MissingFilter(MissingImage)
+	require.NoError(t, err)
+	pipeline := ListQuery{Fields: FieldsSummary, Sort: SortName, Page: 2, Limit: 1}.Pipeline(filter)
+
+	match, _ := stage(pipeline, "$match")
+	assert.Equal(t, filter, match)
+	skip, _ := stage(pipeline, "$skip")
+	assert.Equal(t, int64(1), skip)
+	limit, _ := stage(pipeline, "$limit")
+	assert.Equal(t, int64(1), limit)
+}
+
+func TestMissingFilterInvalid(t *testing.T) {
+	for _, value := range []string{"", "category", "Image"} {
+		_, err := MissingFilter(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// unitWords liste les unités retirées des noms d'ingrédients normalisés
+var unitWords = map[string]bool{
+	"cup": true, "cups": true, "c": true,
+	"tablespoon": true, "tablespoons": true, "tbsp": true, "tbs": true,
+	"teaspoon": true, "teaspoons": true, "tsp": true,
+	"ounce": true, "ounces": true, "oz": true, "fluid": true,
+	"pound": true, "pounds": true, "lb": true, "lbs": true,
+	"gram": true, "grams": true, "g": true, "kg": true, "kilogram": true, "kilograms": true,
+	"ml": true, "milliliter": true, "milliliters": true, "l": true, "liter": true, "liters": true,
+	"quart": true, "quarts": true, "pint": true, "pints": true, "gallon": true, "gallons": true,
+	"pinch": true, "pinches": true, "dash": true, "dashes": true,
+	"can": true, "cans": true, "package": true, "packages": true, "jar": true, "jars": true,
+	"of": true,
+}
+
+// invariantWords ne sont pas singularisés (terminaisons en s qui ne marquent pas un pluriel)
+var invariantWords = map[string]bool{
+	"asparagus": true, "couscous": true, "hummus": true, "molasses": true,
+	"swiss": true, "grits": true, "citrus": true,
+}
+
+var (
+	parenthesesPattern = regexp.MustCompile(`\([^)]*\)`)
+	quantityPattern    = regexp.MustCompile(`^[0-9¼½¾⅓⅔⅛./-]+$`)
+)
+
+// NormalizeIngredientName normalise un nom d'ingrédient pour la recherche
+// Minuscules, espaces réduits, quantités, unités et précisions (après la virgule) retirées, mots au singulier
+// Exemple : "1 (14.5 ounce) can Diced Tomatoes, drained" → "diced tomato"
+func NormalizeIngredientName(name string) string {
+	name = strings.ToLower(name)
+	name = parenthesesPattern.ReplaceAllString(name, " ")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(name) {
+		if quantityPattern.MatchString(word) || unitWords[word] {
+			continue
+		}
+		words = append(words, singularize(word))
+	}
+	return strings.Join(words, " ")
+}
+
+// singularize met un mot anglais au singulier avec des règles simples (tomatoes → tomato, berries → berry)
+func singularize(word string) string {
+	if invariantWords[word] || len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "oes"),
+		strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// NormalizeIngredients renseigne NameNormalized des ingrédients qui ne l'ont pas
+// À défaut de nom, le texte complet (Quantity) est normalisé
+func NormalizeIngredients(r *Recette) {
+	for i := range r.Ingredients {
+		ingredient := &r.Ingredients[i]
+		if ingredient.NameNormalized != "" {
+			continue
+		}
+		source := ingredient.Name
+		if source == "" {
+			source = ingredient.Quantity
+		}
+		ingredient.NameNormalized = NormalizeIngredientName(source)
+	}
+}
+
+// IngredientFilter construit le filtre des recettes contenant un ingrédient
+// Le terme est normalisé comme les noms stockés ("Tomatoes" trouve "diced tomato")
+// L'ancien critère sur unit est conservé pour les recettes importées avant la normalisation
+func IngredientFilter(term string) bson.M {
+	conditions := bson.A{bson.M{"ingredients.unit": term}}
+	if normalized := NormalizeIngredientName(term); normalized != "" {
+		conditions = append(conditions, bson.M{"ingredients.nameNormalized": primitive.Regex{
+			Pattern: `\b` + regexp.QuoteMeta(normalized) + `\b`,
+		}})
+	}
+	return bson.M{"$or": conditions}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeIngredientName(t *testing.T) {
+	cases := map[string]string{
+		"tomatoes":          "tomato",
+		"  Tomatoes  ":      "tomato",
+		"TOMATO":            "tomato",
+		"Cherry   Tomatoes": "cherry tomato",
+		"berries":           "berry",
+		"peaches":           "peach",
+		"eggs":              "egg",
+		"2 cups flour":      "flour",
+		"1/2 teaspoon salt": "salt",
+		"½ cup sugar":       "sugar",
+		"onions, chopped":   "onion",
+		"asparagus":         "asparagus",
+		"molasses":          "molasses",
+		"glass":             "glass",
+		"":                  "",
+		"1 (14.5 ounce) can diced tomatoes, drained": "diced tomato",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, NormalizeIngredientName(input), input)
+	}
+}
+
+// Le nom affiché est conservé, la forme normalisée est ajoutée à côté
+func TestNormalizeIngredients(t *testing.T) {
+	recette := Recette{Ingredients: []Ingredient{
+		{Quantity: "2 Roma Tomatoes", Name: "Roma Tomatoes"},
+		{Quantity: "3 cups chicken stock"},
+		{Quantity: "1 onion", Name: "onion", NameNormalized: "yellow onion"},
+	}}
+	NormalizeIngredients(&recette)
+
+	assert.Equal(t, "Roma Tomatoes", recette.Ingredients[0].Name)
+	assert.Equal(t, "roma tomato", recette.Ingredients[0].NameNormalized)
+	assert.Equal(t, "chicken stock", recette.Ingredients[1].NameNormalized)
+	assert.Equal(t, "yellow onion", recette.Ingredients[2].NameNormalized)
+}
+
+func TestIngredientFilter(t *testing.T) {
+	filter := IngredientFilter("Tomatoes")
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"ingredients.unit": "Tomatoes"},
+		bson.M{"ingredients.nameNormalized": primitive.Regex{Pattern: `\btomato\b`}},
+	}}, filter)
+
+	// Les caractères spéciaux du terme ne sont pas interprétés comme une expression régulière
+	filter = IngredientFilter("a+b")
+	assert.Equal(t, primitive.Regex{Pattern: `\ba\+b\b`}, filter["$or"].(bson.A)[1].(bson.M)["ingredients.nameNormalized"])
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NameIndexName est le nom de l'index sur name, créé au démarrage de l'API
+const NameIndexName = "name_ci"
+
+// NameCollation compare les noms sans tenir compte de la casse ("Chicken Soup" = "chicken soup"),
+// accents compris. Les requêtes doivent utiliser exactement cette collation pour que MongoDB utilise l'index
+func NameCollation() *options.Collation {
+	return &options.Collation{Locale: "en", Strength: 2}
+}
+
+// NameIndexModel décrit l'index sur name, avec la collation des recherches par nom et du tri ?sort=name
+func NameIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName(NameIndexName).SetCollation(NameCollation()),
+	}
+}
+
+// NameLookup construit la recherche exacte d'une recette par nom, insensible à la casse
+// Les espaces autour du nom sont ignorés ; la première recette trouvée est retournée
+func NameLookup(name string) (bson.M, *options.FindOneOptions) {
+	filter := bson.M{"name": strings.TrimSpace(name)}
+	return filter, options.FindOne().SetCollation(NameCollation())
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNameLookupIgnoresCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Chicken Soup", "Chicken Soup"},
+		{"chicken soup", "chicken soup"},
+		{"CHICKEN SOUP", "CHICKEN SOUP"},
+		{"  cHiCkEn SoUp ", "cHiCkEn SoUp"},
+	}
+	for _, tt := range tests {
+		filter, findOptions := NameLookup(tt.name)
+
+		// Le nom est cherché tel quel : c'est la collation (strength 2) qui ignore la casse
+		assert.Equal(t, bson.M{"name": tt.want}, filter, tt.name)
+		require.NotNil(t, findOptions.Collation, tt.name)
+		assert.Equal(t, "en", findOptions.Collation.Locale)
+		assert.Equal(t, 2, findOptions.Collation.Strength)
+	}
+}
+
+func TestNameLookupUsesIndexCollation(t *testing.T) {
+	// MongoDB n'utilise l'index que si la requête a exactement la même collation
+	index := NameIndexModel()
+	assert.Equal(t, bson.D{{Key: "name", Value: 1}}, index.Keys)
+	require.NotNil(t, index.Options)
+	assert.Equal(t, NameIndexName, *index.Options.Name)
+
+	_, findOptions := NameLookup("Chicken Soup")
+	assert.Equal(t, *index.Options.Collation, *findOptions.Collation)
+	assert.Equal(t, *index.Options.Collation, *ListQuery{Sort: SortName}.Collation())
+}
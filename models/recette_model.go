@@ -1,19 +1,59 @@
 package models
 
-type Recette struct {
-	Name         string        `json:"name" swagger:"description(Nom de la recette)"`
-	Page         string        `json:"page" swagger:"description(URL de la page de la recette)"`
-	Image        string        `json:"image" swagger:"description(URL de l'image de la recette)"`
-	Ingredients  []Ingredient  `json:"ingredients" swagger:"description(Liste des ingrédients de la recette)"`
-	Instructions []Instruction `json:"Instructions" swagger:"description(Liste des instructions de la recette)"`
+import (
+	"fmt"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Niveaux de détail disponibles pour les listes de recettes (?fields=)
+const (
+	FieldsSummary = "summary" // id, name, page, image, ingredientCount
+	FieldsFull    = "full"    // Document complet, instructions comprises
+)
+
+// ParseFields valide le paramètre ?fields= (vide = summary par défaut)
+func ParseFields(value string) (string, error) {
+	switch value {
+	case "", FieldsSummary:
+		return FieldsSummary, nil
+	case FieldsFull:
+		return FieldsFull, nil
+	default:
+		return "", fmt.Errorf("valeur de fields invalide %q: %q ou %q attendu", value, FieldsSummary, FieldsFull)
+	}
 }
 
-type Ingredient struct {
-	Quantity string `json:"quantity" swagger:"description(Quantité de l'ingrédient)"`
-	Unit     string `json:"unit" swagger:"description(Unité de mesure de l'ingrédient)"`
+// Recette est le modèle partagé avec le scraper (package recipe) : mêmes champs json et bson des deux côtés
+type Recette = recipe.Recipe
+
+// Ingredient est un ingrédient de Recette
+type Ingredient = recipe.Ingredient
+
+// Instruction est une étape de Recette
+type Instruction = recipe.Instruction
+
+// RecetteSummary est la vue allégée d'une recette renvoyée par défaut par les listes
+type RecetteSummary struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id"`
+	Name             string             `json:"name" bson:"name"`
+	Page             string             `json:"page" bson:"page"`
+	Image            string             `json:"image" bson:"image"`
+	IngredientCount  int                `json:"ingredientCount" bson:"ingredientCount"`
+	TotalTimeMinutes int                `json:"totalTimeMinutes,omitempty" bson:"totalTimeMinutes,omitempty"`
 }
 
-type Instruction struct {
-	Number      string `json:"number" swagger:"description(Numéro de l'instruction)"`
-	Description string `json:"description" swagger:"description(Description de l'instruction)"`
+// SummaryProjection est la projection MongoDB produisant un RecetteSummary
+// Le nombre d'ingrédients est calculé côté serveur, les instructions ne sont jamais lues
+var SummaryProjection = bson.M{
+	"_id":              1,
+	"name":             1,
+	"page":             1,
+	"image":            1,
+	"totalTimeMinutes": 1,
+	"ingredientCount": bson.M{
+		"$size": bson.M{"$ifNull": bson.A{"$ingredients", bson.A{}}},
+	},
 }
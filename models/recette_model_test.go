@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Test de la validation du paramètre ?fields=
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("")
+	require.NoError(t, err)
+	assert.Equal(t, FieldsSummary, fields)
+
+	fields, err = ParseFields("full")
+	require.NoError(t, err)
+	assert.Equal(t, FieldsFull, fields)
+
+	_, err = ParseFields("all")
+	assert.Error(t, err)
+}
+
+// Test que la projection summary ne lit pas les instructions
+func TestSummaryProjectionOmitsInstructions(t *testing.T) {
+	assert.NotContains(t, SummaryProjection, "Instructions")
+	assert.NotContains(t, SummaryProjection, "instructions")
+	assert.NotContains(t, SummaryProjection, "ingredients")
+}
+
+// Test qu'un document projeté se décode en summary sans instructions
+func TestRecetteSummaryResponseOmitsInstructions(t *testing.T) {
+	id := primitive.NewObjectID()
+	raw, err := bson.Marshal(bson.M{
+		"_id":             id,
+		"name":            "Soupe",
+		"page":            "https://example.com/soupe",
+		"image":           "https://example.com/soupe.jpg",
+		"ingredientCount": 3,
+	})
+	require.NoError(t, err)
+
+	var summary RecetteSummary
+	require.NoError(t, bson.Unmarshal(raw, &summary))
+	assert.Equal(t, id, summary.ID)
+	assert.Equal(t, 3, summary.IngredientCount)
+
+	body, err := json.Marshal(summary)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.ElementsMatch(t, []string{"id", "name", "page", "image", "ingredientCount"}, keys(response))
+	assert.NotContains(t, response, "Instructions")
+	assert.NotContains(t, response, "ingredients")
+}
+
+func keys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
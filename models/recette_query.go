@@ -0,0 +1,169 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Clés de tri acceptées par ?sort=
+const (
+	SortName        = "name"        // Ordre alphabétique, insensible à la casse (collation)
+	SortRecent      = "recent"      // Date d'insertion (createdAt)
+	SortIngredients = "ingredients" // Nombre d'ingrédients ($size)
+)
+
+// MaxListLimit borne le nombre de recettes renvoyées par page
+const MaxListLimit = 100
+
+// ListParams contient les paramètres bruts d'une requête de liste
+type ListParams struct {
+	Fields string // ?fields=summary|full
+	Sort   string // ?sort=name|recent|ingredients
+	Order  string // ?order=asc|desc
+	Page   string // ?page= (à partir de 1)
+	Limit  string // ?limit= (0 ou absent = pas de pagination)
+
+	MaxTotalMinutes string // ?max_total_minutes= (recettes dont le temps total est inférieur ou égal)
+}
+
+// ListQuery est la version validée de ListParams
+type ListQuery struct {
+	Fields     string
+	Sort       string // Vide = ordre naturel
+	Descending bool
+	Page       int
+	Limit      int
+
+	MaxTotalMinutes int // 0 = pas de filtre sur le temps
+}
+
+// Parse valide les paramètres et applique les valeurs par défaut
+// Par défaut : summary, ordre naturel, ordre décroissant pour recent, croissant sinon
+func (p ListParams) Parse() (ListQuery, error) {
+	q := ListQuery{Page: 1}
+
+	fields, err := ParseFields(p.Fields)
+	if err != nil {
+		return q, err
+	}
+	q.Fields = fields
+
+	switch p.Sort {
+	case "", SortName, SortRecent, SortIngredients:
+		q.Sort = p.Sort
+	default:
+		return q, fmt.Errorf("clé de tri invalide %q: %q, %q ou %q attendu", p.Sort, SortName, SortRecent, SortIngredients)
+	}
+
+	switch p.Order {
+	case "":
+		q.Descending = q.Sort == SortRecent
+	case "asc":
+		q.Descending = false
+	case "desc":
+		q.Descending = true
+	default:
+		return q, fmt.Errorf("ordre de tri invalide %q: \"asc\" ou \"desc\" attendu", p.Order)
+	}
+
+	if p.Page != "" {
+		page, err := strconv.Atoi(p.Page)
+		if err != nil || page < 1 {
+			return q, fmt.Errorf("page invalide %q: entier >= 1 attendu", p.Page)
+		}
+		q.Page = page
+	}
+
+	if p.Limit != "" {
+		limit, err := strconv.Atoi(p.Limit)
+		if err != nil || limit < 0 || limit > MaxListLimit {
+			return q, fmt.Errorf("limit invalide %q: entier entre 0 et %d attendu", p.Limit, MaxListLimit)
+		}
+		q.Limit = limit
+	}
+
+	if p.MaxTotalMinutes != "" {
+		minutes, err := strconv.Atoi(p.MaxTotalMinutes)
+		if err != nil || minutes < 1 {
+			return q, fmt.Errorf("max_total_minutes invalide %q: entier >= 1 attendu", p.MaxTotalMinutes)
+		}
+		q.MaxTotalMinutes = minutes
+	}
+
+	return q, nil
+}
+
+// Pipeline construit le pipeline d'agrégation MongoDB correspondant à la requête
+func (q ListQuery) Pipeline(filter bson.M) []bson.D {
+	direction := 1
+	if q.Descending {
+		direction = -1
+	}
+
+	// Les recettes sans temps total connu sont exclues par $lte
+	if q.MaxTotalMinutes > 0 {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"totalTimeMinutes": bson.M{"$lte": q.MaxTotalMinutes}}}}
+	}
+
+	pipeline := []bson.D{{{Key: "$match", Value: filter}}}
+
+	// Le tri par nombre d'ingrédients nécessite un champ calculé
+	if q.Sort == SortIngredients {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+			"ingredientCount": SummaryProjection["ingredientCount"],
+		}}})
+	}
+
+	// _id en second critère pour un ordre stable entre les pages
+	switch q.Sort {
+	case SortName:
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "name", Value: direction}, {Key: "_id", Value: direction}}}})
+	case SortRecent:
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: direction}, {Key: "_id", Value: direction}}}})
+	case SortIngredients:
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "ingredientCount", Value: direction}, {Key: "_id", Value: direction}}}})
+	}
+
+	if q.Limit > 0 {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$skip", Value: int64((q.Page - 1) * q.Limit)}},
+			bson.D{{Key: "$limit", Value: int64(q.Limit)}},
+		)
+	}
+
+	if q.Fields == FieldsSummary {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: SummaryProjection}})
+	} else if q.Sort == SortIngredients {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{"ingredientCount": 0}}})
+	}
+
+	return pipeline
+}
+
+// Collation retourne la collation à appliquer (tri par nom insensible à la casse), nil sinon
+func (q ListQuery) Collation() *options.Collation {
+	if q.Sort != SortName {
+		return nil
+	}
+	return NameCollation() // Même collation que l'index sur name
+}
+
+// ParseRandomCount valide le paramètre ?count= de /recettes/random (vide = 1)
+func ParseRandomCount(value string) (int, error) {
+	if value == "" {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 || count > MaxListLimit {
+		return 0, fmt.Errorf("count invalide %q: entier entre 1 et %d attendu", value, MaxListLimit)
+	}
+	return count, nil
+}
+
+// RandomPipeline tire count recettes au hasard avec $sample, sans charger toute la collection
+func RandomPipeline(count int) []bson.D {
+	return []bson.D{{{Key: "$sample", Value: bson.M{"size": count}}}}
+}
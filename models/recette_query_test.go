@@ -0,0 +1,154 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// stage retourne la première étape du pipeline portant l'opérateur donné
+func stage(pipeline []bson.D, operator string) (interface{}, bool) {
+	for _, s := range pipeline {
+		if s[0].Key == operator {
+			return s[0].Value, true
+		}
+	}
+	return nil, false
+}
+
+// stageOperators retourne les opérateurs des étapes du pipeline, dans l'ordre
+func stageOperators(pipeline []bson.D) []string {
+	operators := make([]string, 0, len(pipeline))
+	for _, s := range pipeline {
+		operators = append(operators, s[0].Key)
+	}
+	return operators
+}
+
+// Test du tri pour chaque clé et chaque direction
+func TestListQuerySort(t *testing.T) {
+	cases := []struct {
+		sort, order string
+		field       string
+		direction   int
+	}{
+		{"name", "asc", "name", 1},
+		{"name", "desc", "name", -1},
+		{"recent", "asc", "createdAt", 1},
+		{"recent", "desc", "createdAt", -1},
+		{"ingredients", "asc", "ingredientCount", 1},
+		{"ingredients", "desc", "ingredientCount", -1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.sort+"_"+tc.order, func(t *testing.T) {
+			query, err := ListParams{Sort: tc.sort, Order: tc.order}.Parse()
+			require.NoError(t, err)
+
+			sortStage, ok := stage(query.Pipeline(bson.M{}), "$sort")
+			require.True(t, ok)
+			assert.Equal(t, bson.D{
+				{Key: tc.field, Value: tc.direction},
+				{Key: "_id", Value: tc.direction},
+			}, sortStage)
+
+			_, hasCount := stage(query.Pipeline(bson.M{}), "$addFields")
+			assert.Equal(t, tc.sort == SortIngredients, hasCount)
+			assert.Equal(t, tc.sort == SortName, query.Collation() != nil)
+		})
+	}
+}
+
+// Test des directions par défaut
+func TestListQueryDefaultOrder(t *testing.T) {
+	query, err := ListParams{Sort: "recent"}.Parse()
+	require.NoError(t, err)
+	assert.True(t, query.Descending)
+
+	query, err = ListParams{Sort: "name"}.Parse()
+	require.NoError(t, err)
+	assert.False(t, query.Descending)
+
+	// Sans tri : ordre naturel
+	query, err = ListParams{}.Parse()
+	require.NoError(t, err)
+	_, ok := stage(query.Pipeline(bson.M{}), "$sort")
+	assert.False(t, ok)
+}
+
+// Test du rejet des paramètres invalides
+func TestListQueryInvalidParams(t *testing.T) {
+	for _, params := range []ListParams{
+		{Sort: "rating"},
+		{Order: "up"},
+		{Page: "0"},
+		{Limit: "abc"},
+		{Limit: "1000"},
+		{Fields: "all"},
+	} {
+		_, err := params.Parse()
+		assert.Error(t, err, "%+v", params)
+	}
+}
+
+// Test de la combinaison tri + pagination
+func TestListQueryPagination(t *testing.T) {
+	query, err := ListParams{Sort: "name", Page: "3", Limit: "20"}.Parse()
+	require.NoError(t, err)
+
+	pipeline := query.Pipeline(bson.M{})
+	skip, ok := stage(pipeline, "$skip")
+	require.True(t, ok)
+	assert.Equal(t, int64(40), skip)
+	limit, ok := stage(pipeline, "$limit")
+	require.True(t, ok)
+	assert.Equal(t, int64(20), limit)
+
+	// Le tri précède la pagination, la projection summary vient en dernier
+	assert.Equal(t, "$sort", pipeline[1][0].Key)
+	assert.Equal(t, "$project", pipeline[len(pipeline)-1][0].Key)
+}
+
+// Test du paramètre count et du pipeline $sample
+func TestRandomPipeline(t *testing.T) {
+	count, err := ParseRandomCount("")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = ParseRandomCount("5")
+	require.NoError(t, err)
+	assert.Equal(t, []bson.D{{{Key: "$sample", Value: bson.M{"size": 5}}}}, RandomPipeline(count))
+
+	for _, value := range []string{"0", "-1", "abc", "101"} {
+		_, err := ParseRandomCount(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// Test du filtre sur le temps total (?max_total_minutes=)
+func TestListQueryMaxTotalMinutes(t *testing.T) {
+	query, err := ListParams{MaxTotalMinutes: "30", Sort: "name", Limit: "10"}.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, 30, query.MaxTotalMinutes)
+
+	// Le filtre d'origine est combiné au critère $lte, avant le tri et la pagination
+	filter := bson.M{"name": "Soupe"}
+	pipeline := query.Pipeline(filter)
+	match, ok := stage(pipeline, "$match")
+	require.True(t, ok)
+	assert.Equal(t, bson.M{"$and": bson.A{filter, bson.M{"totalTimeMinutes": bson.M{"$lte": 30}}}}, match)
+	assert.Equal(t, "$sort", pipeline[1][0].Key)
+
+	// Sans le paramètre, le filtre est inchangé
+	query, err = ListParams{}.Parse()
+	require.NoError(t, err)
+	match, _ = stage(query.Pipeline(filter), "$match")
+	assert.Equal(t, filter, match)
+
+	for _, value := range []string{"0", "-5", "abc", "1.5"} {
+		_, err := ListParams{MaxTotalMinutes: value}.Parse()
+		assert.Error(t, err, value)
+	}
+}
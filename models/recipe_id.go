@@ -0,0 +1,19 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecipeIDIndexName est le nom de l'index sur recipeId, créé au démarrage de l'API
+const RecipeIDIndexName = "recipe_id"
+
+// RecipeIDIndexModel décrit l'index sur recipeId, clé des upserts (non unique : les recettes
+// enregistrées avant l'identifiant n'en ont pas)
+func RecipeIDIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: "recipeId", Value: 1}},
+		Options: options.Index().SetName(RecipeIDIndexName),
+	}
+}
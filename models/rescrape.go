@@ -0,0 +1,113 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Bornes de POST /scraper/rescrape : chaque URL lance un processus scraper, la liste est donc limitée
+const (
+	MaxRescrapeURLs     = 50
+	RescrapeConcurrency = 4 // Scrapes simultanés au plus
+)
+
+// Statuts d'une URL dans la réponse de POST /scraper/rescrape
+const (
+	RescrapeSucceeded = "ok"
+	RescrapeFailed    = "failed"
+)
+
+// RescrapeRequest est le corps attendu par POST /scraper/rescrape
+type RescrapeRequest struct {
+	URLs []string `json:"urls"` // Pages de recettes AllRecipes à scraper de nouveau
+}
+
+// Validate vérifie chaque URL (http(s), hôte de CategoryHosts) et le nombre d'URLs, puis retire les doublons
+func (r *RescrapeRequest) Validate() error {
+	seen := make(map[string]bool, len(r.URLs))
+	urls := make([]string, 0, len(r.URLs))
+	for _, raw := range r.URLs {
+		raw = strings.TrimSpace(raw)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("URL invalide %q: URL http(s) absolue attendue", raw)
+		}
+		if !allowedCategoryHost(u.Hostname()) {
+			return fmt.Errorf("hôte non pris en charge %q: %s attendu", u.Hostname(), strings.Join(CategoryHosts, " ou "))
+		}
+		if !seen[raw] {
+			seen[raw] = true
+			urls = append(urls, raw)
+		}
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("liste urls vide: au moins une URL attendue")
+	}
+	if len(urls) > MaxRescrapeURLs {
+		return fmt.Errorf("trop d'URLs (%d): %d au plus", len(urls), MaxRescrapeURLs)
+	}
+	r.URLs = urls
+	return nil
+}
+
+// RescrapeResult est le résultat du scrape d'une URL ; Recette n'est renseignée qu'en cas de succès
+type RescrapeResult struct {
+	URL     string   `json:"url"`
+	Status  string   `json:"status"` // ok ou failed
+	Error   string   `json:"error,omitempty"`
+	Recette *Recette `json:"-"` // Recette à enregistrer, non renvoyée (déjà en base après l'upsert)
+}
+
+// RescrapeFunc scrape une recette (ScrapeSingleRecipe côté contrôleur, pages enregistrées dans les tests)
+type RescrapeFunc func(ctx context.Context, recipeURL string) (Recette, error)
+
+// RescrapeURLs scrape les URLs avec au plus concurrency scrapes simultanés et retourne un résultat par URL,
+// dans l'ordre de urls ; une recette invalide (ValidateRecette) compte comme un échec
+// Une URL non commencée quand ctx est annulé est en échec avec l'erreur du contexte
+func RescrapeURLs(ctx context.Context, urls []string, concurrency int, scrape RescrapeFunc) []RescrapeResult {
+	results := make([]RescrapeResult, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(urls); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = rescrapeOne(ctx, urls[i], scrape)
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// rescrapeOne scrape et valide une recette ; la page scrapée est celle demandée si le scraper ne la renseigne pas
+func rescrapeOne(ctx context.Context, recipeURL string, scrape RescrapeFunc) RescrapeResult {
+	result := RescrapeResult{URL: recipeURL, Status: RescrapeFailed}
+	if err := ctx.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	recette, err := scrape(ctx, recipeURL)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if recette.Page == "" {
+		recette.Page = recipeURL
+	}
+	if err := ValidateRecette(recette); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = RescrapeSucceeded
+	result.Recette = &recette
+	return result
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescrapeRequestValidate(t *testing.T) {
+	request := RescrapeRequest{URLs: []string{
+		"https://www.allrecipes.com/recipe/1/soupe/",
+		" https://www.allrecipes.com/recipe/1/soupe/ ",
+		"https://AllRecipes.com/recipe/2/gratin/",
+	}}
+	require.NoError(t, request.Validate())
+	assert.Equal(t, []string{"https://www.allrecipes.com/recipe/1/soupe/", "https://AllRecipes.com/recipe/2/gratin/"}, request.URLs)
+
+	tooMany := RescrapeRequest{}
+	for i := 0; i <= MaxRescrapeURLs; i++ {
+		tooMany.URLs = append(tooMany.URLs, fmt.Sprintf("https://www.allrecipes.com/recipe/%d/", i))
+	}
+
+	for _, invalid := range []RescrapeRequest{
+		{},
+		{URLs: []string{}},
+		{URLs: []string{"/recipe/1/soupe/"}},
+		{URLs: []string{"https://www.allrecipes.com/recipe/1/", "ftp://www.allrecipes.com/recipe/2/"}},
+		{URLs: []string{"https://evil.example.com/recipe/1/"}},
+		{URLs: []string{"https://www.allrecipes.com.evil.example/recipe/1/"}},
+		tooMany,
+	} {
+		assert.Error(t, invalid.Validate(), "%v", invalid.URLs)
+	}
+}
+
+// rescrapeFixtures sont les recettes renvoyées par le scraper simulé, par URL ; une URL absente est en échec
+var rescrapeFixtures = map[string]Recette{
+	"https://www.allrecipes.com/recipe/1/soupe/": {
+		Name:        "Soupe de légumes",
+		Page:        "https://www.allrecipes.com/recipe/1/soupe/",
+		Ingredients: []Ingredient{{Quantity: "2", Name: "carottes"}},
+	},
+	// Page non renseignée par le scraper : l'URL demandée est utilisée
+	"https://www.allrecipes.com/recipe/2/gratin/": {
+		Name:        "Gratin",
+		Ingredients: []Ingredient{{Quantity: "1 kg", Name: "pommes de terre"}},
+	},
+	// Extraction vide : refusée par la validation
+	"https://www.allrecipes.com/recipe/3/vide/": {
+		Name: "Vide",
+		Page: "https://www.allrecipes.com/recipe/3/vide/",
+	},
+}
+
+func fixtureScraper(ctx context.Context, recipeURL string) (Recette, error) {
+	recette, ok := rescrapeFixtures[recipeURL]
+	if !ok {
+		return Recette{}, errors.New("exit status 1: 404 Not Found")
+	}
+	return recette, nil
+}
+
+// Succès et échecs sont rapportés par URL, dans l'ordre de la requête
+func TestRescrapeURLs(t *testing.T) {
+	urls := []string{
+		"https://www.allrecipes.com/recipe/1/soupe/",
+		"https://www.allrecipes.com/recipe/404/absente/",
+		"https://www.allrecipes.com/recipe/2/gratin/",
+		"https://www.allrecipes.com/recipe/3/vide/",
+	}
+	results := RescrapeURLs(context.Background(), urls, 2, fixtureScraper)
+	require.Len(t, results, len(urls))
+
+	for i, result := range results {
+		assert.Equal(t, urls[i], result.URL)
+	}
+
+	assert.Equal(t, RescrapeSucceeded, results[0].Status)
+	require.NotNil(t, results[0].Recette)
+	assert.Equal(t, "Soupe de légumes", results[0].Recette.Name)
+	assert.Empty(t, results[0].Error)
+
+	assert.Equal(t, RescrapeFailed, results[1].Status)
+	assert.Nil(t, results[1].Recette)
+	assert.Contains(t, results[1].Error, "404")
+
+	assert.Equal(t, RescrapeSucceeded, results[2].Status)
+	require.NotNil(t, results[2].Recette)
+	assert.Equal(t, urls[2], results[2].Recette.Page)
+
+	assert.Equal(t, RescrapeFailed, results[3].Status)
+	assert.Nil(t, results[3].Recette)
+	assert.Contains(t, results[3].Error, "ingredients")
+}
+
+// Jamais plus de concurrency scrapes simultanés
+func TestRescrapeURLsBounded(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	scrape := func(ctx context.Context, recipeURL string) (Recette, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return fixtureScraper(ctx, recipeURL)
+	}
+
+	urls := make([]string, 12)
+	for i := range urls {
+		urls[i] = "https://www.allrecipes.com/recipe/1/soupe/"
+	}
+	results := RescrapeURLs(context.Background(), urls, 3, scrape)
+	assert.Len(t, results, 12)
+	assert.LessOrEqual(t, peak, 3)
+	assert.Greater(t, peak, 1)
+}
+
+// Les URLs non commencées après l'annulation sont en échec sans appel au scraper
+func TestRescrapeURLsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	results := RescrapeURLs(ctx, []string{"https://www.allrecipes.com/recipe/1/soupe/"}, 1, func(ctx context.Context, recipeURL string) (Recette, error) {
+		calls++
+		return fixtureScraper(ctx, recipeURL)
+	})
+	assert.Zero(t, calls)
+	require.Len(t, results, 1)
+	assert.Equal(t, RescrapeFailed, results[0].Status)
+	assert.Contains(t, results[0].Error, context.Canceled.Error())
+}
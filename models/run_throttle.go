@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultScraperMinInterval est l'intervalle minimal entre deux exécutions lancées par l'API sans SCRAPER_MIN_INTERVAL
+const DefaultScraperMinInterval = 5 * time.Minute
+
+// ParseMinInterval valide SCRAPER_MIN_INTERVAL (durée Go, ex: 10m ; vide = DefaultScraperMinInterval, 0 = pas de limite)
+func ParseMinInterval(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultScraperMinInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("SCRAPER_MIN_INTERVAL invalide %q: durée positive ou nulle attendue (ex: 5m, 1h)", value)
+	}
+	return interval, nil
+}
+
+// RunTooSoonError est retournée quand une exécution est demandée avant la fin de l'intervalle minimal
+type RunTooSoonError struct {
+	Wait     time.Duration // Attente restante avant la prochaine exécution autorisée
+	Interval time.Duration
+}
+
+func (e *RunTooSoonError) Error() string {
+	return fmt.Sprintf("dernière exécution du scraper trop récente: nouvel essai possible dans %s (intervalle minimal %s)",
+		e.Wait.Round(time.Second), e.Interval)
+}
+
+// RetryAfterSeconds est l'attente restante arrondie à la seconde supérieure (en-tête Retry-After)
+func (e *RunTooSoonError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.Wait.Seconds()))
+}
+
+// RunThrottle garde la date de la dernière exécution du scraper et impose un intervalle minimal
+// entre deux exécutions demandées par l'API, pour ne pas surcharger le site scrapé par des appels répétés
+type RunThrottle struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastRun  time.Time // Zéro avant la première exécution
+	previous time.Time // Dernière exécution avant la réservation en cours (Release)
+}
+
+// NewRunThrottle crée un limiteur imposant interval entre deux exécutions (0 = pas de limite)
+func NewRunThrottle(interval time.Duration) *RunThrottle {
+	return &RunThrottle{interval: interval}
+}
+
+// Reserve enregistre une exécution à now si l'intervalle depuis la précédente est écoulé,
+// sinon retourne une *RunTooSoonError avec l'attente restante sans rien enregistrer
+func (t *RunThrottle) Reserve(now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.lastRun.IsZero() {
+		if wait := t.lastRun.Add(t.interval).Sub(now); wait > 0 {
+			return &RunTooSoonError{Wait: wait, Interval: t.interval}
+		}
+	}
+	t.previous, t.lastRun = t.lastRun, now
+	return nil
+}
+
+// Release annule la réservation faite à at (scraper qui n'a pas démarré) : la dernière exécution redevient
+// la précédente ; sans effet si une autre exécution a été enregistrée depuis
+func (t *RunThrottle) Release(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastRun.Equal(at) {
+		t.lastRun = t.previous
+	}
+}
+
+// Record enregistre une exécution à now sans vérifier l'intervalle (exécutions planifiées)
+func (t *RunThrottle) Record(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.previous, t.lastRun = t.lastRun, now
+}
+
+// LastRun retourne la date de la dernière exécution enregistrée (zéro s'il n'y en a pas eu)
+func (t *RunThrottle) LastRun() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastRun
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMinInterval(t *testing.T) {
+	interval, err := ParseMinInterval("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultScraperMinInterval, interval)
+
+	interval, err = ParseMinInterval("10m")
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, interval)
+
+	interval, err = ParseMinInterval("0")
+	require.NoError(t, err)
+	assert.Zero(t, interval)
+
+	for _, invalid := range []string{"5", "cinq minutes", "-1m"} {
+		_, err := ParseMinInterval(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// Une exécution demandée pendant l'intervalle est refusée avec l'attente restante, sans décaler la dernière exécution
+func TestRunThrottleWithinInterval(t *testing.T) {
+	throttle := NewRunThrottle(5 * time.Minute)
+	first := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	require.NoError(t, throttle.Reserve(first))
+	assert.Equal(t, first, throttle.LastRun())
+
+	err := throttle.Reserve(first.Add(90*time.Second + 200*time.Millisecond))
+	var tooSoon *RunTooSoonError
+	require.ErrorAs(t, err, &tooSoon)
+	assert.Equal(t, 3*time.Minute+29*time.Second+800*time.Millisecond, tooSoon.Wait)
+	assert.Equal(t, 5*time.Minute, tooSoon.Interval)
+	assert.Equal(t, 210, tooSoon.RetryAfterSeconds())
+	assert.Contains(t, err.Error(), "3m30s")
+	assert.Equal(t, first, throttle.LastRun())
+}
+
+// Une fois l'intervalle écoulé, l'exécution est acceptée et devient la dernière
+func TestRunThrottleAfterInterval(t *testing.T) {
+	throttle := NewRunThrottle(5 * time.Minute)
+	first := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	require.NoError(t, throttle.Reserve(first))
+
+	second := first.Add(5 * time.Minute)
+	require.NoError(t, throttle.Reserve(second))
+	assert.Equal(t, second, throttle.LastRun())
+
+	assert.Error(t, throttle.Reserve(second.Add(time.Minute)))
+}
+
+// Une exécution planifiée n'est pas limitée mais repousse les demandes de l'API
+func TestRunThrottleRecord(t *testing.T) {
+	throttle := NewRunThrottle(5 * time.Minute)
+	assert.True(t, throttle.LastRun().IsZero())
+
+	scheduled := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	throttle.Record(scheduled)
+	throttle.Record(scheduled.Add(time.Second))
+	assert.Equal(t, scheduled.Add(time.Second), throttle.LastRun())
+	assert.Error(t, throttle.Reserve(scheduled.Add(time.Minute)))
+}
+
+// Un intervalle nul n'impose aucune attente
+func TestRunThrottleDisabled(t *testing.T) {
+	throttle := NewRunThrottle(0)
+	now := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	require.NoError(t, throttle.Reserve(now))
+	require.NoError(t, throttle.Reserve(now))
+}
+
+// Une réservation annulée (scraper non démarré) ne bloque pas les demandes suivantes
+func TestRunThrottleRelease(t *testing.T) {
+	throttle := NewRunThrottle(5 * time.Minute)
+	first := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	require.NoError(t, throttle.Reserve(first))
+
+	second := first.Add(10 * time.Minute)
+	require.NoError(t, throttle.Reserve(second))
+	throttle.Release(second)
+	assert.Equal(t, first, throttle.LastRun())
+	require.NoError(t, throttle.Reserve(second.Add(time.Second)))
+
+	// Une exécution enregistrée depuis n'est pas effacée
+	third := second.Add(10 * time.Minute)
+	require.NoError(t, throttle.Reserve(third))
+	throttle.Record(third.Add(time.Second))
+	throttle.Release(third)
+	assert.Equal(t, third.Add(time.Second), throttle.LastRun())
+}
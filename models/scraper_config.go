@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// ScraperConfig est le fichier de configuration lu par le scraper au démarrage (scraper_config.json)
+// Les champs non modifiés par l'API (required_fields) sont conservés tels quels à l'enregistrement
+type ScraperConfig = scraperspec.Config
+
+// LoadScraperConfig lit la configuration du scraper (un fichier absent donne les catégories par défaut)
+func LoadScraperConfig(path string) (ScraperConfig, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ScraperConfig{Categories: append([]string(nil), scraperspec.DefaultCategories...)}, nil
+	}
+	if err != nil {
+		return ScraperConfig{}, err
+	}
+
+	var config ScraperConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return ScraperConfig{}, err
+	}
+	return config, nil
+}
+
+// SaveScraperConfig écrit la configuration via un fichier temporaire renommé,
+// pour que le scraper ne lise jamais un fichier à moitié écrit
+func SaveScraperConfig(path string, config ScraperConfig) error {
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// ValidateCategoryURLs vérifie les catégories envoyées à l'API et retourne la liste nettoyée
+// Chaque entrée doit être une URL http(s) absolue ; les doublons sont retirés en conservant l'ordre
+func ValidateCategoryURLs(categories []string) ([]string, error) {
+	if len(categories) == 0 {
+		return nil, errors.New("au moins une catégorie est requise")
+	}
+
+	seen := make(map[string]bool, len(categories))
+	cleaned := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		u, err := url.Parse(category)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("catégorie invalide %q: URL http(s) absolue attendue", category)
+		}
+		if seen[category] {
+			continue
+		}
+		seen[category] = true
+		cleaned = append(cleaned, category)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScraperConfigLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scraper", "scraper_config.json")
+
+	// Sans fichier, les catégories par défaut du scraper sont listées
+	config, err := LoadScraperConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, scraperspec.DefaultCategories, config.Categories)
+
+	// La mise à jour est persistée et relue telle quelle
+	updated := ScraperConfig{Categories: []string{"https://www.allrecipes.com/recipes/79/desserts/"}}
+	require.NoError(t, SaveScraperConfig(path, updated))
+	config, err = LoadScraperConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, updated, config)
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "le fichier temporaire doit être renommé")
+}
+
+func TestValidateCategoryURLs(t *testing.T) {
+	cleaned, err := ValidateCategoryURLs([]string{
+		" https://www.allrecipes.com/recipes/79/desserts/ ",
+		"http://example.com/soups",
+		"https://www.allrecipes.com/recipes/79/desserts/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://www.allrecipes.com/recipes/79/desserts/", "http://example.com/soups"}, cleaned)
+
+	invalid := [][]string{
+		nil,
+		{"/recipes/79/desserts/"},
+		{"ftp://example.com/recipes"},
+		{"https://"},
+		{"https://example.com/ok", "pas une url"},
+	}
+	for _, categories := range invalid {
+		_, err := ValidateCategoryURLs(categories)
+		assert.Error(t, err, "%v", categories)
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Statuts d'un job du scraper
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// MaxScraperJobs borne les jobs conservés en mémoire : au-delà, les plus anciens sont oubliés
+const MaxScraperJobs = 100
+
+// ScraperJob est une exécution du scraper lancée en arrière-plan par l'API
+type ScraperJob struct {
+	ID         string     `json:"id"`
+	RequestID  string     `json:"request_id"` // Requête API à l'origine du job, aussi loguée dans le séparateur de scraper.log
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// JobRegistry conserve les derniers jobs du scraper ; thread-safe
+type JobRegistry struct {
+	mu    sync.Mutex
+	max   int
+	jobs  map[string]*ScraperJob
+	order []string // IDs du plus ancien au plus récent
+}
+
+// NewJobRegistry crée un registre gardant au plus max jobs
+func NewJobRegistry(max int) *JobRegistry {
+	return &JobRegistry{max: max, jobs: make(map[string]*ScraperJob)}
+}
+
+// Start enregistre un job en cours pour la requête requestID et le retourne
+func (r *JobRegistry) Start(requestID string, now time.Time) ScraperJob {
+	job := &ScraperJob{ID: newJobID(), RequestID: requestID, Status: JobRunning, StartedAt: now}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = job
+	r.order = append(r.order, job.ID)
+	for len(r.order) > r.max {
+		delete(r.jobs, r.order[0])
+		r.order = r.order[1:]
+	}
+	return *job
+}
+
+// Finish marque le job terminé, en échec si err n'est pas nil ; sans effet si le job a été oublié
+func (r *JobRegistry) Finish(id string, err error, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return
+	}
+	job.FinishedAt = &now
+	job.Status = JobSucceeded
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+}
+
+// Get retourne une copie du job id
+func (r *JobRegistry) Get(id string) (ScraperJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return ScraperJob{}, false
+	}
+	return *job, true
+}
+
+// FindByRequestID retourne une copie du job le plus récent lancé par la requête requestID
+func (r *JobRegistry) FindByRequestID(requestID string) (ScraperJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.order) - 1; i >= 0; i-- {
+		if job := r.jobs[r.order[i]]; job.RequestID == requestID {
+			return *job, true
+		}
+	}
+	return ScraperJob{}, false
+}
+
+// newJobID génère un identifiant aléatoire de 16 caractères hexadécimaux
+func newJobID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
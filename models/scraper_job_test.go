@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Le job garde le request ID de la requête qui l'a lancé jusqu'à sa fin
+func TestJobRegistryRequestID(t *testing.T) {
+	registry := NewJobRegistry(10)
+	start := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+
+	job := registry.Start("68f381f9ce484519", start)
+	assert.Len(t, job.ID, 16)
+	assert.Equal(t, "68f381f9ce484519", job.RequestID)
+	assert.Equal(t, JobRunning, job.Status)
+
+	registry.Finish(job.ID, nil, start.Add(time.Minute))
+	got, ok := registry.Get(job.ID)
+	require.True(t, ok)
+	assert.Equal(t, "68f381f9ce484519", got.RequestID)
+	assert.Equal(t, JobSucceeded, got.Status)
+	require.NotNil(t, got.FinishedAt)
+	assert.Equal(t, start.Add(time.Minute), *got.FinishedAt)
+
+	failed := registry.Start("other-request", start)
+	registry.Finish(failed.ID, errors.New("exit status 1"), start)
+	got, _ = registry.Get(failed.ID)
+	assert.Equal(t, JobFailed, got.Status)
+	assert.Equal(t, "exit status 1", got.Error)
+}
+
+// Au-delà de la capacité, les jobs les plus anciens sont oubliés
+func TestJobRegistryEvictsOldest(t *testing.T) {
+	registry := NewJobRegistry(2)
+	now := time.Now()
+	first := registry.Start("a", now)
+	second := registry.Start("b", now)
+	third := registry.Start("c", now)
+
+	_, ok := registry.Get(first.ID)
+	assert.False(t, ok)
+	_, ok = registry.Get(second.ID)
+	assert.True(t, ok)
+	_, ok = registry.Get(third.ID)
+	assert.True(t, ok)
+
+	// Terminer un job oublié est sans effet
+	registry.Finish(first.ID, nil, now)
+	_, ok = registry.Get(first.ID)
+	assert.False(t, ok)
+}
+
+// Un job se retrouve par le request ID de la requête qui l'a lancé, le plus récent en premier
+func TestJobRegistryFindByRequestID(t *testing.T) {
+	registry := NewJobRegistry(10)
+	now := time.Now()
+	registry.Start("68f381f9ce484519", now)
+	other := registry.Start("other-request", now)
+	latest := registry.Start("68f381f9ce484519", now)
+
+	got, ok := registry.FindByRequestID("68f381f9ce484519")
+	require.True(t, ok)
+	assert.Equal(t, latest.ID, got.ID)
+
+	got, ok = registry.FindByRequestID("other-request")
+	require.True(t, ok)
+	assert.Equal(t, other.ID, got.ID)
+
+	_, ok = registry.FindByRequestID("inconnu")
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// ScraperStatusStaleAfter est le délai sans mise à jour au-delà duquel une exécution « en cours » est
+// considérée comme interrompue (le scraper réécrit status.json chaque seconde)
+const ScraperStatusStaleAfter = 30 * time.Second
+
+// ScraperStatus est la progression écrite par le scraper dans status.json pendant une exécution
+type ScraperStatus = scraperspec.Status
+
+// LoadScraperStatus lit status.json et marque comme interrompue une exécution non mise à jour depuis
+// ScraperStatusStaleAfter à l'instant now (un fichier absent renvoie une erreur os.ErrNotExist)
+func LoadScraperStatus(path string, now time.Time) (ScraperStatus, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ScraperStatus{}, err
+	}
+
+	var status ScraperStatus
+	if err := json.Unmarshal(content, &status); err != nil {
+		return ScraperStatus{}, err
+	}
+	if status.Running && now.Sub(status.UpdatedAt) > ScraperStatusStaleAfter {
+		status.Running = false
+		status.Stale = true
+	}
+	return status, nil
+}
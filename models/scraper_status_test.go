@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFakeStatus écrit status.json comme le scraper (fichier temporaire renommé)
+func writeFakeStatus(path string, status ScraperStatus) error {
+	content, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Test de la lecture de status.json pendant qu'un faux scraper le met à jour
+func TestLoadScraperStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+
+	// Avant la première exécution, le fichier n'existe pas
+	_, err := LoadScraperStatus(path, time.Now())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Un faux scraper en cours publie sa progression à chaque étape
+	started := time.Now().Add(-10 * time.Second)
+	updates := make(chan int64)
+	written := make(chan error)
+	go func() {
+		defer close(written)
+		for completed := range updates {
+			written <- writeFakeStatus(path, ScraperStatus{
+				Running:           true,
+				PID:               4242,
+				StartedAt:         started,
+				UpdatedAt:         time.Now(),
+				RecipesFound:      20,
+				RecipesCompleted:  completed,
+				RecipesFailed:     1,
+				TotalRequests:     completed + 5,
+				RequestsPerSecond: 2.5,
+			})
+		}
+	}()
+	for _, completed := range []int64{3, 12} {
+		updates <- completed
+		require.NoError(t, <-written)
+		status, err := LoadScraperStatus(path, time.Now())
+		require.NoError(t, err)
+		assert.True(t, status.Running)
+		assert.False(t, status.Stale)
+		assert.Equal(t, 4242, status.PID)
+		assert.Equal(t, int64(20), status.RecipesFound)
+		assert.Equal(t, completed, status.RecipesCompleted)
+		assert.Equal(t, int64(1), status.RecipesFailed)
+		assert.Equal(t, 2.5, status.RequestsPerSecond)
+	}
+	close(updates)
+	<-written
+
+	// Un scraper qui ne met plus le fichier à jour est considéré comme interrompu
+	status, err := LoadScraperStatus(path, time.Now().Add(ScraperStatusStaleAfter+time.Second))
+	require.NoError(t, err)
+	assert.False(t, status.Running)
+	assert.True(t, status.Stale)
+
+	// Une exécution terminée n'est jamais marquée interrompue
+	finished := time.Now()
+	require.NoError(t, writeFakeStatus(path, ScraperStatus{StartedAt: started, UpdatedAt: finished, FinishedAt: &finished, RecipesCompleted: 19}))
+	status, err = LoadScraperStatus(path, finished.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, status.Running)
+	assert.False(t, status.Stale)
+	require.NotNil(t, status.FinishedAt)
+	assert.Equal(t, int64(19), status.RecipesCompleted)
+}
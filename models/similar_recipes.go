@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultSimilarLimit est le nombre de recettes renvoyées par /recette/:id/similar sans ?limit
+const DefaultSimilarLimit = 5
+
+// SimilarRecette est une recette proche d'une autre : vue allégée, ingrédients en commun et leur nombre (score)
+type SimilarRecette struct {
+	RecetteSummary    `bson:",inline"`
+	SharedIngredients []string `json:"sharedIngredients" bson:"sharedIngredients"`
+	Score             int      `json:"score" bson:"score"`
+}
+
+// ParseSimilarLimit valide le paramètre ?limit= de /recette/:id/similar (vide = 5)
+func ParseSimilarLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultSimilarLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxListLimit {
+		return 0, fmt.Errorf("limit invalide %q: entier entre 1 et %d attendu", value, MaxListLimit)
+	}
+	return limit, nil
+}
+
+// IngredientNames retourne les noms normalisés distincts des ingrédients d'une recette, triés
+// Les ingrédients enregistrés sans nom normalisé sont normalisés à la volée, les noms vides ignorés
+func IngredientNames(recette Recette) []string {
+	// Copie des ingrédients : la recette de l'appelant n'est pas modifiée
+	recette.Ingredients = append([]Ingredient(nil), recette.Ingredients...)
+	NormalizeIngredients(&recette)
+	seen := make(map[string]bool, len(recette.Ingredients))
+	names := make([]string, 0, len(recette.Ingredients))
+	for _, ingredient := range recette.Ingredients {
+		name := ingredient.NameNormalized
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SimilarPipeline retourne les limit recettes partageant le plus d'ingrédients (names) avec la recette id, exclue
+// Le nom normalisé (nameNormalized) de chaque ingrédient est comparé s'il est présent, le nom affiché en minuscules
+// sinon (recettes importées avant la normalisation, comme /recettes/top-ingredients) ; le score est le nombre
+// d'ingrédients distincts partagés, les égalités triées par nom. Les recettes sans ingrédient commun sont écartées
+func SimilarPipeline(id primitive.ObjectID, names []string, limit int) []bson.D {
+	projection := bson.M{"sharedIngredients": 1, "score": 1}
+	for field, value := range SummaryProjection {
+		projection[field] = value
+	}
+
+	return []bson.D{
+		// Candidates : un nom normalisé en commun, ou aucun nom normalisé enregistré
+		{{Key: "$match", Value: bson.M{
+			"_id": bson.M{"$ne": id},
+			"$or": bson.A{
+				bson.M{"ingredients.nameNormalized": bson.M{"$in": names}},
+				bson.M{"ingredients.nameNormalized": bson.M{"$exists": false}},
+			},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"sharedIngredients": bson.M{"$setIntersection": bson.A{
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$ingredients", bson.A{}}},
+					"as":    "ingredient",
+					"in": bson.M{"$ifNull": bson.A{
+						"$$ingredient.nameNormalized",
+						bson.M{"$toLower": "$$ingredient.name"},
+					}},
+				}},
+				names,
+			}},
+		}}},
+		{{Key: "$match", Value: bson.M{"sharedIngredients.0": bson.M{"$exists": true}}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$size": "$sharedIngredients"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: projection}},
+	}
+}
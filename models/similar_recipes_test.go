@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIngredientNames(t *testing.T) {
+	recette := recetteWith("2 Eggs", "1 cup flour", "egg", "2 cups")
+	recette.Ingredients = append(recette.Ingredients, Ingredient{Quantity: "1", Name: "Butter"})
+
+	assert.Equal(t, []string{"butter", "egg", "flour"}, IngredientNames(recette))
+	assert.Empty(t, recette.Ingredients[4].NameNormalized, "la recette de l'appelant n'est pas modifiée")
+	assert.Empty(t, IngredientNames(Recette{}))
+}
+
+// Structure de l'agrégation ; son exécution sur un vrai mongod est couverte par les tests d'intégration
+func TestSimilarPipeline(t *testing.T) {
+	id := primitive.NewObjectID()
+	names := []string{"butter", "egg", "flour"}
+	pipeline := SimilarPipeline(id, names, 2)
+	assert.Equal(t, []string{"$match", "$addFields", "$match", "$addFields", "$sort", "$limit", "$project"}, stageOperators(pipeline))
+
+	// La recette elle-même est exclue ; celles enregistrées sans nom normalisé restent candidates
+	match, _ := stage(pipeline, "$match")
+	assert.Equal(t, bson.M{"$ne": id}, match.(bson.M)["_id"])
+	assert.Contains(t, match.(bson.M)["$or"], bson.M{"ingredients.nameNormalized": bson.M{"$in": names}})
+	assert.Contains(t, match.(bson.M)["$or"], bson.M{"ingredients.nameNormalized": bson.M{"$exists": false}})
+
+	// Chaque ingrédient est comparé par son nom normalisé, ou son nom affiché en minuscules à défaut
+	shared, _ := stage(pipeline, "$addFields")
+	intersection := shared.(bson.M)["sharedIngredients"].(bson.M)["$setIntersection"].(bson.A)
+	mapped := intersection[0].(bson.M)["$map"].(bson.M)
+	assert.Equal(t, bson.M{"$ifNull": bson.A{"$$ingredient.nameNormalized", bson.M{"$toLower": "$$ingredient.name"}}}, mapped["in"])
+	assert.Equal(t, names, intersection[1])
+
+	sort, _ := stage(pipeline, "$sort")
+	assert.Equal(t, "score", sort.(bson.D)[0].Key)
+	limit, _ := stage(pipeline, "$limit")
+	assert.Equal(t, 2, limit)
+	project, _ := stage(pipeline, "$project")
+	assert.Equal(t, 1, project.(bson.M)["sharedIngredients"])
+	assert.Equal(t, 1, project.(bson.M)["score"])
+}
+
+func TestParseSimilarLimit(t *testing.T) {
+	limit, err := ParseSimilarLimit("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSimilarLimit, limit)
+
+	for _, value := range []string{"0", "x", "101"} {
+		_, err := ParseSimilarLimit(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultTopIngredientsLimit est le nombre d'ingrédients renvoyés par /recettes/top-ingredients sans ?limit
+const DefaultTopIngredientsLimit = 20
+
+// IngredientCount est un ingrédient et le nombre de recettes qui l'utilisent
+type IngredientCount struct {
+	Name  string `json:"name" bson:"name"`
+	Count int    `json:"count" bson:"count"`
+}
+
+// ParseTopIngredientsLimit valide le paramètre ?limit= de /recettes/top-ingredients (vide = 20)
+func ParseTopIngredientsLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultTopIngredientsLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxListLimit {
+		return 0, fmt.Errorf("limit invalide %q: entier entre 1 et %d attendu", value, MaxListLimit)
+	}
+	return limit, nil
+}
+
+// TopIngredientsPipeline compte les recettes utilisant chaque ingrédient et garde les limit plus fréquents
+// Le nom normalisé (nameNormalized) est utilisé s'il est présent, le nom affiché en minuscules sinon
+// (recettes importées avant la normalisation) ; un ingrédient répété dans une recette n'est compté qu'une fois
+// À égalité, les ingrédients sont triés par nom pour un résultat stable
+func TopIngredientsPipeline(limit int) []bson.D {
+	return []bson.D{
+		{{Key: "$unwind", Value: "$ingredients"}},
+		// Un couple (recette, ingrédient) par ingrédient distinct de chaque recette
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"recette": "$_id",
+				"name": bson.M{"$ifNull": bson.A{
+					"$ingredients.nameNormalized",
+					bson.M{"$toLower": "$ingredients.name"},
+				}},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$_id.name",
+			"count": bson.M{"$sum": 1},
+		}}},
+		// Ingrédients sans nom (quantité seule, anciens documents)
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$ne": ""}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "name": "$_id", "count": 1}}},
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// recetteWith crée une recette dont les ingrédients sont normalisés comme à l'insertion
+func recetteWith(names ...string) Recette {
+	recette := Recette{Name: "Recette", Page: "https://www.allrecipes.com/recipe/1/"}
+	for _, name := range names {
+		recette.Ingredients = append(recette.Ingredients, Ingredient{Quantity: "1", Name: name})
+	}
+	NormalizeIngredients(&recette)
+	return recette
+}
+
+// Structure de l'agrégation ; son exécution sur un vrai mongod est couverte par les tests d'intégration
+// (go test -tags integration, voir aggregation_integration_test.go)
+func TestTopIngredientsPipeline(t *testing.T) {
+	pipeline := TopIngredientsPipeline(3)
+	assert.Equal(t, []string{"$unwind", "$group", "$group", "$match", "$sort", "$limit", "$project"}, stageOperators(pipeline))
+
+	// Un couple (recette, ingrédient) par nom : normalisé, ou affiché en minuscules pour les anciennes recettes
+	group, _ := stage(pipeline, "$group")
+	assert.Equal(t, bson.M{
+		"recette": "$_id",
+		"name":    bson.M{"$ifNull": bson.A{"$ingredients.nameNormalized", bson.M{"$toLower": "$ingredients.name"}}},
+	}, group.(bson.M)["_id"])
+
+	sort, _ := stage(pipeline, "$sort")
+	assert.Equal(t, bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}, sort)
+	limit, _ := stage(pipeline, "$limit")
+	assert.Equal(t, 3, limit)
+}
+
+func TestParseTopIngredientsLimit(t *testing.T) {
+	limit, err := ParseTopIngredientsLimit("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTopIngredientsLimit, limit)
+
+	limit, err = ParseTopIngredientsLimit("5")
+	require.NoError(t, err)
+	assert.Equal(t, 5, limit)
+
+	for _, value := range []string{"0", "-1", "abc", "101"} {
+		_, err := ParseTopIngredientsLimit(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// recetteValidator vérifie les tags validate du modèle de recette
+// Les champs sont nommés par leur tag json, comme dans les corps de requête
+var recetteValidator = newRecetteValidator()
+
+func newRecetteValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError décrit une contrainte non respectée par un champ
+type FieldError struct {
+	Field   string `json:"field"`           // Chemin json du champ (ex: "page")
+	Rule    string `json:"rule"`            // Tag validate en échec (ex: "required", "min")
+	Param   string `json:"param,omitempty"` // Paramètre de la règle (ex: "1" pour min=1)
+	Message string `json:"message"`
+}
+
+// ValidationError regroupe les champs invalides d'une recette
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// ValidateRecette vérifie une recette avant enregistrement (nom, page http(s), au moins un ingrédient)
+// Retourne nil ou une *ValidationError listant tous les champs invalides
+func ValidateRecette(recette Recette) error {
+	err := recetteValidator.Struct(recette)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// Namespace commence par le type ("Recipe.page") : seul le chemin json est gardé
+		_, path, _ := strings.Cut(fieldErr.Namespace(), ".")
+		fields = append(fields, FieldError{
+			Field:   path,
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldMessage(fieldErr),
+		})
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// fieldMessage traduit une contrainte en message lisible
+func fieldMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "champ obligatoire"
+	case "min":
+		return fmt.Sprintf("au moins %s élément(s) attendu(s)", fieldErr.Param())
+	case "http_url":
+		return fmt.Sprintf("URL http(s) attendue, %q reçu", fieldErr.Value())
+	default:
+		return fmt.Sprintf("contrainte %q non respectée", fieldErr.Tag())
+	}
+}
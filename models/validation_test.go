@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validRecette() Recette {
+	return Recette{
+		Name:        "Soupe",
+		Page:        "https://www.allrecipes.com/recipe/1/soupe/",
+		Ingredients: []Ingredient{{Quantity: "2", Unit: "cups"}},
+	}
+}
+
+// Test de chaque contrainte du modèle : un seul champ en échec à la fois, nommé par son tag json
+func TestValidateRecette(t *testing.T) {
+	require.NoError(t, ValidateRecette(validRecette()))
+
+	cases := []struct {
+		name   string
+		modify func(*Recette)
+		field  string
+		rule   string
+	}{
+		{"nom manquant", func(r *Recette) { r.Name = "" }, "name", "required"},
+		{"page manquante", func(r *Recette) { r.Page = "" }, "page", "required"},
+		{"page sans schéma", func(r *Recette) { r.Page = "www.allrecipes.com/recipe/1" }, "page", "http_url"},
+		{"page non http", func(r *Recette) { r.Page = "ftp://example.com/soupe" }, "page", "http_url"},
+		{"aucun ingrédient", func(r *Recette) { r.Ingredients = nil }, "ingredients", "min"},
+		{"liste d'ingrédients vide", func(r *Recette) { r.Ingredients = []Ingredient{} }, "ingredients", "min"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recette := validRecette()
+			tc.modify(&recette)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, ValidateRecette(recette), &validationErr)
+			require.Len(t, validationErr.Fields, 1)
+			assert.Equal(t, tc.field, validationErr.Fields[0].Field)
+			assert.Equal(t, tc.rule, validationErr.Fields[0].Rule)
+			assert.NotEmpty(t, validationErr.Fields[0].Message)
+			assert.Contains(t, validationErr.Error(), tc.field+": ")
+		})
+	}
+
+	// Tous les champs invalides sont listés
+	var validationErr *ValidationError
+	require.ErrorAs(t, ValidateRecette(Recette{}), &validationErr)
+	assert.Len(t, validationErr.Fields, 3)
+	assert.Equal(t, "1", validationErr.Fields[2].Param)
+}
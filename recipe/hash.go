@@ -0,0 +1,35 @@
+package recipe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+)
+
+// ContentHash calcule l'empreinte SHA-256 du contenu d'une recette (nom, ingrédients, instructions)
+// Les textes sont normalisés (casse, espaces) et les autres champs ignorés : seule une vraie modification
+// de la recette change l'empreinte. Le scraper l'écrit avec chaque recette, l'API s'en sert pour ignorer
+// les recettes inchangées à l'import et pour comparer une recette stockée à sa version fraîche
+func ContentHash(r Recipe) string {
+	h := sha256.New()
+	writeHashField(h, r.Name)
+	for _, ingredient := range r.Ingredients {
+		writeHashField(h, "i", ingredient.Quantity, ingredient.Unit, ingredient.Name)
+	}
+	for _, instruction := range r.Instructions {
+		writeHashField(h, "s", instruction.Description)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashField écrit des champs normalisés séparés par des octets nuls, terminés par un saut de ligne
+func writeHashField(h hash.Hash, fields ...string) {
+	for i, field := range fields {
+		if i > 0 {
+			h.Write([]byte{0})
+		}
+		h.Write([]byte(strings.Join(strings.Fields(strings.ToLower(field)), " ")))
+	}
+	h.Write([]byte{'\n'})
+}
@@ -0,0 +1,45 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// soupeHash est l'empreinte de la recette de référence
+const soupeHash = "4c879a28c8a3b760a55b70ee55f3c3c418f051edc28694a287ed11e260ad01b0"
+
+func soupeRecipe() Recipe {
+	return Recipe{
+		Name:         "Soupe de légumes",
+		Page:         "https://example.com/soupe",
+		Ingredients:  []Ingredient{{Quantity: "2 carottes", Name: "carottes"}, {Quantity: "1 oignon", Name: "oignon"}},
+		Instructions: []Instruction{{Number: "1", Description: "Éplucher les légumes."}, {Number: "2", Description: "Cuire 20 minutes."}},
+	}
+}
+
+// Test de la stabilité de l'empreinte : capacité des slices, casse, espaces et champs hors contenu sans effet
+func TestContentHash(t *testing.T) {
+	recipe := soupeRecipe()
+	assert.Equal(t, soupeHash, ContentHash(recipe))
+
+	// Mêmes éléments dans des slices de capacité différente
+	same := soupeRecipe()
+	same.Ingredients = append(make([]Ingredient, 0, 64), recipe.Ingredients...)
+	same.Instructions = append(make([]Instruction, 0, 64), recipe.Instructions...)
+	assert.Equal(t, soupeHash, ContentHash(same))
+
+	same.Name = "  SOUPE de   légumes\n"
+	same.Category = "soupes"
+	same.Image = "https://img.example/soupe.jpg"
+	same.Ingredients[0].NameNormalized = "carrot"
+	assert.Equal(t, soupeHash, ContentHash(same))
+
+	changed := soupeRecipe()
+	changed.Ingredients = changed.Ingredients[:1]
+	assert.NotEqual(t, soupeHash, ContentHash(changed))
+
+	changed = soupeRecipe()
+	changed.Instructions[1].Description = "Cuire 25 minutes."
+	assert.NotEqual(t, soupeHash, ContentHash(changed))
+}
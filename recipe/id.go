@@ -0,0 +1,38 @@
+package recipe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// idLength est le nombre de caractères hexadécimaux gardés de l'empreinte (64 bits)
+const idLength = 16
+
+// ID calcule l'identifiant stable d'une recette (recipeId) à partir de l'URL canonique de sa page
+// Une même recette a le même identifiant quelle que soit l'exécution, même si l'URL diffère par le schéma,
+// le www, la casse de l'hôte, la barre finale, la query ou le fragment ("" pour une page vide)
+// Le scraper le calcule pour dédoublonner une exécution, l'API pour ses upserts
+func ID(page string) string {
+	page = strings.TrimSpace(page)
+	if page == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(canonicalURL(page)))
+	return hex.EncodeToString(sum[:])[:idLength]
+}
+
+// canonicalURL normalise l'URL d'une page de recette (https, hôte en minuscules sans www ni port par défaut,
+// chemin sans barre finale, sans query ni fragment) ; une URL illisible est gardée telle quelle
+func canonicalURL(page string) string {
+	u, err := url.Parse(page)
+	if err != nil || u.Host == "" {
+		return page
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	return "https://" + host + strings.TrimRight(u.EscapedPath(), "/")
+}
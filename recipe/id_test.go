@@ -0,0 +1,31 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cookiesID est l'identifiant de la recette de référence
+const cookiesID = "66fafa1834c30adb"
+
+// Test de la stabilité de l'identifiant : les variantes d'une même URL donnent le même identifiant
+func TestID(t *testing.T) {
+	for _, page := range []string{
+		"https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies/",
+		"https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies",
+		"https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies/?utm_source=newsletter&page=2",
+		"https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies/#recipe-steps",
+		"http://WWW.AllRecipes.com:443/recipe/10813/best-chocolate-chip-cookies//",
+		" https://allrecipes.com/recipe/10813/best-chocolate-chip-cookies/ ",
+	} {
+		assert.Equal(t, cookiesID, ID(page), page)
+	}
+
+	assert.Len(t, cookiesID, idLength)
+	assert.NotEqual(t, cookiesID, ID("https://www.allrecipes.com/recipe/10814/best-chocolate-chip-cookies/"))
+	// Le chemin garde sa casse : seul l'hôte est insensible à la casse
+	assert.NotEqual(t, cookiesID, ID("https://www.allrecipes.com/Recipe/10813/best-chocolate-chip-cookies/"))
+	assert.NotEqual(t, ID("https://example.com:8080/soupe"), ID("https://example.com/soupe"))
+	assert.Empty(t, ID("  "))
+}
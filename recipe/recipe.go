@@ -0,0 +1,77 @@
+// Package recipe définit le modèle de recette partagé par le scraper et l'API
+// Les tags json décrivent data.json et les réponses de l'API, les tags bson les documents MongoDB :
+// les deux côtés utilisent les mêmes noms de champs. Les tags validate sont vérifiés par l'API
+// à l'enregistrement (models.ValidateRecette)
+package recipe
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recipe est une recette telle qu'émise par le scraper et stockée par l'API
+type Recipe struct {
+	ID               primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty" swagger:"description(Identifiant MongoDB, absent tant que la recette n'est pas stockée)"`
+	RecipeID         string             `json:"recipeId,omitempty" bson:"recipeId,omitempty" swagger:"description(Identifiant stable dérivé de l'URL canonique de la page, identique d'une exécution à l'autre)"`
+	Name             string             `json:"name" bson:"name" validate:"required" swagger:"description(Nom de la recette)"`
+	Page             string             `json:"page" bson:"page" validate:"required,http_url" swagger:"description(URL de la page de la recette)"`
+	Image            string             `json:"image" bson:"image" swagger:"description(URL de l'image de la recette)"`
+	Category         string             `json:"category,omitempty" bson:"category,omitempty" swagger:"description(Catégorie AllRecipes d'origine, ex: desserts)"`
+	Language         string             `json:"language,omitempty" bson:"language,omitempty" swagger:"description(Langue détectée par le scraper, code ISO 639-1)"`
+	Tags             []string           `json:"tags,omitempty" bson:"tags,omitempty" swagger:"description(Étiquettes normalisées issues du fil d'Ariane et des catégories de la page)"`
+	Cuisine          string             `json:"cuisine,omitempty" bson:"cuisine,omitempty" swagger:"description(Cuisine de la recette en minuscules, ex: italian)"`
+	Course           string             `json:"course,omitempty" bson:"course,omitempty" swagger:"description(Type de plat en minuscules, ex: dessert)"`
+	Ingredients      []Ingredient       `json:"ingredients" bson:"ingredients" validate:"min=1" swagger:"description(Liste des ingrédients de la recette)"`
+	Instructions     []Instruction      `json:"instructions" bson:"instructions" swagger:"description(Liste des instructions de la recette)"`
+	PrepTimeMinutes  int                `json:"prepTimeMinutes,omitempty" bson:"prepTimeMinutes,omitempty" swagger:"description(Temps de préparation en minutes)"`
+	CookTimeMinutes  int                `json:"cookTimeMinutes,omitempty" bson:"cookTimeMinutes,omitempty" swagger:"description(Temps de cuisson en minutes)"`
+	TotalTimeMinutes int                `json:"totalTimeMinutes,omitempty" bson:"totalTimeMinutes,omitempty" swagger:"description(Temps total de préparation et de cuisson en minutes)"`
+	Rating           *Rating            `json:"rating,omitempty" bson:"rating,omitempty" swagger:"description(Note moyenne des utilisateurs)"`
+	Nutrition        *Nutrition         `json:"nutrition,omitempty" bson:"nutrition,omitempty" swagger:"description(Valeurs nutritionnelles par portion)"`
+	CreatedAt        *time.Time         `json:"createdAt,omitempty" bson:"createdAt,omitempty" swagger:"description(Date d'insertion de la recette)"`
+	ContentHash      string             `json:"contentHash,omitempty" bson:"contentHash,omitempty" swagger:"description(Empreinte SHA-256 du nom, des ingrédients et des instructions)"`
+}
+
+// Ingredient représente un ingrédient avec sa quantité et son unité
+type Ingredient struct {
+	Quantity       string `json:"quantity" bson:"quantity" swagger:"description(Quantité de l'ingrédient)"`
+	Unit           string `json:"unit" bson:"unit" swagger:"description(Unité de mesure de l'ingrédient)"`
+	Name           string `json:"name,omitempty" bson:"name,omitempty" swagger:"description(Nom de l'ingrédient tel qu'affiché)"`
+	NameNormalized string `json:"nameNormalized,omitempty" bson:"nameNormalized,omitempty" swagger:"description(Nom normalisé pour la recherche, renseigné par l'API)"`
+}
+
+// Instruction représente une étape de la recette
+type Instruction struct {
+	Number      string `json:"number" bson:"number" swagger:"description(Numéro de l'instruction)"`
+	Description string `json:"description" bson:"description" swagger:"description(Description de l'instruction)"`
+}
+
+// Rating est la note moyenne d'une recette et le nombre d'avis
+type Rating struct {
+	Value float64 `json:"value" bson:"value" swagger:"description(Note moyenne sur 5)"`
+	Count int     `json:"count" bson:"count" swagger:"description(Nombre d'avis)"`
+}
+
+// Nutrition reprend les valeurs nutritionnelles affichées par la page, unités comprises (ex: "12g")
+type Nutrition struct {
+	Calories      string `json:"calories,omitempty" bson:"calories,omitempty"`
+	Fat           string `json:"fat,omitempty" bson:"fat,omitempty"`
+	Carbohydrates string `json:"carbohydrates,omitempty" bson:"carbohydrates,omitempty"`
+	Protein       string `json:"protein,omitempty" bson:"protein,omitempty"`
+}
+
+// MarshalJSON omet l'identifiant tant qu'il est nul : omitempty ne s'applique pas à un ObjectID,
+// qui serait sinon écrit "000000000000000000000000" dans data.json
+func (r Recipe) MarshalJSON() ([]byte, error) {
+	type plain Recipe // Sans la méthode MarshalJSON
+	var id *primitive.ObjectID
+	if !r.ID.IsZero() {
+		id = &r.ID
+	}
+	return json.Marshal(struct {
+		ID *primitive.ObjectID `json:"id,omitempty"`
+		plain
+	}{id, plain(r)})
+}
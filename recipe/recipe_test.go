@@ -0,0 +1,86 @@
+package recipe
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func sampleRecipe() Recipe {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return Recipe{
+		ID:               primitive.NewObjectID(),
+		Name:             "Soupe de tomates",
+		Page:             "https://www.allrecipes.com/recipe/1/soupe/",
+		Image:            "https://example.com/soupe.jpg",
+		Category:         "soup",
+		Ingredients:      []Ingredient{{Quantity: "2", Unit: "cups", Name: "Tomatoes", NameNormalized: "tomato"}},
+		Instructions:     []Instruction{{Number: "1", Description: "Mixer"}},
+		PrepTimeMinutes:  10,
+		CookTimeMinutes:  20,
+		TotalTimeMinutes: 30,
+		Rating:           &Rating{Value: 4.5, Count: 120},
+		Nutrition:        &Nutrition{Calories: "180", Fat: "7g"},
+		CreatedAt:        &created,
+		ContentHash:      "abc",
+	}
+}
+
+// Test de l'aller-retour BSON et des noms de champs des documents MongoDB
+func TestRecipeBSON(t *testing.T) {
+	original := sampleRecipe()
+
+	raw, err := bson.Marshal(original)
+	require.NoError(t, err)
+
+	var decoded Recipe
+	require.NoError(t, bson.Unmarshal(raw, &decoded))
+	assert.Equal(t, original, decoded)
+
+	var document bson.M
+	require.NoError(t, bson.Unmarshal(raw, &document))
+	assert.Equal(t, original.ID, document["_id"])
+	for _, key := range []string{"name", "page", "image", "category", "ingredients", "instructions",
+		"prepTimeMinutes", "cookTimeMinutes", "totalTimeMinutes", "rating", "nutrition", "createdAt", "contentHash"} {
+		assert.Contains(t, document, key)
+	}
+	assert.NotContains(t, document, "language", "champ vide omis")
+	ingredient := document["ingredients"].(bson.A)[0].(bson.M)
+	assert.Equal(t, "tomato", ingredient["nameNormalized"])
+	assert.Equal(t, 4.5, document["rating"].(bson.M)["value"])
+
+	// Une recette du scraper, sans identifiant, laisse MongoDB générer _id
+	raw, err = bson.Marshal(Recipe{Name: "Soupe"})
+	require.NoError(t, err)
+	document = bson.M{}
+	require.NoError(t, bson.Unmarshal(raw, &document))
+	assert.NotContains(t, document, "_id")
+	assert.NotContains(t, document, "rating")
+}
+
+// Test des noms de champs JSON, identiques à ceux de BSON hors identifiant
+func TestRecipeJSON(t *testing.T) {
+	original := sampleRecipe()
+
+	content, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	var decoded Recipe
+	require.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, original, decoded)
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, original.ID.Hex(), document["id"])
+	assert.Contains(t, document, "instructions")
+
+	// Sans identifiant, data.json n'a pas de champ id
+	content, err = json.Marshal(Recipe{Name: "Soupe"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), `"id"`)
+}
@@ -0,0 +1,99 @@
+package responses
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Codes d'erreur renvoyés dans le champ "code" de ErrorResponse
+const (
+	CodeInvalidParameter  = "INVALID_PARAMETER"
+	CodeInvalidRecipeID   = "INVALID_RECIPE_ID"
+	CodeRecipeNotFound    = "RECIPE_NOT_FOUND"
+	CodeNoRecipes         = "NO_RECIPES"
+	CodeDatabaseError     = "DATABASE_ERROR"
+	CodeDataFileNotFound  = "DATA_FILE_NOT_FOUND"
+	CodeDataFileError     = "DATA_FILE_ERROR"
+	CodeDataFileTooLarge  = "DATA_FILE_TOO_LARGE"
+	CodeLogFileNotFound   = "LOG_FILE_NOT_FOUND"
+	CodeLogFileError      = "LOG_FILE_ERROR"
+	CodeStatusNotFound    = "STATUS_NOT_FOUND"
+	CodeStatusFileError   = "STATUS_FILE_ERROR"
+	CodeConfigError       = "CONFIG_ERROR"
+	CodeExportInterrupted = "EXPORT_INTERRUPTED"
+	CodeInvalidData       = "INVALID_DATA"
+	CodeValidationFailed  = "VALIDATION_FAILED"
+	CodeScraperNotFound   = "SCRAPER_NOT_FOUND"
+	CodeScraperFailed     = "SCRAPER_FAILED"
+	CodeScraperRunning    = "SCRAPER_RUNNING"
+	CodeScraperTooSoon    = "SCRAPER_TOO_SOON"
+	CodeJobNotFound       = "JOB_NOT_FOUND"
+	CodeMetricsError      = "METRICS_ERROR"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeAuthNotConfigured = "AUTH_NOT_CONFIGURED"
+	CodeInternalError     = "INTERNAL_ERROR"
+)
+
+// ErrorResponse est l'enveloppe commune à toutes les réponses d'erreur de l'API
+type ErrorResponse struct {
+	Error     bool        `json:"error"`
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"` // Précisions structurées (ex: champs invalides)
+	RequestID string      `json:"request_id,omitempty"`
+	Version   string      `json:"version,omitempty"` // Version de l'API, sur les erreurs levées par Fiber (ErrorHandler)
+}
+
+// SendError envoie une ErrorResponse avec le request ID posé par le middleware de logging
+func SendError(c *fiber.Ctx, status int, code, message string) error {
+	requestID, _ := c.Locals("requestID").(string)
+	return SendJSON(c, status, ErrorResponse{
+		Error:     true,
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+	})
+}
+
+// SendErrorDetails envoie une ErrorResponse accompagnée de précisions structurées (champ details)
+func SendErrorDetails(c *fiber.Ctx, status int, code, message string, details interface{}) error {
+	requestID, _ := c.Locals("requestID").(string)
+	return SendJSON(c, status, ErrorResponse{
+		Error:     true,
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: requestID,
+	})
+}
+
+// ErrorHandler retourne le gestionnaire des erreurs levées par Fiber ou non traitées par les handlers
+// Le code est dérivé du statut (CodeForStatus) et la version de l'API ajoutée à l'enveloppe
+func ErrorHandler(version string) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		status := fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+		requestID, _ := c.Locals("requestID").(string)
+		return SendJSON(c, status, ErrorResponse{
+			Error:     true,
+			Code:      CodeForStatus(status),
+			Message:   err.Error(),
+			RequestID: requestID,
+			Version:   version,
+		})
+	}
+}
+
+// CodeForStatus dérive un code générique d'un statut HTTP (ex: 404 → NOT_FOUND)
+// Utilisé pour les erreurs levées par Fiber lui-même, sans code métier
+func CodeForStatus(status int) string {
+	text := http.StatusText(status)
+	if text == "" || status >= 500 {
+		return CodeInternalError
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
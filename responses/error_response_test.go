@@ -0,0 +1,117 @@
+package responses
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de la forme de l'enveloppe d'erreur pour 400, 404 et 500
+func TestSendErrorShape(t *testing.T) {
+	cases := []struct {
+		status  int
+		code    string
+		message string
+	}{
+		{400, CodeInvalidRecipeID, "ID de recette invalide"},
+		{404, CodeRecipeNotFound, "Recette introuvable"},
+		{500, CodeDatabaseError, "Erreur lors de la récupération des recettes"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				c.Locals("requestID", "req-123")
+				return SendError(c, tc.status, tc.code, tc.message)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+			assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &raw))
+			assert.Equal(t, map[string]interface{}{
+				"error":      true,
+				"code":       tc.code,
+				"message":    tc.message,
+				"request_id": "req-123",
+			}, raw)
+		})
+	}
+}
+
+// Test sans request ID (middleware absent) : le champ est omis
+func TestSendErrorWithoutRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return SendError(c, 404, CodeRecipeNotFound, "Recette introuvable")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	var response ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.True(t, response.Error)
+	assert.Empty(t, response.RequestID)
+}
+
+// Test des précisions structurées : présentes avec SendErrorDetails, absentes sinon
+func TestSendErrorDetails(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return SendErrorDetails(c, 400, CodeValidationFailed, "1 recette(s) invalide(s)", fiber.Map{"invalid": 1})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	var raw map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	assert.Equal(t, CodeValidationFailed, raw["code"])
+	assert.Equal(t, map[string]interface{}{"invalid": float64(1)}, raw["details"])
+}
+
+// Test des codes génériques dérivés du statut HTTP
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, "NOT_FOUND", CodeForStatus(404))
+	assert.Equal(t, "METHOD_NOT_ALLOWED", CodeForStatus(405))
+	assert.Equal(t, CodeInternalError, CodeForStatus(500))
+	assert.Equal(t, CodeInternalError, CodeForStatus(599))
+}
+
+// Test du gestionnaire d'erreurs de Fiber : code dérivé du statut et version de l'API
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler("1.2.3")})
+	app.Get("/teapot", func(c *fiber.Ctx) error {
+		c.Locals("requestID", "req-456")
+		return fiber.NewError(fiber.StatusTeapot, "théière")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/teapot", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTeapot, resp.StatusCode)
+	var response ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, ErrorResponse{Error: true, Code: "IM_A_TEAPOT", Message: "théière", RequestID: "req-456", Version: "1.2.3"}, response)
+
+	// Route absente : 404 levé par Fiber
+	resp, err = app.Test(httptest.NewRequest("GET", "/absente", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, "NOT_FOUND", response.Code)
+	assert.Equal(t, "1.2.3", response.Version)
+}
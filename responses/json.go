@@ -0,0 +1,28 @@
+package responses
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WantsPretty indique si le client demande du JSON indenté (?pretty=true)
+func WantsPretty(c *fiber.Ctx) bool {
+	pretty, err := strconv.ParseBool(c.Query("pretty"))
+	return err == nil && pretty
+}
+
+// SendJSON envoie data en JSON compact, ou indenté avec ?pretty=true
+func SendJSON(c *fiber.Ctx, status int, data interface{}) error {
+	if !WantsPretty(c) {
+		return c.Status(status).JSON(data)
+	}
+
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(status).Send(body)
+}
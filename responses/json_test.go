@@ -0,0 +1,50 @@
+package responses
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getBody exécute GET target et retourne le statut, le Content-Type et le corps
+func getBody(t *testing.T, app *fiber.App, target string) (int, string, string) {
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+}
+
+func TestSendJSONPretty(t *testing.T) {
+	app := fiber.New()
+	app.Get("/recette", func(c *fiber.Ctx) error {
+		return SendJSON(c, 200, fiber.Map{"name": "Soupe"})
+	})
+	app.Get("/erreur", func(c *fiber.Ctx) error {
+		return SendError(c, 404, CodeRecipeNotFound, "Recette introuvable")
+	})
+
+	// Compact par défaut
+	status, contentType, body := getBody(t, app, "/recette")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, `{"name":"Soupe"}`, body)
+
+	status, contentType, body = getBody(t, app, "/recette?pretty=true")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "{\n  \"name\": \"Soupe\"\n}", body)
+
+	_, _, body = getBody(t, app, "/recette?pretty=false")
+	assert.Equal(t, `{"name":"Soupe"}`, body)
+
+	// Les erreurs suivent le même réglage
+	status, _, body = getBody(t, app, "/erreur?pretty=1")
+	assert.Equal(t, 404, status)
+	assert.Contains(t, body, "\n  \"code\": \"RECIPE_NOT_FOUND\"")
+}
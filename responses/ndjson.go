@@ -0,0 +1,62 @@
+package responses
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+)
+
+// NDJSONContentType est le type MIME d'un flux JSON délimité par des retours à la ligne
+const NDJSONContentType = "application/x-ndjson"
+
+// ndjsonFlushEvery fixe le nombre de lignes écrites entre deux envois au client
+const ndjsonFlushEvery = 100
+
+// Cursor est le sous-ensemble de *mongo.Cursor utilisé pour l'export
+type Cursor interface {
+	Next(ctx context.Context) bool
+	Decode(val interface{}) error
+	Err() error
+}
+
+// ExportError est la dernière ligne d'un export interrompu, pour que le client ne le prenne pas pour complet
+type ExportError struct {
+	Error   bool   `json:"error"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Lines   int    `json:"lines"` // Documents écrits avant l'interruption
+}
+
+// WriteNDJSON écrit chaque document du curseur sur une ligne, sans charger toute la collection en mémoire
+// newDoc fournit la valeur dans laquelle décoder chaque document
+// En cas d'erreur du curseur, une ligne ExportError termine le flux et l'erreur est retournée
+func WriteNDJSON(ctx context.Context, w *bufio.Writer, cursor Cursor, newDoc func() interface{}) (int, error) {
+	encoder := json.NewEncoder(w) // Encode ajoute le retour à la ligne
+	count := 0
+
+	fail := func(err error) (int, error) {
+		encoder.Encode(ExportError{Error: true, Code: CodeExportInterrupted, Message: err.Error(), Lines: count})
+		w.Flush()
+		return count, err
+	}
+
+	for cursor.Next(ctx) {
+		doc := newDoc()
+		if err := cursor.Decode(doc); err != nil {
+			return fail(err)
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return count, err // Écriture impossible : le client est déconnecté
+		}
+		count++
+		if count%ndjsonFlushEvery == 0 {
+			if err := w.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fail(err)
+	}
+	return count, w.Flush()
+}
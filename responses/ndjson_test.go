@@ -0,0 +1,93 @@
+package responses
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCursor parcourt des documents en mémoire et peut échouer après failAfter documents
+type fakeCursor struct {
+	docs      []map[string]interface{}
+	pos       int
+	failAfter int // -1 = jamais
+	err       error
+}
+
+func (f *fakeCursor) Next(ctx context.Context) bool {
+	if f.failAfter >= 0 && f.pos >= f.failAfter {
+		f.err = errors.New("connexion perdue")
+		return false
+	}
+	if f.pos >= len(f.docs) {
+		return false
+	}
+	f.pos++
+	return true
+}
+
+func (f *fakeCursor) Decode(val interface{}) error {
+	content, _ := json.Marshal(f.docs[f.pos-1])
+	return json.Unmarshal(content, val)
+}
+
+func (f *fakeCursor) Err() error { return f.err }
+
+func newFakeCursor(count, failAfter int) *fakeCursor {
+	docs := make([]map[string]interface{}, count)
+	for i := range docs {
+		docs[i] = map[string]interface{}{"name": "Recette", "index": i}
+	}
+	return &fakeCursor{docs: docs, failAfter: failAfter}
+}
+
+func newMap() interface{} { return &map[string]interface{}{} }
+
+func TestWriteNDJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	w := bufio.NewWriter(&buffer)
+
+	// Plus de documents que ndjsonFlushEvery : plusieurs envois intermédiaires
+	count, err := WriteNDJSON(context.Background(), w, newFakeCursor(250, -1), newMap)
+	require.NoError(t, err)
+	assert.Equal(t, 250, count)
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 250)
+	for _, line := range lines {
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &doc))
+	}
+	assert.JSONEq(t, `{"name":"Recette","index":249}`, lines[249])
+}
+
+func TestWriteNDJSONEmpty(t *testing.T) {
+	var buffer bytes.Buffer
+	count, err := WriteNDJSON(context.Background(), bufio.NewWriter(&buffer), newFakeCursor(0, -1), newMap)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, buffer.String())
+}
+
+func TestWriteNDJSONCursorError(t *testing.T) {
+	var buffer bytes.Buffer
+	count, err := WriteNDJSON(context.Background(), bufio.NewWriter(&buffer), newFakeCursor(10, 3), newMap)
+	require.Error(t, err)
+	assert.Equal(t, 3, count)
+
+	// La dernière ligne signale l'interruption
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	var trailer ExportError
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &trailer))
+	assert.True(t, trailer.Error)
+	assert.Equal(t, CodeExportInterrupted, trailer.Code)
+	assert.Equal(t, 3, trailer.Lines)
+}
@@ -3,25 +3,50 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/maxime-louis14/api-golang/controllers"
+	"github.com/maxime-louis14/api-golang/middleware"
 )
 
 // GetRecetteByName récupère une recette par son nom
 // @Summary Récupérer une recette par son nom
-// @Description Récupère une recette en utilisant son nom
+// @Description Récupère une recette en utilisant son nom exact, sans tenir compte de la casse
 // @Tags Recettes
 // @Param name path string true "Nom de la recette"
+// @Param fuzzy query bool false "Recherche approchée, tolère les fautes de frappe"
 // @Produce json
 // @Success 200 {object} models.Recette
 // @Failure 404 {string} string "Recette introuvable"
 // @Router /recettes/{name} [get]
 
 func RecetteRoute(app *fiber.App) {
-	app.Post("/scraper/run", controllers.LaunchScraper)
-	app.Post("/scraper/run/stream", controllers.LaunchScraperStream) // Route pour streaming des logs en temps réel
-	app.Get("/scraper/data", controllers.GetScraperData)             // Route pour télécharger le fichier JSON
+	// Idempotency-Key : une requête répétée renvoie la réponse du premier lancement
+	app.Post("/scraper/run", middleware.Idempotency(middleware.DefaultIdempotencyTTL), controllers.LaunchScraper)
+	app.Post("/scraper/run/stream", controllers.LaunchScraperStream)             // Route pour streaming des logs en temps réel
+	app.Get("/scraper/data", controllers.GetScraperData)                         // Route pour télécharger le fichier JSON
+	app.Get("/scraper/data/fresh", controllers.GetScraperDataFreshness)          // Âge de data.json comparé à ?max_age=
+	app.Post("/scraper/refresh-if-stale", controllers.RefreshScraperDataIfStale) // Lance le scraper si data.json est périmé
+	app.Get("/scraper/jobs", controllers.FindScraperJob)                         // Dernier job lancé par ?request_id=
+	app.Get("/scraper/jobs/:id", controllers.GetScraperJob)                      // État d'un job et request ID qui l'a lancé
+	app.Get("/scraper/logs", controllers.GetScraperLogs)                         // Dernières lignes de scraper.log (?tail=200)
+	app.Delete("/scraper/logs", middleware.APIKeyAuth(), controllers.ClearScraperLogs)
+	app.Get("/scraper/status", controllers.GetScraperStatus)         // Progression de l'exécution en cours (status.json)
+	app.Get("/scraper/categories", controllers.GetScraperCategories) // Catégories lues par le scraper (scraper_config.json)
+	app.Post("/scraper/categories", middleware.APIKeyAuth(), controllers.UpdateScraperCategories)
+	app.Get("/scraper/schedule", controllers.GetScraperSchedule) // Planification des exécutions et prochaine échéance
+	app.Put("/scraper/schedule", middleware.APIKeyAuth(), controllers.UpdateScraperSchedule)
+	app.Delete("/scraper/schedule", middleware.APIKeyAuth(), controllers.DeleteScraperSchedule)
+	app.Post("/scraper/diff", controllers.DiffScraperRecipe)                                  // Scrape d'une page comparé à la recette enregistrée
+	app.Post("/scraper/category", middleware.APIKeyAuth(), controllers.ScrapeScraperCategory) // Parcours d'une seule catégorie à la demande
+	app.Post("/scraper/rescrape", middleware.APIKeyAuth(), controllers.RescrapeRecipes)       // Nouveau scrape et upsert d'une liste de recettes
 	app.Post("/recettes", controllers.PostRecette)
 	app.Get("/recettes", controllers.GetAllRecettes)
+	app.Delete("/recettes", middleware.APIKeyAuth(), controllers.DeleteRecettes) // Suppression en masse par filtre
+	app.Get("/recettes/export", controllers.ExportRecettes)                      // Toutes les recettes en NDJSON (téléchargement)
+	app.Post("/recettes/import", controllers.ImportRecettes)                     // Upsert par page depuis un fichier JSON ou NDJSON
+	app.Get("/recettes/random", controllers.GetRandomRecette)                    // Recette(s) tirée(s) au hasard via $sample
+	app.Get("/recettes/top-ingredients", controllers.GetTopIngredients)          // Ingrédients les plus fréquents (?limit=20)
+	app.Get("/recettes/incomplete", controllers.GetIncompleteRecettes)           // Recettes sans ingrédients, image, nutrition... (?missing=)
 	app.Get("/recette/:id", controllers.GetRecetteByID)
+	app.Get("/recette/:id/similar", controllers.GetSimilarRecettes) // Recettes partageant le plus d'ingrédients (?limit=5)
 	app.Get("/recette/name/:name", controllers.GetRecetteByName)
 	app.Get("/recette/ingredient/:ingredient", controllers.GetRecettesByIngredient)
 
@@ -0,0 +1,188 @@
+// Package scheduler lance le scraper depuis l'API selon une planification cron persistée sur disque
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// ErrInvalidSchedule est retourné pour une planification qui n'est ni un cron à 5 champs ni un descripteur (@every 6h, @daily...)
+var ErrInvalidSchedule = errors.New("planification invalide")
+
+// Status décrit la planification active (réponse de GET /scraper/schedule)
+type Status struct {
+	Schedule string     `json:"schedule"`           // Spécification cron, vide si désactivée
+	Enabled  bool       `json:"enabled"`            // Planification active
+	NextRun  *time.Time `json:"next_run,omitempty"` // Prochaine exécution
+}
+
+// persisted est le contenu du fichier de planification
+type persisted struct {
+	Schedule string `json:"schedule"`
+}
+
+// Scheduler exécute run selon la planification persistée dans path
+// Thread-safe : modifiable par l'API pendant que le planificateur tourne
+type Scheduler struct {
+	mu    sync.Mutex
+	path  string
+	run   func()
+	cron  *cron.Cron
+	entry cron.EntryID
+	spec  string
+
+	running atomic.Bool      // Exécution en cours : les échéances suivantes sont ignorées
+	now     func() time.Time // Horloge (remplaçable dans les tests)
+}
+
+// Parse valide une planification cron (5 champs) ou un descripteur (@every 6h, @hourly...)
+func Parse(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, spec, err)
+	}
+	return schedule, nil
+}
+
+// NextRun retourne la prochaine échéance de spec après from
+func NextRun(spec string, from time.Time) (time.Time, error) {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(from), nil
+}
+
+// New charge la planification persistée (fichier absent = désactivée) et démarre le planificateur
+func New(path string, run func()) (*Scheduler, error) {
+	s := &Scheduler{path: path, run: run, cron: cron.New(), now: time.Now}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		var saved persisted
+		if err := json.Unmarshal(content, &saved); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if saved.Schedule != "" {
+			if err := s.apply(saved.Schedule); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	s.cron.Start()
+	return s, nil
+}
+
+// Status retourne la planification active et sa prochaine échéance
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.spec == "" {
+		return Status{}
+	}
+	next, err := NextRun(s.spec, s.now())
+	if err != nil {
+		return Status{Schedule: s.spec, Enabled: true}
+	}
+	return Status{Schedule: s.spec, Enabled: true, NextRun: &next}
+}
+
+// Set remplace la planification, la persiste et retourne le nouvel état
+func (s *Scheduler) Set(spec string) (Status, error) {
+	spec = strings.TrimSpace(spec)
+	if _, err := Parse(spec); err != nil {
+		return Status{}, err
+	}
+
+	s.mu.Lock()
+	if err := s.save(spec); err != nil {
+		s.mu.Unlock()
+		return Status{}, err
+	}
+	err := s.apply(spec)
+	s.mu.Unlock()
+	if err != nil {
+		return Status{}, err
+	}
+	return s.Status(), nil
+}
+
+// Disable supprime la planification (les exécutions manuelles restent possibles)
+func (s *Scheduler) Disable() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if s.entry != 0 {
+		s.cron.Remove(s.entry)
+	}
+	s.entry, s.spec = 0, ""
+	return nil
+}
+
+// Stop arrête le planificateur et attend la fin de l'exécution en cours
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// apply remplace l'entrée du planificateur (appelant verrouillé ou avant démarrage)
+func (s *Scheduler) apply(spec string) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	if s.entry != 0 {
+		s.cron.Remove(s.entry)
+	}
+	s.entry = s.cron.Schedule(schedule, cron.FuncJob(s.tick))
+	s.spec = spec
+	return nil
+}
+
+// tick lance une exécution, sauf si la précédente est encore en cours
+func (s *Scheduler) tick() {
+	if !s.running.CompareAndSwap(false, true) {
+		logger.LogInfo("Exécution planifiée ignorée: la précédente est encore en cours", nil)
+		return
+	}
+	defer s.running.Store(false)
+	s.run()
+}
+
+// save écrit la planification via un fichier temporaire renommé
+func (s *Scheduler) save(spec string) error {
+	content, err := json.MarshalIndent(persisted{Schedule: spec}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
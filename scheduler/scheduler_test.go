@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du calcul de la prochaine échéance
+func TestNextRun(t *testing.T) {
+	from := time.Date(2024, 3, 1, 5, 30, 0, 0, time.Local)
+
+	next, err := NextRun("0 */6 * * *", from)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 6, 0, 0, 0, time.Local), next)
+
+	next, err = NextRun("@every 2h", from)
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(2*time.Hour), next)
+
+	next, err = NextRun("@daily", from)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local), next)
+
+	for _, spec := range []string{"", "toutes les heures", "* * *", "@every"} {
+		_, err := NextRun(spec, from)
+		assert.ErrorIs(t, err, ErrInvalidSchedule, spec)
+	}
+}
+
+// Test de la définition, de la consultation et de la désactivation de la planification
+func TestSchedulerSetViewDisable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scraper", "scraper_schedule.json")
+	now := time.Date(2024, 3, 1, 5, 30, 0, 0, time.Local)
+
+	s, err := New(path, func() {})
+	require.NoError(t, err)
+	defer s.Stop()
+	s.now = func() time.Time { return now }
+
+	// Sans fichier, la planification est désactivée
+	assert.Equal(t, Status{}, s.Status())
+
+	status, err := s.Set(" 0 */6 * * * ")
+	require.NoError(t, err)
+	assert.Equal(t, "0 */6 * * *", status.Schedule)
+	assert.True(t, status.Enabled)
+	require.NotNil(t, status.NextRun)
+	assert.Equal(t, time.Date(2024, 3, 1, 6, 0, 0, 0, time.Local), *status.NextRun)
+
+	// Une planification invalide est refusée et ne remplace pas l'active
+	_, err = s.Set("toutes les heures")
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+	assert.Equal(t, "0 */6 * * *", s.Status().Schedule)
+
+	// La planification est persistée et rechargée au démarrage suivant
+	reloaded, err := New(path, func() {})
+	require.NoError(t, err)
+	assert.Equal(t, "0 */6 * * *", reloaded.Status().Schedule)
+	reloaded.Stop()
+
+	require.NoError(t, s.Disable())
+	assert.Equal(t, Status{}, s.Status())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, s.Disable(), "désactiver deux fois n'est pas une erreur")
+}
+
+// Test de l'exécution planifiée : déclenchée à l'échéance, ignorée si la précédente tourne encore
+func TestSchedulerRuns(t *testing.T) {
+	var runs atomic.Int32
+	fired := make(chan struct{}, 10)
+	s, err := New(filepath.Join(t.TempDir(), "scraper_schedule.json"), func() {
+		runs.Add(1)
+		fired <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer s.Stop()
+
+	_, err = s.Set("@every 1s")
+	require.NoError(t, err)
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("exécution planifiée non déclenchée")
+	}
+	require.NoError(t, s.Disable())
+
+	// Une échéance pendant une exécution est ignorée
+	s.running.Store(true)
+	before := runs.Load()
+	s.tick()
+	assert.Equal(t, before, runs.Load())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// Paramètres du délai adaptatif entre requêtes (AIMD)
+// Un blocage (403/429) multiplie le délai, chaque réponse propre le réduit d'un pas fixe
+const (
+	adaptiveMinDelay = 2 * time.Second        // Délai plancher, identique à l'ancien Delay statique
+	adaptiveMaxDelay = 30 * time.Second       // Plafond, sous le seuil de détection des workers bloqués
+	adaptiveStep     = 250 * time.Millisecond // Réduction par réponse propre
+	adaptiveIncrease = 2.0                    // Facteur appliqué à chaque blocage
+)
+
+// recipeLimiter est partagé par tous les collecteurs de recettes (un collecteur par recette)
+// Un blocage observé par un worker ralentit donc tous les autres
+var recipeLimiter = newAdaptiveLimiter(adaptiveMinDelay, adaptiveMaxDelay, 0)
+
+// adaptiveLimiter ajuste le délai avant chaque requête selon les réponses observées
+// Thread-safe grâce au Mutex : les workers le consultent et le mettent à jour en parallèle
+type adaptiveLimiter struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	min    time.Duration
+	max    time.Duration
+	jitter time.Duration       // Part aléatoire ajoutée à chaque attente (0 = aucune)
+	sleep  func(time.Duration) // Remplaçable dans les tests
+}
+
+// newAdaptiveLimiter crée un limiteur démarrant au délai minimal
+func newAdaptiveLimiter(min, max, jitter time.Duration) *adaptiveLimiter {
+	return &adaptiveLimiter{delay: min, min: min, max: max, jitter: jitter, sleep: time.Sleep}
+}
+
+// Delay retourne le délai courant
+func (l *adaptiveLimiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.delay
+}
+
+// Observe met à jour le délai à partir du statut d'une réponse
+// 403/429 : augmentation multiplicative ; 2xx/3xx : diminution additive ; autres erreurs : inchangé
+func (l *adaptiveLimiter) Observe(statusCode int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.delay
+	switch {
+	case statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests:
+		l.delay = time.Duration(float64(l.delay) * adaptiveIncrease)
+		if l.delay > l.max {
+			l.delay = l.max
+		}
+		if l.delay != previous {
+			logAdaptiveDelay(statusCode, previous, l.delay)
+		}
+	case statusCode >= 200 && statusCode < 400:
+		l.delay -= adaptiveStep
+		if l.delay < l.min {
+			l.delay = l.min
+		}
+	}
+}
+
+// Wait attend le délai courant, plus une part aléatoire éventuelle
+func (l *adaptiveLimiter) Wait() {
+	l.mu.Lock()
+	delay := l.delay
+	if l.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(l.jitter) + 1))
+	}
+	l.mu.Unlock()
+	l.sleep(delay)
+}
+
+// track branche le limiteur sur un collecteur : attente avant chaque requête, ajustement à chaque réponse
+// À utiliser à la place de Delay/RandomDelay dans LimitRule
+func (l *adaptiveLimiter) track(collector *colly.Collector) {
+	collector.OnRequest(func(r *colly.Request) {
+		l.Wait()
+	})
+	collector.OnResponse(func(r *colly.Response) {
+		l.Observe(r.StatusCode)
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		l.Observe(r.StatusCode)
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test du délai adaptatif : une rafale de 429 l'augmente, des réponses propres le ramènent au plancher
+func TestAdaptiveLimiterBurst(t *testing.T) {
+	limiter := newAdaptiveLimiter(time.Second, 10*time.Second, 0)
+	assert.Equal(t, time.Second, limiter.Delay())
+
+	limiter.Observe(http.StatusTooManyRequests)
+	assert.Equal(t, 2*time.Second, limiter.Delay())
+	limiter.Observe(http.StatusTooManyRequests)
+	limiter.Observe(http.StatusForbidden)
+	assert.Equal(t, 8*time.Second, limiter.Delay())
+
+	// Plafonné au maximum
+	limiter.Observe(http.StatusTooManyRequests)
+	assert.Equal(t, 10*time.Second, limiter.Delay())
+
+	// Les erreurs qui ne sont pas des blocages ne changent rien
+	limiter.Observe(http.StatusNotFound)
+	limiter.Observe(http.StatusInternalServerError)
+	assert.Equal(t, 10*time.Second, limiter.Delay())
+
+	// Diminution additive, jamais sous le plancher
+	limiter.Observe(http.StatusOK)
+	assert.Equal(t, 10*time.Second-adaptiveStep, limiter.Delay())
+	for i := 0; i < 100; i++ {
+		limiter.Observe(http.StatusOK)
+	}
+	assert.Equal(t, time.Second, limiter.Delay())
+}
+
+// Test du limiteur branché sur un collecteur : les attentes suivent les 429 renvoyés par le serveur
+func TestAdaptiveLimiterTracksCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("status") == "429" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	limiter := newAdaptiveLimiter(100*time.Millisecond, time.Second, 0)
+	var mu sync.Mutex
+	var waits []time.Duration
+	limiter.sleep = func(d time.Duration) {
+		mu.Lock()
+		waits = append(waits, d)
+		mu.Unlock()
+	}
+
+	collector := colly.NewCollector()
+	limiter.track(collector)
+	for i := 0; i < 3; i++ {
+		collector.Visit(fmt.Sprintf("%s/?status=429&i=%d", server.URL, i))
+	}
+	collector.Visit(server.URL + "/?status=200")
+
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+	}, waits)
+	assert.Equal(t, 800*time.Millisecond-adaptiveStep, limiter.Delay())
+}
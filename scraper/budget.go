@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gocolly/colly"
+)
+
+// errBudgetExhausted est retourné à la place d'une visite quand le budget de requêtes est consommé
+var errBudgetExhausted = errors.New("budget de requêtes atteint (-max-requests)")
+
+// crawlBudget borne le nombre total de requêtes de l'exécution (configuré par main depuis -max-requests)
+var crawlBudget = newRequestBudget(0)
+
+// requestBudget compte les requêtes émises et refuse les suivantes une fois le maximum atteint
+// Thread-safe : la réservation atomique évite que des workers concurrents dépassent le budget
+type requestBudget struct {
+	max       int64 // 0 = illimité
+	used      atomic.Int64
+	exhausted sync.Once
+}
+
+// newRequestBudget crée un budget de max requêtes (0 = illimité)
+func newRequestBudget(max int64) *requestBudget {
+	return &requestBudget{max: max}
+}
+
+// Take réserve une requête, false si le budget est épuisé
+func (b *requestBudget) Take() bool {
+	if b.max <= 0 {
+		return true
+	}
+	if b.used.Add(1) <= b.max {
+		return true
+	}
+	b.exhausted.Do(func() { logBudgetExhausted(b.max) })
+	return false
+}
+
+// Exhausted indique si plus aucune requête ne peut être émise
+func (b *requestBudget) Exhausted() bool {
+	return b.max > 0 && b.used.Load() >= b.max
+}
+
+// visitWithBudget visite une URL si le budget le permet
+func visitWithBudget(collector *colly.Collector, url string) error {
+	if !crawlBudget.Take() {
+		return errBudgetExhausted
+	}
+	return collector.Visit(url)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de la réservation concurrente : jamais plus de requêtes que le budget
+func TestRequestBudget(t *testing.T) {
+	budget := newRequestBudget(10)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	taken := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if budget.Take() {
+				mutex.Lock()
+				taken++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, taken)
+	assert.True(t, budget.Exhausted())
+
+	// 0 = illimité
+	unlimited := newRequestBudget(0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, unlimited.Take())
+	}
+	assert.False(t, unlimited.Exhausted())
+
+	o, err := parseOptions([]string{"-max-requests", "500"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), o.MaxRequests)
+	_, err = parseOptions([]string{"-max-requests", "-1"}, io.Discard)
+	assert.Error(t, err)
+}
+
+// Test du budget sur une exécution complète : catégorie et recettes restent sous -max-requests
+func TestMaxRequestsCapsRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if strings.HasPrefix(r.URL.Path, "/recipe/") {
+			w.Write([]byte(recipePageHTML))
+			return
+		}
+		var cards strings.Builder
+		for i := 1; i <= 5; i++ {
+			fmt.Fprintf(&cards, `<a class="mntl-card" href="/recipe/budget-%d"><span class="card__title-text">Recette %d</span></a>`, i, i)
+		}
+		fmt.Fprintf(w, `<html><body><div class="mntl-taxonomysc-article-list-group">%s</div></body></html>`, cards.String())
+	}))
+	defer server.Close()
+
+	saved := crawlBudget
+	defer func() { crawlBudget = saved }()
+	const budget = 3
+	crawlBudget = newRequestBudget(budget)
+
+	stats := NewScrapingStats(2)
+	recipeURLs := make(chan RecipeData, 10)
+	completedRecipes := make(chan Recipe, 10)
+	done := make(chan bool)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	var wg sync.WaitGroup
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, nil, nil)
+	startRecipeProcessor(recipeURLs, completedRecipes, stats, &wg, nil)
+
+	collector := createMainCollectorWithPagination(stats, recipeURLs, 1, nil, nil)
+	require.NoError(t, visitWithBudget(collector, server.URL+"/recipes/79/desserts/"))
+	close(recipeURLs)
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("le pipeline ne s'est pas terminé")
+	}
+
+	// Une requête pour la catégorie, deux pour les recettes, les trois autres recettes ne sont pas visitées
+	assert.LessOrEqual(t, stats.GetTotalRequests(), int64(budget))
+	assert.Equal(t, int64(5), stats.RecipesFound)
+	assert.Equal(t, int64(2), stats.RecipesCompleted)
+	assert.Equal(t, int64(3), stats.RecipesSkipped)
+	assert.ErrorIs(t, visitWithBudget(collector, server.URL+"/recipes/80/drinks/"), errBudgetExhausted)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// runCategory parcourt une seule catégorie avec le collecteur principal et le traitement des workers,
+// et écrit ses recettes en NDJSON sur w au fil de l'eau. Utilisé par l'API (POST /scraper/category)
+// maxPages compte la première page : 1 = pas de pagination
+// Retourne le code de sortie : 0 si au moins une recette a été extraite, 1 sinon (raison écrite sur errOut)
+func runCategory(categoryURL string, maxPages int, w, errOut io.Writer) int {
+	resetRunState()
+	addCustomCookies(categoryURL)
+
+	stats := NewScrapingStats(resolveWorkerSizing(1, 100).Workers)
+	recipeURLs := make(chan RecipeData, opts.URLBuffer)
+	completedRecipes := make(chan Recipe, opts.RecipeBuffer)
+	done := make(chan bool)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	var wg sync.WaitGroup
+
+	stream := NewRecipeWriter(w, formatNDJSON, true)
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, stream, nil)
+	startRecipeProcessor(recipeURLs, completedRecipes, stats, &wg, nil)
+
+	// Le collecteur compte les pages suivantes, pas la première
+	collector := createMainCollectorWithPagination(stats, recipeURLs, maxPages-1, nil, nil)
+	visitErr := visitWithBudget(collector, categoryURL)
+	close(recipeURLs)
+	<-done
+
+	if err := stream.Close(); err != nil {
+		fmt.Fprintf(errOut, "Erreur d'écriture des recettes: %v\n", err)
+		return 1
+	}
+	if len(recipes) == 0 {
+		if visitErr != nil {
+			fmt.Fprintf(errOut, "Catégorie %s inaccessible: %v\n", redactURI(categoryURL), redactURI(visitErr.Error()))
+		} else {
+			fmt.Fprintf(errOut, "Aucune recette extraite de %s\n", redactURI(categoryURL))
+		}
+		return 1
+	}
+	return 0
+}
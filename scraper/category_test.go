@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// categoryFixtureServer sert une catégorie de trois pages de deux recettes, reliées par pagination-next
+func categoryFixtureServer(t *testing.T, pageRequests *int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if strings.HasPrefix(r.URL.Path, "/recipe/") {
+			w.Write([]byte(recipePageHTML))
+			return
+		}
+		if r.URL.Path != "/recipes/79/desserts/" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt64(pageRequests, 1)
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+
+		var body strings.Builder
+		body.WriteString(`<html><body><div class="mntl-taxonomysc-article-list-group">`)
+		for i := 1; i <= 2; i++ {
+			fmt.Fprintf(&body, `<a class="mntl-card" href="/recipe/p%d-%d"><span class="card__title-text">Dessert %d-%d</span></a>`, page, i, page, i)
+		}
+		body.WriteString(`</div>`)
+		if page < 3 {
+			fmt.Fprintf(&body, `<a data-testid="pagination-next" href="/recipes/79/desserts/?page=%d">Suivant</a>`, page+1)
+		}
+		body.WriteString(`</body></html>`)
+		w.Write([]byte(body.String()))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Test d'une catégorie parcourue seule : les recettes des pages autorisées sont écrites en NDJSON
+func TestRunCategory(t *testing.T) {
+	var pageRequests int64
+	server := categoryFixtureServer(t, &pageRequests)
+
+	var out, errOut bytes.Buffer
+	code := runCategory(server.URL+"/recipes/79/desserts/", 2, &out, &errOut)
+	require.Equal(t, 0, code, errOut.String())
+
+	var pages []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var recipe Recipe
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &recipe))
+		assert.NotEmpty(t, recipe.Ingredients)
+		assert.Equal(t, "desserts", recipe.Category)
+		pages = append(pages, strings.TrimPrefix(recipe.Page, server.URL))
+	}
+	// Deux pages sur trois : la troisième n'est pas demandée
+	assert.ElementsMatch(t, []string{"/recipe/p1-1", "/recipe/p1-2", "/recipe/p2-1", "/recipe/p2-2"}, pages)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&pageRequests))
+}
+
+// Test d'une catégorie sans recette : code 1 et raison sur la sortie d'erreur
+func TestRunCategoryNotFound(t *testing.T) {
+	var pageRequests int64
+	server := categoryFixtureServer(t, &pageRequests)
+
+	var out, errOut bytes.Buffer
+	assert.Equal(t, 1, runCategory(server.URL+"/recipes/80/absente/", 1, &out, &errOut))
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), "inaccessible")
+}
+
+// Test des options -category et -category-pages
+func TestCategoryOptions(t *testing.T) {
+	o, err := parseOptions([]string{"-category", "https://www.allrecipes.com/recipes/79/desserts/", "-category-pages", "5"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.allrecipes.com/recipes/79/desserts/", o.CategoryURL)
+	assert.Equal(t, 5, o.CategoryPages)
+
+	o, err = parseOptions(nil, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, 3, o.CategoryPages)
+
+	for _, args := range [][]string{
+		{"-category", "/recipes/79/desserts/"},
+		{"-category-pages", "0"},
+		{"-category-pages", "21"},
+	} {
+		_, err := parseOptions(args, io.Discard)
+		assert.Error(t, err, args)
+	}
+}
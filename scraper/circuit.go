@@ -0,0 +1,184 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// États du disjoncteur
+const (
+	circuitClosed   = "closed"    // Requêtes normales
+	circuitOpen     = "open"      // Site en train de bloquer : plus aucune requête jusqu'à la fin de la pause
+	circuitHalfOpen = "half-open" // Fin de pause : une seule requête de test décide de la réouverture
+)
+
+// Paramètres du disjoncteur partagé par les collecteurs
+const (
+	circuitWindow       = 20               // Nombre de dernières réponses prises en compte
+	circuitMinSamples   = 10               // Réponses nécessaires avant de pouvoir ouvrir le circuit
+	circuitThreshold    = 0.5              // Proportion de blocages au-delà de laquelle le circuit s'ouvre
+	circuitCooldown     = 60 * time.Second // Pause avant la requête de test
+	circuitPollInterval = time.Second      // Attente des workers pendant qu'une requête de test est en cours
+	circuitProbeTimeout = 2 * time.Minute  // Requête de test sans réponse (abandonnée avant Record) : remplacée
+)
+
+// siteBreaker est partagé par tous les collecteurs : un blocage concerne tout le site
+var siteBreaker = newCircuitBreaker(circuitWindow, circuitMinSamples, circuitThreshold, circuitCooldown)
+
+// circuitBreaker suspend les requêtes quand la proportion de blocages (403, 429, 503) devient trop élevée
+// Thread-safe grâce au Mutex : consulté par tous les workers avant chaque requête
+type circuitBreaker struct {
+	mu         sync.Mutex
+	state      string
+	outcomes   []bool // Fenêtre circulaire des dernières réponses (true = blocage)
+	next       int
+	filled     int
+	minSamples int
+	threshold  float64
+	cooldown   time.Duration
+	openedAt   time.Time
+	probing    bool // Requête de test en cours (état half-open)
+	probeStart time.Time
+	probeLimit time.Duration // Au-delà, la requête de test est considérée perdue
+
+	now   func() time.Time    // Horloge (remplaçable dans les tests)
+	sleep func(time.Duration) // Attente (remplaçable dans les tests)
+}
+
+// newCircuitBreaker crée un disjoncteur fermé
+func newCircuitBreaker(window, minSamples int, threshold float64, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		state:      circuitClosed,
+		outcomes:   make([]bool, window),
+		minSamples: minSamples,
+		threshold:  threshold,
+		cooldown:   cooldown,
+		probeLimit: circuitProbeTimeout,
+		now:        time.Now,
+		sleep:      time.Sleep,
+	}
+}
+
+// State retourne l'état courant
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// tryAcquire indique si une requête peut partir, sinon combien de temps attendre avant de réessayer
+func (b *circuitBreaker) tryAcquire() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if remaining := b.cooldown - b.now().Sub(b.openedAt); remaining > 0 {
+			return remaining, false
+		}
+		// Fin de la pause : l'appelant devient la requête de test
+		b.setState(circuitHalfOpen)
+		b.startProbe()
+		return 0, true
+	case circuitHalfOpen:
+		// Une requête de test annulée avant sa réponse n'appelle jamais Record : elle est remplacée après probeLimit
+		if b.probing && b.now().Sub(b.probeStart) < b.probeLimit {
+			return circuitPollInterval, false
+		}
+		b.startProbe()
+		return 0, true
+	default:
+		return 0, true
+	}
+}
+
+// startProbe fait de l'appelant la requête de test (appelant verrouillé)
+func (b *circuitBreaker) startProbe() {
+	b.probing = true
+	b.probeStart = b.now()
+}
+
+// Acquire bloque jusqu'à ce que le disjoncteur autorise une requête
+func (b *circuitBreaker) Acquire() {
+	for {
+		wait, ok := b.tryAcquire()
+		if ok {
+			return
+		}
+		b.sleep(wait)
+	}
+}
+
+// Record enregistre le statut d'une réponse (0 = pas de réponse HTTP)
+func (b *circuitBreaker) Record(statusCode int) {
+	blocked := statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitHalfOpen:
+		if !b.probing {
+			return // Réponse d'une requête partie avant l'ouverture
+		}
+		b.probing = false
+		if blocked {
+			b.open()
+			return
+		}
+		// Le site répond de nouveau : la fenêtre repart de zéro
+		b.filled, b.next = 0, 0
+		b.setState(circuitClosed)
+	case circuitClosed:
+		b.outcomes[b.next] = blocked
+		b.next = (b.next + 1) % len(b.outcomes)
+		if b.filled < len(b.outcomes) {
+			b.filled++
+		}
+		if b.filled >= b.minSamples && b.blockedRate() > b.threshold {
+			b.open()
+		}
+	}
+}
+
+// blockedRate retourne la proportion de blocages dans la fenêtre (appelant verrouillé)
+func (b *circuitBreaker) blockedRate() float64 {
+	blocked := 0
+	for i := 0; i < b.filled; i++ {
+		if b.outcomes[i] {
+			blocked++
+		}
+	}
+	return float64(blocked) / float64(b.filled)
+}
+
+// open ouvre le circuit pour la durée de la pause (appelant verrouillé)
+func (b *circuitBreaker) open() {
+	b.openedAt = b.now()
+	b.setState(circuitOpen)
+}
+
+// setState change d'état et le logge (appelant verrouillé)
+func (b *circuitBreaker) setState(state string) {
+	if b.state == state {
+		return
+	}
+	logCircuitState(b.state, state, b.cooldown)
+	b.state = state
+}
+
+// track branche le disjoncteur sur un collecteur : autorisation avant chaque requête, bilan à chaque réponse
+func (b *circuitBreaker) track(collector *colly.Collector) {
+	collector.OnRequest(func(r *colly.Request) {
+		b.Acquire()
+	})
+	collector.OnResponse(func(r *colly.Response) {
+		b.Record(r.StatusCode)
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		b.Record(r.StatusCode)
+	})
+}
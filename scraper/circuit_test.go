@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBreaker crée un disjoncteur sur une horloge simulée (fenêtre de 4 réponses, 2 minimum, seuil 50%)
+func newTestBreaker() (*circuitBreaker, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(4, 2, 0.5, time.Minute)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+// Test des transitions fermé → ouvert → semi-ouvert → fermé
+func TestCircuitBreakerTransitions(t *testing.T) {
+	b, now := newTestBreaker()
+
+	// Un blocage isolé ne suffit pas (minimum de réponses non atteint)
+	b.Record(429)
+	assert.Equal(t, circuitClosed, b.State())
+
+	// 50% de blocages ne dépasse pas le seuil
+	b.Record(200)
+	assert.Equal(t, circuitClosed, b.State())
+
+	// Les 404 et erreurs réseau ne sont pas des blocages
+	b.Record(404)
+	b.Record(0)
+	assert.Equal(t, circuitClosed, b.State())
+
+	// La fenêtre glisse : 3 blocages sur les 4 dernières réponses ouvrent le circuit
+	b.Record(403)
+	b.Record(503)
+	b.Record(429)
+	assert.Equal(t, circuitOpen, b.State())
+
+	// Pendant la pause, aucune requête ne part
+	wait, ok := b.tryAcquire()
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, wait)
+
+	*now = now.Add(40 * time.Second)
+	wait, ok = b.tryAcquire()
+	assert.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Fin de la pause : une seule requête de test
+	*now = now.Add(20 * time.Second)
+	_, ok = b.tryAcquire()
+	assert.True(t, ok)
+	assert.Equal(t, circuitHalfOpen, b.State())
+
+	wait, ok = b.tryAcquire()
+	assert.False(t, ok)
+	assert.Equal(t, circuitPollInterval, wait)
+
+	// La requête de test réussit : le circuit se referme avec une fenêtre vide
+	b.Record(200)
+	assert.Equal(t, circuitClosed, b.State())
+	_, ok = b.tryAcquire()
+	assert.True(t, ok)
+
+	b.Record(429)
+	assert.Equal(t, circuitClosed, b.State())
+}
+
+// Test d'une requête de test bloquée : le circuit se rouvre pour une nouvelle pause
+func TestCircuitBreakerProbeFailure(t *testing.T) {
+	b, now := newTestBreaker()
+	b.Record(429)
+	b.Record(429)
+	assert.Equal(t, circuitOpen, b.State())
+
+	*now = now.Add(time.Minute)
+	_, ok := b.tryAcquire()
+	assert.True(t, ok)
+
+	b.Record(429)
+	assert.Equal(t, circuitOpen, b.State())
+
+	wait, ok := b.tryAcquire()
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, wait)
+}
+
+// Test de l'attente bloquante jusqu'à la fin de la pause
+func TestCircuitBreakerAcquireWaits(t *testing.T) {
+	b, now := newTestBreaker()
+	var slept []time.Duration
+	b.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		*now = now.Add(d)
+	}
+
+	b.Record(403)
+	b.Record(403)
+	b.Acquire()
+
+	assert.Equal(t, []time.Duration{time.Minute}, slept)
+	assert.Equal(t, circuitHalfOpen, b.State())
+}
+
+// Test d'une requête de test sans réponse : un autre worker la remplace après probeLimit
+func TestCircuitBreakerLostProbe(t *testing.T) {
+	b, now := newTestBreaker()
+	b.Record(429)
+	b.Record(429)
+
+	*now = now.Add(time.Minute)
+	_, ok := b.tryAcquire()
+	assert.True(t, ok)
+
+	// Requête de test en cours : les autres workers attendent
+	wait, ok := b.tryAcquire()
+	assert.False(t, ok)
+	assert.Equal(t, circuitPollInterval, wait)
+
+	// Requête de test abandonnée sans Record : remplacée une fois le délai dépassé
+	*now = now.Add(circuitProbeTimeout)
+	_, ok = b.tryAcquire()
+	assert.True(t, ok)
+	_, ok = b.tryAcquire()
+	assert.False(t, ok)
+
+	b.Record(200)
+	assert.Equal(t, circuitClosed, b.State())
+}
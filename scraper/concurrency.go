@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Modèles de concurrence du traitement des recettes (-concurrency-model)
+const (
+	concurrencyPool      = "pool"      // maxWorkers workers fixes lisant la file (défaut historique)
+	concurrencySemaphore = "semaphore" // Une goroutine par recette, bornée par un sémaphore de maxWorkers places
+)
+
+// parseConcurrencyModel valide la valeur de -concurrency-model
+func parseConcurrencyModel(value string) (string, error) {
+	switch value {
+	case concurrencyPool, concurrencySemaphore:
+		return value, nil
+	}
+	return "", fmt.Errorf("modèle de concurrence invalide %q: pool ou semaphore attendu", value)
+}
+
+// recipeProcessFunc traite une recette pour le compte d'un worker (processRecipeReusable hors benchmarks)
+type recipeProcessFunc func(recipeData RecipeData, workerStats *WorkerStats)
+
+// runRecipePool traite les recettes avec maxWorkers workers réutilisables et attend leur fin
+// Chaque worker traite une recette à la fois : le nombre de workers est la seule limite de concurrence
+func runRecipePool(recipeURLs <-chan RecipeData, stats *ScrapingStats, wg *sync.WaitGroup, maxWorkers int, process recipeProcessFunc) {
+	// Créer des workers réutilisables
+	for i := 0; i < maxWorkers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			workerStats := WorkerStats{
+				WorkerID:         workerID,
+				RequestsHandled:  0,
+				RecipesProcessed: 0,
+				StartTime:        time.Now(),
+			}
+
+			logWorkerStarted(workerID)
+
+			// Le worker traite les recettes en continu
+			for recipeData := range recipeURLs {
+				// Log de la queue
+				queueLength := len(recipeURLs)
+				logWorkerQueue(workerID, queueLength)
+
+				// Traiter la recette
+				process(recipeData, &workerStats)
+			}
+
+			// La file est fermée et vidée : enregistrer les stats finales du worker, une seule fois
+			workerStats = stats.FinishWorker(workerStats)
+			logWorkerFinished(workerID, workerStats.RequestsHandled, workerStats.RecipesProcessed, workerStats.Duration)
+		}(i)
+	}
+
+	logWorkersReady(maxWorkers)
+
+	// Attendre que toutes les goroutines se terminent
+	wg.Wait()
+}
+
+// runRecipeSemaphore lance une goroutine par recette, au plus maxWorkers à la fois, et attend leur fin
+// Le sémaphore distribue des numéros de slot : les stats et le heartbeat d'un slot ne sont jamais
+// partagés par deux goroutines en même temps, et stats.json garde un détail « par worker »
+func runRecipeSemaphore(recipeURLs <-chan RecipeData, stats *ScrapingStats, wg *sync.WaitGroup, maxWorkers int, process recipeProcessFunc) {
+	slots := make(chan int, maxWorkers)
+	slotStats := make([]WorkerStats, maxWorkers)
+	for i := 0; i < maxWorkers; i++ {
+		slots <- i
+		slotStats[i] = WorkerStats{WorkerID: i, StartTime: time.Now()}
+	}
+
+	logWorkersReady(maxWorkers)
+
+	for recipeData := range recipeURLs {
+		// Bloquant tant que maxWorkers recettes sont en cours
+		slotID := <-slots
+		logWorkerQueue(slotID, len(recipeURLs))
+		wg.Add(1)
+		go func(recipeData RecipeData, slotID int) {
+			defer wg.Done()
+			defer func() { slots <- slotID }()
+			process(recipeData, &slotStats[slotID])
+		}(recipeData, slotID)
+	}
+
+	// Attendre que toutes les goroutines se terminent
+	wg.Wait()
+
+	// Toutes les goroutines ont rendu leur slot : enregistrer les stats de chaque slot, une seule fois
+	for _, workerStats := range slotStats {
+		workerStats = stats.FinishWorker(workerStats)
+		logWorkerFinished(workerStats.WorkerID, workerStats.RequestsHandled, workerStats.RecipesProcessed, workerStats.Duration)
+	}
+}
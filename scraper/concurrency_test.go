@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runPipeline traite urls avec le modèle de concurrence donné et retourne les pages des recettes complétées
+func runPipeline(t *testing.T, model string, urls []string) ([]string, *ScrapingStats) {
+	t.Helper()
+	saved := opts
+	defer func() { opts = saved }()
+	opts.ConcurrencyModel = model
+
+	stats := NewScrapingStats(3)
+	recipeURLs := make(chan RecipeData, len(urls))
+	completedRecipes := make(chan Recipe, len(urls))
+	done := make(chan bool)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	var wg sync.WaitGroup
+
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, nil, nil)
+	startRecipeProcessor(recipeURLs, completedRecipes, stats, &wg, nil)
+	for i, url := range urls {
+		stats.IncrementRecipesFound()
+		recipeURLs <- RecipeData{URL: url, Title: fmt.Sprintf("Soupe %d", i)}
+	}
+	close(recipeURLs)
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("le pipeline %s ne s'est pas terminé", model)
+	}
+
+	recipesMutex.RLock()
+	defer recipesMutex.RUnlock()
+	pages := make([]string, 0, len(recipes))
+	for _, recipe := range recipes {
+		pages = append(pages, recipe.Page)
+	}
+	sort.Strings(pages)
+	return pages, stats
+}
+
+// Test des deux modèles de concurrence : mêmes recettes complétées, quelle que soit leur durée
+func TestConcurrencyModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Des recettes de durées différentes
+		if len(r.URL.Path)%2 == 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	var urls []string
+	for i := 0; i < 6; i++ {
+		urls = append(urls, fmt.Sprintf("%s/recipe/%d", server.URL, i*7))
+	}
+
+	o, err := parseOptions([]string{"-concurrency-model", "semaphore"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, concurrencySemaphore, o.ConcurrencyModel)
+	assert.Equal(t, concurrencyPool, defaultOptions().ConcurrencyModel)
+	_, err = parseOptions([]string{"-concurrency-model", "threads"}, io.Discard)
+	assert.Error(t, err)
+
+	poolPages, poolStats := runPipeline(t, concurrencyPool, urls)
+	semaphorePages, semaphoreStats := runPipeline(t, concurrencySemaphore, urls)
+
+	assert.Len(t, poolPages, len(urls))
+	assert.Equal(t, poolPages, semaphorePages)
+	for _, stats := range []*ScrapingStats{poolStats, semaphoreStats} {
+		assert.Equal(t, int64(len(urls)), stats.RecipesCompleted)
+		assert.Len(t, stats.WorkerStats, 3)
+	}
+}
+
+// Test des stats par worker (à lancer avec -race) : chaque recette est comptée une seule fois,
+// par un seul worker, et jamais plus de MaxWorkers recettes ne sont traitées en même temps
+func TestWorkerStatsAccounting(t *testing.T) {
+	var hits, inFlight, peak int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		current := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+		for {
+			previous := atomic.LoadInt64(&peak)
+			if current <= previous || atomic.CompareAndSwapInt64(&peak, previous, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Path == "/recipe/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	urls := []string{server.URL + "/recipe/missing"}
+	for i := 0; i < 5; i++ {
+		urls = append(urls, fmt.Sprintf("%s/recipe/%d", server.URL, i))
+	}
+
+	for _, model := range []string{concurrencyPool, concurrencySemaphore} {
+		t.Run(model, func(t *testing.T) {
+			atomic.StoreInt64(&hits, 0)
+			atomic.StoreInt64(&peak, 0)
+
+			pages, stats := runPipeline(t, model, urls)
+			require.Len(t, pages, 5)
+
+			var requests, recipes int64
+			for _, worker := range stats.SortedWorkerStats() {
+				requests += worker.RequestsHandled
+				recipes += worker.RecipesProcessed
+				assert.False(t, worker.EndTime.IsZero(), "worker %d non terminé", worker.WorkerID)
+			}
+			assert.Len(t, stats.WorkerStats, stats.MaxWorkers)
+			assert.Equal(t, stats.RecipesCompleted, recipes)
+			assert.Equal(t, int64(5), recipes)
+			assert.Equal(t, atomic.LoadInt64(&hits), requests, "requêtes des workers")
+			assert.Equal(t, stats.RecipeRequests, requests)
+			assert.LessOrEqual(t, atomic.LoadInt64(&peak), int64(stats.MaxWorkers))
+
+			// Un enregistrement répété ne cumule pas les compteurs
+			worker := stats.WorkerStats[0]
+			stats.FinishWorker(worker)
+			assert.Equal(t, worker.RecipesProcessed, stats.WorkerStats[0].RecipesProcessed)
+		})
+	}
+}
+
+// Benchmark de la distribution des recettes aux workers, avec un traitement simulé d'1ms (attente réseau)
+// Compare les deux modèles de concurrence à nombre de workers égal
+func BenchmarkRecipeProcessor(b *testing.B) {
+	const workers = 8
+	const recipesPerRun = 64
+	process := func(recipeData RecipeData, workerStats *WorkerStats) {
+		time.Sleep(time.Millisecond)
+		workerStats.RecipesProcessed++
+	}
+	run := map[string]func(<-chan RecipeData, *ScrapingStats, *sync.WaitGroup, int, recipeProcessFunc){
+		concurrencyPool:      runRecipePool,
+		concurrencySemaphore: runRecipeSemaphore,
+	}
+
+	for _, model := range []string{concurrencyPool, concurrencySemaphore} {
+		b.Run(model, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				stats := NewScrapingStats(workers)
+				recipeURLs := make(chan RecipeData, recipesPerRun)
+				for j := 0; j < recipesPerRun; j++ {
+					recipeURLs <- RecipeData{URL: fmt.Sprintf("https://example.com/recipe/%d", j)}
+				}
+				close(recipeURLs)
+
+				var wg sync.WaitGroup
+				run[model](recipeURLs, stats, &wg, workers, process)
+			}
+			b.ReportMetric(float64(b.N*recipesPerRun)/b.Elapsed().Seconds(), "recipes/s")
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFilename est le fichier de configuration lu dans le répertoire courant (modifiable par l'API)
+const defaultConfigFilename = "scraper_config.json"
+
+// ScraperConfig est le contenu du fichier de configuration du scraper, défini avec l'API dans scraperspec
+type ScraperConfig = scraperspec.Config
+
+// defaultScraperConfig retourne la configuration utilisée en l'absence de fichier
+func defaultScraperConfig() ScraperConfig {
+	return ScraperConfig{Categories: append([]string(nil), scraperspec.DefaultCategories...)}
+}
+
+// loadScraperConfig charge la configuration depuis le disque (un fichier absent donne la configuration par défaut)
+func loadScraperConfig(path string) (ScraperConfig, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultScraperConfig(), nil
+	}
+	if err != nil {
+		return ScraperConfig{}, err
+	}
+
+	config, err := decodeScraperConfig(path, content)
+	if err != nil {
+		return ScraperConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	// Références ${VAR} : les secrets restent dans l'environnement plutôt que dans le fichier
+	for _, name := range expandConfigEnv(&config) {
+		logConfigEnvUnset(path, name)
+	}
+	if err := validateScraperConfig(config); err != nil {
+		return ScraperConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// configWatcher fournit la configuration de chaque exécution
+// Avec -watch-config, le fichier est relu avant chaque exécution : une configuration invalide est ignorée
+// et la précédente reste active, pour qu'une erreur d'édition n'arrête pas un scraper lancé en service
+type configWatcher struct {
+	path  string
+	watch bool
+
+	mu     sync.Mutex
+	active ScraperConfig
+}
+
+// newConfigWatcher charge la configuration initiale (une erreur à ce stade est fatale pour l'appelant)
+func newConfigWatcher(path string, watch bool) (*configWatcher, error) {
+	config, err := loadScraperConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return &configWatcher{path: path, watch: watch, active: config}, nil
+}
+
+// Current retourne la configuration à utiliser pour l'exécution qui commence
+func (w *configWatcher) Current() ScraperConfig {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.watch {
+		return w.active
+	}
+
+	// Valider avant de remplacer : la configuration active n'est jamais remplacée par une configuration invalide
+	config, err := loadScraperConfig(w.path)
+	if err != nil {
+		logConfigReloadError(err)
+		return w.active
+	}
+	if !reflect.DeepEqual(config, w.active) {
+		logConfigReloaded(w.path, len(config.Categories))
+		w.active = config
+	}
+	return w.active
+}
+
+// decodeScraperConfig décode la configuration selon l'extension du fichier (.yaml/.yml, .toml, JSON sinon)
+func decodeScraperConfig(path string, content []byte) (ScraperConfig, error) {
+	var config ScraperConfig
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &config)
+	case ".toml":
+		err = toml.Unmarshal(content, &config)
+	default:
+		err = json.Unmarshal(content, &config)
+	}
+	return config, err
+}
+
+// expandConfigEnv remplace les références ${VAR} (ou $VAR) des champs texte par les variables d'environnement
+// Une variable absente est remplacée par une chaîne vide ; les noms concernés sont retournés sans doublon
+func expandConfigEnv(c *ScraperConfig) []string {
+	var unset []string
+	seen := make(map[string]bool)
+	lookup := func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok && !seen[name] {
+			seen[name] = true
+			unset = append(unset, name)
+		}
+		return value
+	}
+
+	for i, category := range c.Categories {
+		c.Categories[i] = os.Expand(category, lookup)
+	}
+	return unset
+}
+
+// validateScraperConfig vérifie que chaque catégorie est une URL http(s) absolue et que les champs obligatoires existent
+func validateScraperConfig(c ScraperConfig) error {
+	if len(c.Categories) == 0 {
+		return errors.New("aucune catégorie configurée")
+	}
+	for _, category := range c.Categories {
+		u, err := url.Parse(category)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("catégorie invalide %q: URL http(s) absolue attendue", category)
+		}
+	}
+	return validateRequiredFields(c.RequiredFields)
+}
+
+// categorySlug retourne le dernier segment du chemin d'une page de catégorie (".../recipes/79/desserts/?page=2" → "desserts")
+func categorySlug(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	slug := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if slug == "/" || slug == "." {
+		return ""
+	}
+	return strings.ToLower(slug)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du chargement du fichier de configuration
+func TestLoadScraperConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	// Un fichier absent donne les catégories par défaut
+	config, err := loadScraperConfig(filepath.Join(dir, defaultConfigFilename))
+	require.NoError(t, err)
+	assert.Equal(t, scraperspec.DefaultCategories, config.Categories)
+
+	path := filepath.Join(dir, "custom.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"categories": ["https://example.com/recipes/1/"]}`), 0644))
+	config, err = loadScraperConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/recipes/1/"}, config.Categories)
+
+	// Les URLs invalides et les listes vides sont refusées
+	for _, content := range []string{`{"categories": ["/recipes/1/"]}`, `{"categories": []}`, `{`} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err = loadScraperConfig(path)
+		assert.Error(t, err, content)
+	}
+}
+
+// Test des formats de configuration : JSON, YAML et TOML équivalents donnent la même configuration
+func TestLoadScraperConfigFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"scraper_config.json": `{"categories": ["https://example.com/recipes/1/soup/", "https://example.com/recipes/2/desserts/"]}`,
+		"scraper_config.yaml": "categories:\n  - https://example.com/recipes/1/soup/\n  - https://example.com/recipes/2/desserts/\n",
+		"scraper_config.yml":  "categories: [https://example.com/recipes/1/soup/, https://example.com/recipes/2/desserts/]\n",
+		"scraper_config.TOML": "categories = [\n  \"https://example.com/recipes/1/soup/\",\n  \"https://example.com/recipes/2/desserts/\",\n]\n",
+	}
+	expected := ScraperConfig{Categories: []string{"https://example.com/recipes/1/soup/", "https://example.com/recipes/2/desserts/"}}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		config, err := loadScraperConfig(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, config, name)
+	}
+
+	// Le décodeur suit l'extension : du TOML dans un fichier .yaml est refusé
+	path := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(files["scraper_config.TOML"]), 0644))
+	_, err := loadScraperConfig(path)
+	assert.Error(t, err)
+}
+
+// Test de l'expansion des variables d'environnement dans la configuration
+func TestLoadScraperConfigExpandsEnv(t *testing.T) {
+	t.Setenv("SCRAPER_TEST_HOST", "recipes.example.com")
+	t.Setenv("SCRAPER_TEST_EMPTY", "")
+	os.Unsetenv("SCRAPER_TEST_UNSET")
+
+	path := filepath.Join(t.TempDir(), "scraper_config.json")
+	content := `{"categories": ["https://${SCRAPER_TEST_HOST}/recipes/79/desserts/", "https://example.com/recipes/${SCRAPER_TEST_UNSET}81/"]}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := loadScraperConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://recipes.example.com/recipes/79/desserts/", "https://example.com/recipes/81/"}, config.Categories)
+
+	// Seules les variables absentes sont signalées (une variable vide est définie), sans doublon
+	config = ScraperConfig{Categories: []string{"${SCRAPER_TEST_UNSET}a", "$SCRAPER_TEST_UNSET/${SCRAPER_TEST_EMPTY}", "${SCRAPER_TEST_HOST}"}}
+	assert.Equal(t, []string{"SCRAPER_TEST_UNSET"}, expandConfigEnv(&config))
+	assert.Equal(t, []string{"a", "/", "recipes.example.com"}, config.Categories)
+}
+
+// Test du rechargement entre deux exécutions (-watch-config)
+func TestConfigWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scraper_config.json")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write(`{"categories": ["https://example.com/recipes/1/soup/"]}`)
+
+	watcher, err := newConfigWatcher(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/recipes/1/soup/"}, watcher.Current().Categories)
+
+	// Fichier modifié entre deux exécutions : les nouvelles catégories sont prises en compte
+	write(`{"categories": ["https://example.com/recipes/2/desserts/", "https://example.com/recipes/3/drinks/"]}`)
+	assert.Equal(t, []string{"https://example.com/recipes/2/desserts/", "https://example.com/recipes/3/drinks/"}, watcher.Current().Categories)
+
+	// Fichier invalide : la configuration précédente reste active
+	write(`{"categories": [`)
+	assert.Equal(t, []string{"https://example.com/recipes/2/desserts/", "https://example.com/recipes/3/drinks/"}, watcher.Current().Categories)
+	write(`{"categories": ["/recipes/4/"]}`)
+	assert.Len(t, watcher.Current().Categories, 2)
+
+	// Sans -watch-config, la configuration chargée au démarrage est conservée
+	write(`{"categories": ["https://example.com/recipes/1/soup/"]}`)
+	static, err := newConfigWatcher(path, false)
+	require.NoError(t, err)
+	write(`{"categories": ["https://example.com/recipes/2/desserts/"]}`)
+	assert.Equal(t, []string{"https://example.com/recipes/1/soup/"}, static.Current().Categories)
+
+	o, err := parseOptions([]string{"-watch-config"}, os.Stderr)
+	require.NoError(t, err)
+	assert.True(t, o.WatchConfig)
+}
+
+// Test de l'option -config
+func TestConfigOption(t *testing.T) {
+	o, err := parseOptions(nil, os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfigFilename, o.ConfigPath)
+
+	o, err = parseOptions([]string{"-config", "/etc/scraper.json"}, os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/scraper.json", o.ConfigPath)
+}
+
+// Test de l'extraction de la catégorie depuis l'URL de la page de catégorie
+func TestCategorySlug(t *testing.T) {
+	cases := map[string]string{
+		"https://www.allrecipes.com/recipes/79/desserts/":        "desserts",
+		"https://www.allrecipes.com/recipes/79/desserts/?page=2": "desserts",
+		"https://www.allrecipes.com/recipes/81/Side-Dish":        "side-dish",
+		"https://www.allrecipes.com/":                            "",
+	}
+	for raw, expected := range cases {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		assert.Equal(t, expected, categorySlug(u), raw)
+	}
+	assert.Equal(t, "", categorySlug(nil))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// debugHTMLDir reçoit, avec -debug-html, le HTML brut des recettes en échec (dans -output-dir)
+const debugHTMLDir = "debug"
+
+// maxDebugHTMLFiles borne le nombre de pages sauvegardées par exécution
+const maxDebugHTMLFiles = 100
+
+// debugHTML sauvegarde les pages en échec (nil sans -debug-html, configuré par configureDebugHTML)
+var debugHTML *debugHTMLWriter
+
+// configureDebugHTML prépare la sauvegarde des pages en échec pour une nouvelle exécution
+func configureDebugHTML() {
+	debugHTML = nil
+	if opts.DebugHTML {
+		debugHTML = newDebugHTMLWriter(filepath.Join(opts.OutputDir, debugHTMLDir), maxDebugHTMLFiles)
+	}
+}
+
+// debugHTMLWriter écrit le HTML d'une page dans debug/<slug>.html, dans la limite de max fichiers
+// Thread-safe grâce au Mutex : les workers peuvent sauvegarder en parallèle
+type debugHTMLWriter struct {
+	mu      sync.Mutex
+	dir     string
+	max     int
+	saved   map[string]bool // Slugs déjà écrits : une page re-tentée remplace son fichier sans compter deux fois
+	limited bool            // Limite atteinte et signalée
+}
+
+// newDebugHTMLWriter crée un writer vers dir (créé à la première page)
+func newDebugHTMLWriter(dir string, max int) *debugHTMLWriter {
+	return &debugHTMLWriter{dir: dir, max: max, saved: make(map[string]bool)}
+}
+
+// Save écrit body pour pageURL et retourne le chemin du fichier
+// Retourne "" sans erreur si la sauvegarde est désactivée (nil) ou la limite atteinte
+func (d *debugHTMLWriter) Save(pageURL string, body []byte) (string, error) {
+	if d == nil || len(body) == 0 {
+		return "", nil
+	}
+
+	slug := recipeSlug(pageURL)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.saved[slug] && len(d.saved) >= d.max {
+		if !d.limited {
+			d.limited = true
+			logDebugHTMLLimit(d.max, d.dir)
+		}
+		return "", nil
+	}
+
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(d.dir, slug+".html")
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return "", err
+	}
+	d.saved[slug] = true
+	return path, nil
+}
+
+// recipeSlug construit un nom de fichier à partir du chemin de l'URL (ex: recipe-10813-best-cookies)
+func recipeSlug(pageURL string) string {
+	path := pageURL
+	if parsed, err := url.Parse(pageURL); err == nil {
+		path = parsed.Path
+	}
+
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(path) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+		if slug.Len() >= 100 {
+			break
+		}
+	}
+	if slug.Len() == 0 {
+		return "page"
+	}
+	return slug.String()
+}
+
+// saveDebugHTML sauvegarde la page d'une recette en échec et logge le fichier écrit
+func saveDebugHTML(pageURL string, body []byte) {
+	path, err := debugHTML.Save(pageURL, body)
+	if err != nil {
+		logDebugHTMLError(pageURL, err)
+		return
+	}
+	if path != "" {
+		logDebugHTMLSaved(pageURL, path)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test d'une page vide et d'une page en erreur : leur HTML est sauvegardé dans debug/
+func TestDebugHTMLSavesFailedPages(t *testing.T) {
+	previous, previousWriter := opts, debugHTML
+	defer func() { opts, debugHTML = previous, previousWriter }()
+	opts.EmptyRetries = 0
+	opts.DebugHTML = true
+	opts.OutputDir = t.TempDir()
+	configureDebugHTML()
+
+	fetcher := fetcherFunc(func(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+		if strings.HasSuffix(pageURL, "/recipe/2/erreur/") {
+			body := []byte("<html><body>Maintenance</body></html>")
+			return nil, nil, &fetchError{StatusCode: http.StatusServiceUnavailable, Body: body, Err: errors.New("Service Unavailable")}
+		}
+		return htmlPage(emptyRecipeHTML)
+	})
+
+	for _, path := range []string{"/recipe/1/soupe-vide/", "/recipe/2/erreur/"} {
+		recipe := Recipe{Page: "https://www.allrecipes.com" + path}
+		scrapeRecipeDetails(context.Background(), fetcher, &recipe, extractorCSS, make(chan Recipe, 1), NewScrapingStats(1), nil)
+	}
+
+	content, err := os.ReadFile(filepath.Join(opts.OutputDir, debugHTMLDir, "recipe-1-soupe-vide.html"))
+	require.NoError(t, err)
+	assert.Equal(t, emptyRecipeHTML, string(content))
+	content, err = os.ReadFile(filepath.Join(opts.OutputDir, debugHTMLDir, "recipe-2-erreur.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Maintenance")
+
+	o, err := parseOptions([]string{"-debug-html"}, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, o.DebugHTML)
+}
+
+// Test de la limite de pages sauvegardées : une page déjà sauvegardée est remplacée sans compter
+func TestDebugHTMLLimit(t *testing.T) {
+	dir := t.TempDir()
+	writer := newDebugHTMLWriter(dir, 1)
+
+	path, err := writer.Save("https://example.com/recipe/1/", []byte("v1"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "recipe-1.html"), path)
+	path, err = writer.Save("https://example.com/recipe/1/", []byte("v2"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, path)
+
+	path, err = writer.Save("https://example.com/recipe/2/", []byte("v1"))
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Sans -debug-html, rien n'est écrit
+	var disabled *debugHTMLWriter
+	path, err = disabled.Save("https://example.com/recipe/3/", []byte("v1"))
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestRecipeSlug(t *testing.T) {
+	assert.Equal(t, "recipe-10813-best-chocolate-chip-cookies", recipeSlug("https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies/"))
+	assert.Equal(t, "recette-cr-pes", recipeSlug("https://example.com/recette/crêpes?x=1"))
+	assert.Equal(t, "page", recipeSlug("https://example.com/"))
+}
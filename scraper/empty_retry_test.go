@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emptyRecipeHTML est une page de recette rendue sans ingrédient
+const emptyRecipeHTML = `<html><body><h1 class="article-heading">Soupe de légumes</h1></body></html>`
+
+// Test d'une page vide au premier téléchargement puis complète : la recette est envoyée après une nouvelle tentative
+func TestEmptyRecipeRetried(t *testing.T) {
+	var hits atomic.Int32
+	fetcher := fetcherFunc(func(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+		if hits.Add(1) == 1 {
+			return htmlPage(emptyRecipeHTML)
+		}
+		return htmlPage(recipePageHTML)
+	})
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	recipe := Recipe{Name: "Soupe", Page: "https://www.allrecipes.com/recipe/1/soupe/"}
+
+	extraction := scrapeRecipeDetails(context.Background(), fetcher, &recipe, extractorCSS, completedRecipes, stats, nil)
+
+	assert.Equal(t, int32(2), hits.Load())
+	assert.Equal(t, 1, extraction.Retries)
+	assert.Equal(t, 2, extraction.Attempts)
+	assert.NoError(t, extraction.Err)
+	require.Len(t, completedRecipes, 1)
+	assert.Len(t, (<-completedRecipes).Ingredients, 2)
+	assert.Equal(t, int64(1), stats.RecipesCompleted)
+}
+
+// Test d'une page toujours vide : abandon après -empty-retries tentatives et recette non envoyée
+func TestEmptyRecipeGivesUp(t *testing.T) {
+	previous := opts
+	defer func() { opts = previous }()
+	opts.EmptyRetries = 3
+
+	var hits atomic.Int32
+	fetcher := fetcherFunc(func(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+		hits.Add(1)
+		return htmlPage(emptyRecipeHTML)
+	})
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	recipe := Recipe{Name: "Soupe", Page: "https://www.allrecipes.com/recipe/1/soupe/"}
+
+	extraction := scrapeRecipeDetails(context.Background(), fetcher, &recipe, extractorCSS, completedRecipes, stats, nil)
+
+	assert.Equal(t, int32(4), hits.Load())
+	assert.Equal(t, 3, extraction.Retries)
+	assert.ErrorIs(t, extraction.Err, errEmptyRecipe)
+	assert.Empty(t, completedRecipes)
+	assert.Zero(t, stats.RecipesCompleted)
+
+	o, err := parseOptions([]string{"-empty-retries", "0"}, io.Discard)
+	require.NoError(t, err)
+	assert.Zero(t, o.EmptyRetries)
+	_, err = parseOptions([]string{"-empty-retries", "-1"}, io.Discard)
+	assert.Error(t, err)
+}
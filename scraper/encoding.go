@@ -0,0 +1,130 @@
+package main
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptedEncodings est l'Accept-Encoding envoyé par configureRealisticHeaders (celui d'un Chrome récent)
+// Chaque encodage annoncé doit être décodé par decodingTransport
+const acceptedEncodings = "gzip, deflate, br, zstd"
+
+// decodingTransport décompresse les réponses selon leur Content-Encoding avant l'analyse par Colly
+// Avec un Accept-Encoding explicite, net/http ne décompresse rien et Colly ne gère que gzip :
+// une page servie en br ou zstd arriverait compressée dans les sélecteurs et la recette serait vide
+type decodingTransport struct {
+	base http.RoundTripper
+}
+
+// newDecodingTransport crée un transport décompressant les réponses de base
+func newDecodingTransport(base http.RoundTripper) *decodingTransport {
+	return &decodingTransport{base: base}
+}
+
+// RoundTrip exécute la requête et remplace le corps compressé par le corps décodé
+// Les en-têtes Content-Encoding et Content-Length sont retirés, la réponse marquée Uncompressed
+// (Colly ne tente pas un second décodage gzip) ; un encodage inconnu laisse la réponse telle quelle
+func (t *decodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encodings := contentEncodings(resp.Header.Get("Content-Encoding"))
+	if len(encodings) == 0 || req.Method == http.MethodHead || resp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+	for _, encoding := range encodings {
+		if !supportedEncoding(encoding) {
+			return resp, nil
+		}
+	}
+
+	// Les encodages sont listés dans l'ordre d'application : décodage dans l'ordre inverse
+	decoded := &decodedBody{Reader: resp.Body, closers: []io.Closer{resp.Body}}
+	for i := len(encodings) - 1; i >= 0; i-- {
+		reader, err := decodeBody(encodings[i], decoded.Reader)
+		if err != nil {
+			decoded.Close()
+			return nil, fmt.Errorf("décodage %s de %s: %w", encodings[i], req.URL, err)
+		}
+		decoded.Reader = reader
+		if closer, ok := reader.(io.Closer); ok {
+			decoded.closers = append(decoded.closers, closer)
+		}
+	}
+
+	resp.Body = decoded
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// contentEncodings découpe un Content-Encoding (gzip, br...) en encodages en minuscules, identity ignoré
+func contentEncodings(header string) []string {
+	var encodings []string
+	for _, encoding := range strings.Split(header, ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+		encodings = append(encodings, encoding)
+	}
+	return encodings
+}
+
+// supportedEncoding indique si decodeBody sait décoder l'encodage
+func supportedEncoding(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "deflate", "br", "zstd":
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeBody retourne un lecteur décompressant body selon l'encodage
+// deflate est le format zlib de la RFC 9110 (et non un flux deflate brut)
+func decodeBody(encoding string, body io.Reader) (io.Reader, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		return zlib.NewReader(body)
+	case "br":
+		return brotli.NewReader(body), nil
+	case "zstd":
+		decoder, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("encodage non supporté")
+	}
+}
+
+// decodedBody lit le corps décodé ; la fermeture libère les décodeurs puis le corps d'origine
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer // Corps d'origine en premier, puis chaque décodeur
+}
+
+func (b *decodedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if closeErr := b.closers[i].Close(); i == 0 {
+			err = closeErr
+		}
+	}
+	return err
+}
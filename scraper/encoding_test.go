@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeBody compresse content selon l'encodage HTTP
+func encodeBody(t *testing.T, encoding, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		encoder, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		w = encoder
+	default:
+		t.Fatalf("encodage inconnu %q", encoding)
+	}
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// Une page de recette servie en brotli est décodée avant l'extraction
+func TestCollyFetcherDecodesBrotli(t *testing.T) {
+	useTestCookieJar(t)
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Encoding", "br")
+		w.Write(encodeBody(t, "br", recipePageHTML))
+	}))
+	defer server.Close()
+
+	body, header, err := newCollyFetcher(NewScrapingStats(1)).Fetch(context.Background(), server.URL+"/recipe")
+	require.NoError(t, err)
+	assert.Equal(t, acceptedEncodings, acceptEncoding)
+	assert.Empty(t, header.Get("Content-Encoding"))
+
+	recipe, err := parseRecipeHTML(body, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Soupe de légumes", recipe.Name)
+	assert.Len(t, recipe.Ingredients, 2)
+	assert.Len(t, recipe.Instructions, 2)
+}
+
+// Chaque encodage annoncé dans Accept-Encoding est décodé, y compris plusieurs encodages successifs
+func TestDecodingTransport(t *testing.T) {
+	const page = "<html><h1>Soupe</h1></html>"
+	cases := []struct {
+		name   string
+		header string
+		body   func(t *testing.T) []byte
+	}{
+		{"gzip", "gzip", func(t *testing.T) []byte { return encodeBody(t, "gzip", page) }},
+		{"deflate", "deflate", func(t *testing.T) []byte { return encodeBody(t, "deflate", page) }},
+		{"brotli", "br", func(t *testing.T) []byte { return encodeBody(t, "br", page) }},
+		{"zstd", "zstd", func(t *testing.T) []byte { return encodeBody(t, "zstd", page) }},
+		{"gzip puis brotli", "gzip, BR", func(t *testing.T) []byte {
+			return encodeBody(t, "br", string(encodeBody(t, "gzip", page)))
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := tc.body(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", tc.header)
+				w.Write(encoded)
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept-Encoding", acceptedEncodings)
+			resp, err := newDecodingTransport(http.DefaultTransport).RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, page, string(body))
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		})
+	}
+}
+
+// Un encodage inconnu laisse la réponse intacte ; un corps corrompu est une erreur de requête
+func TestDecodingTransportUnsupportedAndCorrupt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", r.URL.Query().Get("encoding"))
+		w.Write([]byte("pas compressé"))
+	}))
+	defer server.Close()
+
+	transport := newDecodingTransport(http.DefaultTransport)
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?encoding=compress", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", acceptedEncodings)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "pas compressé", string(body))
+	assert.Equal(t, "compress", resp.Header.Get("Content-Encoding"))
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"?encoding=gzip", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", acceptedEncodings)
+	_, err = transport.RoundTrip(req)
+	assert.ErrorContains(t, err, "décodage gzip")
+}
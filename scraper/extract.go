@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Sélecteurs des pages de recettes AllRecipes (2024)
+const (
+	ingredientListSelector = "ul.mm-recipes-structured-ingredients__list"
+	ingredientItemSelector = "li.mm-recipes-structured-ingredients__list-item"
+	instructionsSelector   = "div.mm-recipes-steps__content"
+	instructionSelector    = "ol.mntl-sc-block li"
+	jsonLDSelector         = "script[type='application/ld+json']"
+)
+
+// parseRecipeHTML extrait une recette d'une page HTML avec la stratégie -extractor, sans requête colly
+// base est l'URL de la page : elle renseigne Page et résout une image relative (nil si inconnue)
+func parseRecipeHTML(html []byte, base *url.URL) (Recipe, error) {
+	return parseRecipePage(html, base, opts.Extractor)
+}
+
+// parseRecipePage est parseRecipeHTML avec une stratégie d'extraction explicite (handlers de scrapeRecipeDetails)
+func parseRecipePage(html []byte, base *url.URL, extractor string) (Recipe, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	content := extractRecipeContent(doc.Selection, extractor != extractorCSS)
+	recipe := Recipe{
+		Name:         content.Name,
+		Image:        content.Image,
+		Ingredients:  content.Ingredients,
+		Instructions: content.Instructions,
+		Tags:         content.Taxonomy.Tags,
+		Cuisine:      content.Taxonomy.Cuisine,
+		Course:       content.Taxonomy.Course,
+	}
+	content.Details.apply(&recipe)
+	applyExtractor(extractor, &recipe, content.JSONLD)
+
+	if base != nil {
+		recipe.Page = base.String()
+		if image, err := base.Parse(recipe.Image); err == nil && recipe.Image != "" {
+			recipe.Image = image.String()
+		}
+	}
+	return recipe, nil
+}
+
+// recipeContent est le contenu extrait d'une page de recette, sans dépendre d'une requête colly
+type recipeContent struct {
+	Name         string         // Premier <h1> non vide
+	Image        string         // og:image
+	Ingredients  []Ingredient   // Sélecteurs CSS
+	Instructions []Instruction  // Sélecteurs CSS
+	JSONLD       *jsonLDRecipe  // Recette schema.org (nil si absente ou non demandée)
+	Taxonomy     recipeTaxonomy // Fil d'Ariane et catégories JSON-LD, quelle que soit la stratégie
+	Details      jsonLDDetails  // Temps, nutrition et note JSON-LD, quelle que soit la stratégie
+}
+
+// extractRecipeContent lit le contenu d'une page de recette déjà analysée
+// withJSONLD : lire aussi les balises JSON-LD (-extractor jsonld ou auto) ; leurs catégories alimentent Taxonomy
+// et leurs temps, nutrition et note Details dans tous les cas
+func extractRecipeContent(doc *goquery.Selection, withJSONLD bool) recipeContent {
+	var content recipeContent
+
+	doc.Find("h1").EachWithBreak(func(_ int, h1 *goquery.Selection) bool {
+		content.Name = strings.TrimSpace(h1.Text())
+		return content.Name == ""
+	})
+	doc.Find("meta[property='og:image']").EachWithBreak(func(_ int, meta *goquery.Selection) bool {
+		content.Image, _ = meta.Attr("content")
+		return content.Image == ""
+	})
+
+	// Plusieurs listes (ex: pâte + garniture) sont concaténées dans l'ordre de la page
+	doc.Find(ingredientListSelector).Each(func(_ int, list *goquery.Selection) {
+		content.Ingredients = append(content.Ingredients, extractIngredients(list)...)
+	})
+	doc.Find(instructionsSelector).Each(func(_ int, block *goquery.Selection) {
+		content.Instructions = append(content.Instructions, extractInstructions(block, len(content.Instructions))...)
+	})
+
+	ld := extractJSONLD(doc)
+	content.Taxonomy = buildTaxonomy(extractBreadcrumb(doc), ld)
+	if ld != nil {
+		content.Details = ld.Details
+	}
+	if withJSONLD {
+		content.JSONLD = ld
+	}
+	return content
+}
+
+// extractIngredients lit les ingrédients d'une liste structurée
+func extractIngredients(list *goquery.Selection) []Ingredient {
+	var ingredients []Ingredient
+	list.Find(ingredientItemSelector).Each(func(_ int, item *goquery.Selection) {
+		// Extraire la quantité et l'unité séparément
+		quantity := strings.TrimSpace(item.Find("span[data-ingredient-quantity=true]").Text())
+		unit := strings.TrimSpace(item.Find("span[data-ingredient-unit=true]").Text())
+		name := strings.TrimSpace(item.Find("span[data-ingredient-name=true]").Text())
+
+		// Si on a des données structurées, les utiliser
+		if quantity != "" || unit != "" || name != "" {
+			ingredients = append(ingredients, Ingredient{
+				Quantity: strings.TrimSpace(item.Text()), // Texte complet pour l'instant
+				Unit:     "",                             // Pas de séparation pour l'instant
+				Name:     name,
+			})
+		}
+	})
+	return ingredients
+}
+
+// extractInstructions lit les étapes d'un bloc d'instructions, numérotées à la suite des offset étapes déjà lues
+func extractInstructions(block *goquery.Selection, offset int) []Instruction {
+	var instructions []Instruction
+	block.Find(instructionSelector).Each(func(_ int, step *goquery.Selection) {
+		// Texte de la balise <p>, ou texte complet de l'étape à défaut
+		description := strings.TrimSpace(step.Find("p.mntl-sc-block-html").Text())
+		if description == "" {
+			description = strings.TrimSpace(step.Text())
+		}
+		if description != "" {
+			instructions = append(instructions, Instruction{
+				Number:      strconv.Itoa(offset + len(instructions) + 1),
+				Description: description,
+			})
+		}
+	})
+	return instructions
+}
+
+// extractJSONLD retourne la première recette schema.org des balises JSON-LD du document
+func extractJSONLD(doc *goquery.Selection) *jsonLDRecipe {
+	var recipe *jsonLDRecipe
+	doc.Find(jsonLDSelector).EachWithBreak(func(_ int, script *goquery.Selection) bool {
+		recipe = parseJSONLDRecipe(script.Text())
+		return recipe == nil
+	})
+	return recipe
+}
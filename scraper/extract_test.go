@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFixture lit une page de recette capturée dans testdata
+func loadFixture(tb testing.TB, name string) []byte {
+	tb.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(tb, err)
+	return content
+}
+
+// parseFixture analyse une page de recette comme le fait colly avant les handlers OnHTML
+func parseFixture(tb testing.TB, page []byte) *goquery.Selection {
+	tb.Helper()
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	require.NoError(tb, err)
+	return doc.Selection
+}
+
+// Test de l'extraction sans collecteur : deux listes d'ingrédients, étapes numérotées, JSON-LD
+func TestExtractRecipeContent(t *testing.T) {
+	doc := parseFixture(t, loadFixture(t, "recipe_cookies.html"))
+
+	content := extractRecipeContent(doc, true)
+	assert.Equal(t, "Best Chocolate Chip Cookies", content.Name)
+	assert.Equal(t, "https://www.allrecipes.com/thmb/cookies-og.jpg", content.Image)
+	require.Len(t, content.Ingredients, 10)
+	assert.Equal(t, "all-purpose flour", content.Ingredients[0].Name)
+	assert.Equal(t, "chopped walnuts", content.Ingredients[9].Name)
+	require.Len(t, content.Instructions, 6)
+	assert.Equal(t, "6", content.Instructions[5].Number)
+	require.NotNil(t, content.JSONLD)
+	assert.Len(t, content.JSONLD.Ingredients, 10)
+	assert.Equal(t, 60, content.Details.TotalTimeMinutes)
+
+	withoutJSONLD := extractRecipeContent(doc, false)
+	assert.Nil(t, withoutJSONLD.JSONLD)
+	assert.Equal(t, 60, withoutJSONLD.Details.TotalTimeMinutes, "totalTime lu quelle que soit la stratégie")
+}
+
+// Test de parseRecipeHTML sur plusieurs pages capturées, selon la stratégie d'extraction
+func TestParseRecipeHTML(t *testing.T) {
+	base, err := url.Parse("https://example.com/recette/42/")
+	require.NoError(t, err)
+
+	tests := []struct {
+		fixture      string
+		extractor    string
+		name         string
+		image        string
+		ingredients  int
+		firstName    string
+		instructions []string
+	}{
+		{
+			fixture: "recipe_cookies.html", extractor: extractorCSS,
+			name: "Best Chocolate Chip Cookies", image: "https://www.allrecipes.com/thmb/cookies-og.jpg",
+			ingredients: 10, firstName: "all-purpose flour",
+			instructions: []string{"Preheat the oven to 350 degrees F (175 degrees C).", "Beat butter, white sugar, and brown sugar with an electric mixer in a large bowl until smooth."},
+		},
+		{
+			// Étapes sans <p>, premier <h1> vide, ingrédient sans données structurées ignoré, image relative
+			fixture: "recipe_plain_steps.html", extractor: extractorCSS,
+			name: "Simple Pancakes", image: "https://example.com/thmb/pancakes.jpg",
+			ingredients: 3, firstName: "all-purpose flour",
+			instructions: []string{"Whisk the flour, milk and egg together.", "Cook on a hot buttered pan until golden."},
+		},
+		{
+			// Site sans balisage AllRecipes : seules les données structurées sont exploitables
+			fixture: "recipe_jsonld_only.html", extractor: extractorAuto,
+			name: "Quiche lorraine", image: "https://example.com/images/quiche-1x1.jpg",
+			ingredients: 5, firstName: "1 pâte brisée",
+			instructions: []string{"Préchauffer le four à 180 °C.", "Faire revenir les lardons.", "Battre les œufs avec la crème et le lait.", "Garnir la pâte et enfourner 35 minutes."},
+		},
+		{
+			fixture: "recipe_jsonld_only.html", extractor: extractorCSS,
+			name: "Quiche lorraine",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture+"/"+tt.extractor, func(t *testing.T) {
+			previous := opts
+			defer func() { opts = previous }()
+			opts.Extractor = tt.extractor
+
+			recipe, err := parseRecipeHTML(loadFixture(t, tt.fixture), base)
+			require.NoError(t, err)
+			assert.Equal(t, base.String(), recipe.Page)
+			assert.Equal(t, tt.name, recipe.Name)
+			assert.Equal(t, tt.image, recipe.Image)
+			require.Len(t, recipe.Ingredients, tt.ingredients)
+			if tt.ingredients > 0 {
+				assert.Equal(t, tt.firstName, recipe.Ingredients[0].Name)
+			}
+			require.GreaterOrEqual(t, len(recipe.Instructions), len(tt.instructions))
+			for i, description := range tt.instructions {
+				assert.Equal(t, Instruction{Number: strconv.Itoa(i + 1), Description: description}, recipe.Instructions[i])
+			}
+		})
+	}
+}
+
+// BenchmarkScrapeRecipeDetails mesure l'extraction CSS des handlers de scrapeRecipeDetails (analyse HTML comprise)
+func BenchmarkScrapeRecipeDetails(b *testing.B) {
+	page := loadFixture(b, "recipe_cookies.html")
+	base, _ := url.Parse("https://www.allrecipes.com/recipe/10813/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		recipe, err := parseRecipePage(page, base, extractorCSS)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(recipe.Ingredients) == 0 {
+			b.Fatal("aucun ingrédient extrait")
+		}
+	}
+}
+
+// BenchmarkExtractFromJSONLD mesure la lecture de la recette schema.org d'une page déjà analysée
+func BenchmarkExtractFromJSONLD(b *testing.B) {
+	doc := parseFixture(b, loadFixture(b, "recipe_cookies.html"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if recipe := extractJSONLD(doc); recipe == nil {
+			b.Fatal("aucune recette JSON-LD")
+		}
+	}
+}
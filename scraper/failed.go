@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+)
+
+// failedFilename est la liste des recettes en échec écrite en fin d'exécution, à côté de data.json
+const failedFilename = "failed.json"
+
+// FailedRecipe décrit une recette abandonnée après toutes ses tentatives
+type FailedRecipe struct {
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	Category   string `json:"category,omitempty"`
+	Error      string `json:"error"`                 // Dernière erreur rencontrée
+	Failure    string `json:"failure"`               // Catégorie de l'échec : http, timeout, empty ou parse
+	StatusCode int    `json:"status_code,omitempty"` // Dernier statut HTTP reçu (0 = pas de réponse)
+	Attempts   int    `json:"attempts"`              // Nombre de requêtes envoyées pour cette recette
+}
+
+// Catégories d'échec d'une recette, comptées séparément dans ScrapingStats
+// http et timeout indiquent plutôt un blocage, empty et parse un changement du balisage
+const (
+	failureHTTP    = "http"    // Statut HTTP en erreur ou connexion impossible
+	failureTimeout = "timeout" // Délai dépassé (-request-timeout) ou requête annulée par le superviseur
+	failureEmpty   = "empty"   // Page reçue sans ingrédient, même après -empty-retries
+	failureParse   = "parse"   // Réponse illisible (pas du HTML, document invalide)
+)
+
+// errParseRecipe signale une réponse de recette qui n'a pas pu être analysée
+var errParseRecipe = errors.New("page de recette illisible")
+
+// classifyFailure détermine la catégorie d'échec d'une recette à partir de l'erreur de la visite
+func classifyFailure(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errEmptyRecipe):
+		return failureEmpty
+	case errors.Is(err, errParseRecipe):
+		return failureParse
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return failureTimeout
+	default:
+		return failureHTTP
+	}
+}
+
+// failedRecipes collecte les recettes en échec des différents workers
+// Thread-safe grâce au Mutex ; un collecteur nil ignore les ajouts (selftest, tests)
+type failedRecipes struct {
+	mu      sync.Mutex
+	entries []FailedRecipe
+}
+
+// Add enregistre une recette en échec
+func (f *failedRecipes) Add(entry FailedRecipe) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entry)
+}
+
+// Entries retourne une copie des recettes en échec
+func (f *failedRecipes) Entries() []FailedRecipe {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FailedRecipe(nil), f.entries...)
+}
+
+// Save écrit la liste dans dir/filename, vide comprise, pour ne pas laisser celle d'une exécution précédente
+func (f *failedRecipes) Save(dir, filename string) error {
+	entries := f.Entries()
+	if entries == nil {
+		entries = []FailedRecipe{}
+	}
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutputFile(dir, filename, content)
+}
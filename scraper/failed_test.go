@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de la liste des recettes en échec : une URL toujours en erreur se retrouve dans failed.json
+func TestFailedRecipesDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/recipe/broken" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 2)
+	failed := &failedRecipes{}
+
+	processRecipeReusable(RecipeData{URL: server.URL + "/recipe/ok", Title: "Soupe"}, newCollyFetcher(stats), stats, completedRecipes, &WorkerStats{WorkerID: 1}, nil, failed)
+	processRecipeReusable(RecipeData{URL: server.URL + "/recipe/broken", Title: "Cassée", Category: "soup"}, newCollyFetcher(stats), stats, completedRecipes, &WorkerStats{WorkerID: 1}, nil, failed)
+
+	dir := t.TempDir()
+	require.NoError(t, failed.Save(dir, failedFilename))
+
+	content, err := os.ReadFile(filepath.Join(dir, failedFilename))
+	require.NoError(t, err)
+	var entries []FailedRecipe
+	require.NoError(t, json.Unmarshal(content, &entries))
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, server.URL+"/recipe/broken", entries[0].URL)
+	assert.Equal(t, "Cassée", entries[0].Title)
+	assert.Equal(t, "soup", entries[0].Category)
+	assert.Equal(t, http.StatusInternalServerError, entries[0].StatusCode)
+	assert.Equal(t, 1, entries[0].Attempts)
+	assert.NotEmpty(t, entries[0].Error)
+	assert.Equal(t, int64(1), stats.RecipesFailed)
+}
+
+// Test de l'écriture d'une liste vide : failed.json ne garde pas les échecs d'une exécution précédente
+func TestFailedRecipesSaveEmpty(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, (&failedRecipes{}).Save(dir, failedFilename))
+
+	content, err := os.ReadFile(filepath.Join(dir, failedFilename))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(content))
+
+	// Un collecteur nil ignore les ajouts
+	var none *failedRecipes
+	none.Add(FailedRecipe{URL: "https://example.com"})
+	assert.Empty(t, none.Entries())
+}
+
+// Test de la répartition des échecs : erreur HTTP, timeout, page vide et réponse illisible
+func TestFailureCategories(t *testing.T) {
+	previous := opts
+	defer func() { opts = previous }()
+	opts.EmptyRetries = 0
+	opts.RequestTimeout = 200 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/recipe/http":
+			http.NotFound(w, r)
+		case "/recipe/timeout":
+			time.Sleep(time.Second)
+		case "/recipe/empty":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(emptyRecipeHTML))
+		case "/recipe/parse":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": "Soupe"}`))
+		}
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	failed := &failedRecipes{}
+	categories := []string{failureHTTP, failureTimeout, failureEmpty, failureParse}
+	for _, category := range categories {
+		processRecipeReusable(RecipeData{URL: server.URL + "/recipe/" + category, Title: category}, newCollyFetcher(stats), stats, make(chan Recipe, 1), &WorkerStats{WorkerID: 1}, nil, failed)
+	}
+
+	detailed := stats.GetDetailedStats()
+	assert.Equal(t, int64(4), detailed.RecipesFailed)
+	assert.Equal(t, int64(1), detailed.FailedHTTP)
+	assert.Equal(t, int64(1), detailed.FailedTimeout)
+	assert.Equal(t, int64(1), detailed.FailedEmpty)
+	assert.Equal(t, int64(1), detailed.FailedParse)
+
+	entries := failed.Entries()
+	require.Len(t, entries, 4)
+	for i, category := range categories {
+		assert.Equal(t, category, entries[i].Title)
+		assert.Equal(t, category, entries[i].Failure)
+	}
+}
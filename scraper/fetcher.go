@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gocolly/colly"
+)
+
+// defaultHeadlessAfter est le nombre de 403 sur une même URL avant de passer par le navigateur headless
+const defaultHeadlessAfter = 2
+
+// Fetcher récupère le contenu d'une page, indépendamment de son analyse (parseRecipePage)
+// collyFetcher passe par un collecteur Colly (implémentation par défaut), headlessFetcher par un navigateur
+// capable de résoudre un challenge JavaScript ; les tests fournissent des pages enregistrées
+// Un statut HTTP en erreur est retourné en *fetchError, avec le corps de la réponse
+type Fetcher interface {
+	Fetch(ctx context.Context, pageURL string) ([]byte, http.Header, error)
+}
+
+// fetchError est une réponse HTTP en erreur (403, 404, 500...) ou une requête sans réponse (StatusCode 0)
+type fetchError struct {
+	StatusCode int
+	Body       []byte // Corps de la réponse, sauvegardé par -debug-html
+	Err        error
+}
+
+func (e *fetchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.Err
+}
+
+// collyFetcher récupère les pages par un collecteur de recette (createRecipeCollector) : en-têtes réalistes,
+// délai adaptatif, disjoncteur, budget de requêtes et statistiques restent ceux des collecteurs Colly
+// Un collecteur est créé par page : la requête est annulée avec ctx (superviseur des workers)
+type collyFetcher struct {
+	stats *ScrapingStats
+}
+
+// newCollyFetcher crée le fetcher par défaut, comptant ses requêtes dans stats
+func newCollyFetcher(stats *ScrapingStats) *collyFetcher {
+	return &collyFetcher{stats: stats}
+}
+
+// Fetch visite la page dans la limite du budget (errBudgetExhausted sans requête s'il est épuisé)
+// La durée réseau mesurée par requestTimings est remise aux destinataires de ctx (withHTTPDuration)
+func (f *collyFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+	collector := createRecipeCollector(ctx, f.stats)
+
+	var body []byte
+	var header http.Header
+	var failed *fetchError
+	collector.OnResponse(func(r *colly.Response) {
+		body = r.Body
+		if r.Headers != nil {
+			header = *r.Headers
+		}
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		failed = &fetchError{StatusCode: r.StatusCode, Body: r.Body}
+	})
+
+	if err := visitWithBudget(collector, pageURL); err != nil {
+		if failed != nil {
+			failed.Err = err
+			return nil, nil, failed
+		}
+		return nil, nil, err
+	}
+	return body, header, nil
+}
+
+// headlessFallback est le transport des collecteurs avec -headless : les requêtes passent par primary
+// et, au after-ième 403 sur une même URL, la page est récupérée par fallback
+// Les cookies obtenus par le navigateur (cf_clearance) sont renvoyés en Set-Cookie : le client HTTP
+// de Colly les range dans sharedCookieJar et les requêtes suivantes continuent sans navigateur
+type headlessFallback struct {
+	primary  http.RoundTripper
+	fallback Fetcher
+	after    int
+
+	mu      sync.Mutex
+	blocked map[string]int // 403 consécutifs par URL
+}
+
+// newHeadlessFallback crée un transport basculant sur fallback après after réponses 403 pour une URL
+func newHeadlessFallback(primary http.RoundTripper, fallback Fetcher, after int) *headlessFallback {
+	return &headlessFallback{primary: primary, fallback: fallback, after: after, blocked: make(map[string]int)}
+}
+
+// RoundTrip exécute la requête par primary, puis par fallback si l'URL est bloquée de façon répétée
+// Si le navigateur échoue aussi, la réponse 403 d'origine est retournée (comptée comme un blocage)
+func (t *headlessFallback) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.primary.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := req.URL.String()
+	if resp.StatusCode != http.StatusForbidden {
+		t.reset(key)
+		return resp, nil
+	}
+	if t.block(key) < t.after {
+		return resp, nil
+	}
+
+	// Corps de la réponse 403 conservé pour la retourner si le navigateur échoue
+	original, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(original))
+
+	logHeadlessFallback(key, t.after)
+	body, header, err := t.fallback.Fetch(req.Context(), key)
+	if err != nil {
+		logHeadlessError(key, err)
+		return resp, nil
+	}
+	t.reset(key)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// block compte un 403 de plus pour l'URL et retourne le total
+func (t *headlessFallback) block(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.blocked[key]++
+	return t.blocked[key]
+}
+
+// reset oublie les 403 d'une URL qui a de nouveau répondu
+func (t *headlessFallback) reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.blocked, key)
+}
+
+// configureHeadless installe le repli headless sous requestTimings quand -headless est actif
+// requestTimings étant partagé par tous les collecteurs, tous en profitent
+// Les requêtes directes passent par le transport installé par configureTransport
+func configureHeadless() {
+	if !opts.Headless {
+		return
+	}
+	fallback := newHeadlessFetcher(opts.HeadlessBrowser, opts.RequestTimeout)
+	requestTimings.base = newHeadlessFallback(requestTimings.base, fallback, opts.HeadlessAfter)
+}
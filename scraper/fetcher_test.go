@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fetcherFunc adapte une fonction à l'interface Fetcher (pages enregistrées, erreurs simulées)
+type fetcherFunc func(ctx context.Context, pageURL string) ([]byte, http.Header, error)
+
+func (f fetcherFunc) Fetch(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+	return f(ctx, pageURL)
+}
+
+// htmlPage retourne une page HTML telle qu'un Fetcher la récupère
+func htmlPage(body string) ([]byte, http.Header, error) {
+	header := make(http.Header)
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	return []byte(body), header, nil
+}
+
+// fakeFetcher remplace le navigateur headless : il retourne la page résolue et un cookie de session
+type fakeFetcher struct {
+	calls int
+	err   error
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	body, header, _ := htmlPage("<html><h1>Résolu</h1></html>")
+	header.Add("Set-Cookie", (&http.Cookie{Name: "cf_clearance", Value: "ok", Path: "/"}).String())
+	return body, header, nil
+}
+
+// Après deux 403 sur la même URL, la page passe par le navigateur et la suite utilise ses cookies
+func TestHeadlessFallbackAfterRepeated403(t *testing.T) {
+	var cookies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookies = append(cookies, r.Header.Get("Cookie"))
+		if _, err := r.Cookie("cf_clearance"); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Just a moment..."))
+			return
+		}
+		w.Write([]byte("<html><h1>Direct</h1></html>"))
+	}))
+	defer server.Close()
+
+	headless := &fakeFetcher{}
+	collector := colly.NewCollector(colly.AllowURLRevisit())
+	collector.SetCookieJar(newCookieJar())
+	collector.WithTransport(newHeadlessFallback(http.DefaultTransport, headless, 2))
+
+	var titles []string
+	var statuses []int
+	collector.OnHTML("h1", func(e *colly.HTMLElement) { titles = append(titles, e.Text) })
+	collector.OnError(func(r *colly.Response, err error) { statuses = append(statuses, r.StatusCode) })
+
+	assert.Error(t, collector.Visit(server.URL+"/recipe"))
+	assert.Equal(t, 0, headless.calls, "un seul 403 ne lance pas le navigateur")
+
+	require.NoError(t, collector.Visit(server.URL+"/recipe"))
+	assert.Equal(t, 1, headless.calls)
+	assert.Equal(t, []string{"Résolu"}, titles)
+
+	// Le cookie obtenu par le navigateur est renvoyé par Colly : plus de 403 ni de navigateur
+	require.NoError(t, collector.Visit(server.URL+"/recipe/autre"))
+	assert.Equal(t, 1, headless.calls)
+	assert.Equal(t, []string{"Résolu", "Direct"}, titles)
+	assert.Equal(t, []int{http.StatusForbidden}, statuses)
+	assert.Contains(t, cookies[len(cookies)-1], "cf_clearance=ok")
+}
+
+// Les 403 sont comptés par URL : une autre URL bloquée une fois ne déclenche pas le navigateur
+func TestHeadlessFallbackCountsPerURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	headless := &fakeFetcher{}
+	fallback := newHeadlessFallback(http.DefaultTransport, headless, 2)
+	client := &http.Client{Transport: fallback}
+
+	for _, path := range []string{"/a", "/b"} {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+	assert.Equal(t, 0, headless.calls)
+}
+
+// Si le navigateur échoue, la réponse 403 d'origine est retournée, corps compris
+func TestHeadlessFallbackKeeps403OnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("bloqué"))
+	}))
+	defer server.Close()
+
+	headless := &fakeFetcher{err: errors.New("navigateur introuvable")}
+	client := &http.Client{Transport: newHeadlessFallback(http.DefaultTransport, headless, 1)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "bloqué", string(body))
+	assert.Equal(t, 1, headless.calls)
+}
+
+// Les réponses CDP sont associées à leur commande, les événements intermédiaires ignorés
+func TestCDPSessionCall(t *testing.T) {
+	var sent bytes.Buffer
+	received := `{"method":"Target.targetCreated","params":{}}` + "\x00" +
+		`{"id":1,"result":{"targetId":"T1"}}` + "\x00" +
+		`{"id":2,"error":{"message":"No target with given id"}}` + "\x00"
+	session := &cdpSession{w: &sent, r: bufio.NewReader(strings.NewReader(received))}
+
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	require.NoError(t, session.call("", "Target.createTarget", map[string]interface{}{"url": "about:blank"}, &target))
+	assert.Equal(t, "T1", target.TargetID)
+	assert.Equal(t, `{"id":1,"method":"Target.createTarget","params":{"url":"about:blank"}}`+"\x00", sent.String())
+
+	err := session.call("S1", "Target.attachToTarget", nil, nil)
+	assert.EqualError(t, err, "Target.attachToTarget: No target with given id")
+}
+
+func TestCDPCookieConversion(t *testing.T) {
+	cookie := cdpCookie{Name: "cf_clearance", Value: "abc", Domain: ".allrecipes.com", Path: "/", Expires: 4102444800.5, HTTPOnly: true, Secure: true}.httpCookie()
+	assert.Equal(t, time.Unix(4102444800, 5e8), cookie.Expires)
+	assert.True(t, cookie.HttpOnly)
+
+	// Un cookie de session (expires -1) n'a pas de date d'expiration
+	session := cdpCookie{Name: "s", Value: "v", Expires: -1}.httpCookie()
+	assert.True(t, session.Expires.IsZero())
+
+	// Le cookie est accepté par le jar pour le domaine de la page
+	jar := newCookieJar()
+	page, _ := url.Parse("https://www.allrecipes.com/recipe/1")
+	jar.SetCookies(page, []*http.Cookie{cookie})
+	assert.Len(t, jar.Cookies(page), 1)
+}
+
+func TestHeadlessOptions(t *testing.T) {
+	o, err := parseOptions(nil, io.Discard)
+	require.NoError(t, err)
+	assert.False(t, o.Headless)
+	assert.Equal(t, defaultHeadlessBrowser, o.HeadlessBrowser)
+	assert.Equal(t, defaultHeadlessAfter, o.HeadlessAfter)
+
+	o, err = parseOptions([]string{"-headless", "-headless-browser", "/usr/bin/google-chrome", "-headless-after", "3"}, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, o.Headless)
+	assert.Equal(t, "/usr/bin/google-chrome", o.HeadlessBrowser)
+	assert.Equal(t, 3, o.HeadlessAfter)
+
+	_, err = parseOptions([]string{"-headless-after", "0"}, io.Discard)
+	assert.Error(t, err)
+}
+
+// Le traitement d'une recette ne dépend que du Fetcher : pages enregistrées, sans serveur ni délai
+func TestProcessRecipeWithFixtureFetcher(t *testing.T) {
+	fixture := loadFixture(t, "recipe_cookies.html")
+	var requested []string
+	fetcher := fetcherFunc(func(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+		requested = append(requested, pageURL)
+		if strings.Contains(pageURL, "/recipe/404/") {
+			return nil, nil, &fetchError{StatusCode: http.StatusNotFound, Err: errors.New("Not Found")}
+		}
+		reportRequest(ctx, requestTiming{URL: pageURL, Duration: 40 * time.Millisecond})
+		return fixture, http.Header{"Content-Type": {"text/html"}}, nil
+	})
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	failed := &failedRecipes{}
+	workerStats := &WorkerStats{WorkerID: 1}
+
+	processRecipeReusable(RecipeData{URL: "https://www.allrecipes.com/recipe/1/soupe/", Category: "soups"}, fetcher, stats, completedRecipes, workerStats, nil, failed)
+	processRecipeReusable(RecipeData{URL: "https://www.allrecipes.com/recipe/404/absente/", Title: "Absente"}, fetcher, stats, completedRecipes, workerStats, nil, failed)
+
+	assert.Equal(t, []string{"https://www.allrecipes.com/recipe/1/soupe/", "https://www.allrecipes.com/recipe/404/absente/"}, requested)
+	require.Len(t, completedRecipes, 1)
+	recipe := <-completedRecipes
+	assert.Equal(t, "Best Chocolate Chip Cookies", recipe.Name)
+	assert.Len(t, recipe.Ingredients, 10)
+	assert.Equal(t, "soups", recipe.Category)
+	assert.Equal(t, 40*time.Millisecond, workerStats.HTTPDuration)
+
+	require.Len(t, failed.entries, 1)
+	assert.Equal(t, http.StatusNotFound, failed.entries[0].StatusCode)
+	assert.Equal(t, 1, failed.entries[0].Attempts)
+	assert.Equal(t, failureHTTP, failed.entries[0].Failure)
+	assert.Equal(t, int64(2), workerStats.RequestsHandled)
+}
+
+// collyFetcher retourne le corps et les en-têtes, et une *fetchError avec le statut en cas d'erreur HTTP
+func TestCollyFetcher(t *testing.T) {
+	useTestCookieJar(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/absente" {
+			http.Error(w, "introuvable", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	fetcher := newCollyFetcher(stats)
+
+	var httpDuration time.Duration
+	body, header, err := fetcher.Fetch(withHTTPDuration(context.Background(), &httpDuration), server.URL+"/recipe")
+	require.NoError(t, err)
+	assert.Equal(t, recipePageHTML, string(body))
+	assert.Equal(t, "text/html", header.Get("Content-Type"))
+	assert.Greater(t, httpDuration, time.Duration(0))
+
+	_, _, err = fetcher.Fetch(context.Background(), server.URL+"/absente")
+	var fetchErr *fetchError
+	require.ErrorAs(t, err, &fetchErr)
+	assert.Equal(t, http.StatusNotFound, fetchErr.StatusCode)
+	assert.Contains(t, string(fetchErr.Body), "introuvable")
+	assert.Equal(t, int64(2), stats.RecipeRequests)
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// defaultHeadlessBrowser est le navigateur lancé par -headless (Chrome ou Chromium, cherché dans le PATH)
+const defaultHeadlessBrowser = "chromium"
+
+// headlessPollInterval est la période de vérification de la fin du challenge JavaScript
+const headlessPollInterval = 500 * time.Millisecond
+
+// challengeDone est évalué dans la page : vrai une fois la page chargée et le challenge Cloudflare passé
+const challengeDone = `document.readyState === "complete" && !/just a moment|un instant|attention required/i.test(document.title)`
+
+// headlessFetcher récupère une page dans un navigateur headless piloté par le protocole DevTools (CDP)
+// Le navigateur est lancé pour chaque page avec --remote-debugging-pipe : les messages JSON
+// transitent par les descripteurs 3 et 4, sans port réseau ni dépendance supplémentaire
+// Les requêtes sont sérialisées : un seul navigateur tourne à la fois
+type headlessFetcher struct {
+	browser string
+	timeout time.Duration
+	mu      sync.Mutex
+}
+
+// newHeadlessFetcher crée un fetcher lançant browser, chaque page étant bornée par timeout
+func newHeadlessFetcher(browser string, timeout time.Duration) *headlessFetcher {
+	return &headlessFetcher{browser: browser, timeout: timeout}
+}
+
+// Fetch ouvre la page dans le navigateur, attend la fin du challenge et retourne le HTML rendu
+// Les en-têtes portent les cookies du navigateur en Set-Cookie, pour le cookie jar du client HTTP
+func (f *headlessFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	profile, err := os.MkdirTemp("", "scraper-headless-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(profile)
+
+	args := []string{
+		"--headless=new",
+		"--remote-debugging-pipe",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-gpu",
+		"--user-data-dir=" + profile,
+		"--user-agent=" + getRandomUserAgent(),
+		"about:blank",
+	}
+	cmd := exec.CommandContext(ctx, f.browser, args...)
+
+	// Descripteur 3 : commandes lues par le navigateur ; descripteur 4 : réponses et événements
+	commandsRead, commandsWrite, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer commandsWrite.Close()
+	eventsRead, eventsWrite, err := os.Pipe()
+	if err != nil {
+		commandsRead.Close()
+		return nil, nil, err
+	}
+	defer eventsRead.Close()
+	cmd.ExtraFiles = []*os.File{commandsRead, eventsWrite}
+
+	err = cmd.Start()
+	// Les extrémités du navigateur ne servent plus au processus courant
+	commandsRead.Close()
+	eventsWrite.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("lancement du navigateur %q: %w", f.browser, err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	// Cookies du jar partagé pour cette page (-cookie, warm-up, challenges déjà résolus)
+	var jarCookies []*http.Cookie
+	if u, err := url.Parse(pageURL); err == nil {
+		jarCookies = sharedCookieJar.Cookies(u)
+	}
+
+	session := &cdpSession{w: commandsWrite, r: bufio.NewReader(eventsRead)}
+	html, cookies, err := session.load(ctx, pageURL, jarCookies)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w (challenge non résolu en %s)", ctx.Err(), f.timeout)
+		}
+		return nil, nil, err
+	}
+	session.call("", "Browser.close", nil, nil)
+
+	header := make(http.Header)
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	for _, cookie := range cookies {
+		header.Add("Set-Cookie", cookie.String())
+	}
+	return []byte(html), header, nil
+}
+
+// cdpSession échange des messages CDP avec le navigateur (JSON terminé par un octet nul)
+type cdpSession struct {
+	w      io.Writer
+	r      *bufio.Reader
+	nextID int
+}
+
+// cdpMessage est une commande, une réponse ou un événement CDP
+type cdpMessage struct {
+	ID        int             `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    interface{}     `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// call envoie une commande (à la cible sessionID, ou au navigateur si vide) et décode sa réponse dans result
+// Les événements reçus entre-temps sont ignorés
+func (s *cdpSession) call(sessionID, method string, params, result interface{}) error {
+	s.nextID++
+	id := s.nextID
+	message, err := json.Marshal(cdpMessage{ID: id, SessionID: sessionID, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if _, err := s.w.Write(append(message, 0)); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	for {
+		raw, err := s.r.ReadBytes(0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		var response cdpMessage
+		if err := json.Unmarshal(raw[:len(raw)-1], &response); err != nil {
+			return fmt.Errorf("%s: réponse illisible: %w", method, err)
+		}
+		if response.ID != id {
+			continue
+		}
+		if response.Error != nil {
+			return fmt.Errorf("%s: %s", method, response.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// evaluate évalue une expression dans la page et décode sa valeur dans value
+func (s *cdpSession) evaluate(sessionID, expression string, value interface{}) error {
+	var result struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	params := map[string]interface{}{"expression": expression, "returnByValue": true}
+	if err := s.call(sessionID, "Runtime.evaluate", params, &result); err != nil {
+		return err
+	}
+	if result.ExceptionDetails != nil {
+		return errors.New(result.ExceptionDetails.Text)
+	}
+	return json.Unmarshal(result.Result.Value, value)
+}
+
+// load ouvre pageURL dans un nouvel onglet, attend la fin du challenge et retourne le HTML et les cookies
+// jarCookies sont posés pour pageURL avant l'ouverture de l'onglet
+// Une lecture bloquée est débloquée par l'arrêt du navigateur quand ctx expire (exec.CommandContext)
+func (s *cdpSession) load(ctx context.Context, pageURL string, jarCookies []*http.Cookie) (string, []*http.Cookie, error) {
+	if len(jarCookies) > 0 {
+		params := make([]map[string]string, 0, len(jarCookies))
+		for _, cookie := range jarCookies {
+			params = append(params, map[string]string{"name": cookie.Name, "value": cookie.Value, "url": pageURL})
+		}
+		if err := s.call("", "Storage.setCookies", map[string]interface{}{"cookies": params}, nil); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := s.call("", "Target.createTarget", map[string]interface{}{"url": pageURL}, &target); err != nil {
+		return "", nil, err
+	}
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	params := map[string]interface{}{"targetId": target.TargetID, "flatten": true}
+	if err := s.call("", "Target.attachToTarget", params, &attached); err != nil {
+		return "", nil, err
+	}
+
+	ticker := time.NewTicker(headlessPollInterval)
+	defer ticker.Stop()
+	for {
+		var done bool
+		if err := s.evaluate(attached.SessionID, challengeDone, &done); err != nil {
+			return "", nil, err
+		}
+		if done {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	var html string
+	if err := s.evaluate(attached.SessionID, "document.documentElement.outerHTML", &html); err != nil {
+		return "", nil, err
+	}
+	var stored struct {
+		Cookies []cdpCookie `json:"cookies"`
+	}
+	if err := s.call("", "Storage.getCookies", nil, &stored); err != nil {
+		return "", nil, err
+	}
+	cookies := make([]*http.Cookie, 0, len(stored.Cookies))
+	for _, cookie := range stored.Cookies {
+		cookies = append(cookies, cookie.httpCookie())
+	}
+	return html, cookies, nil
+}
+
+// cdpCookie est un cookie tel que retourné par Storage.getCookies
+type cdpCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"` // Secondes depuis l'epoch, -1 pour un cookie de session
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+}
+
+// httpCookie convertit le cookie pour un en-tête Set-Cookie
+func (c cdpCookie) httpCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		HttpOnly: c.HTTPOnly,
+		Secure:   c.Secure,
+	}
+	if c.Expires > 0 {
+		seconds, fraction := math.Modf(c.Expires)
+		cookie.Expires = time.Unix(int64(seconds), int64(fraction*1e9))
+	}
+	return cookie
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrowserEnv lance le binaire de test comme faux navigateur (done : challenge résolu, stuck : jamais résolu)
+const fakeBrowserEnv = "SCRAPER_TEST_FAKE_BROWSER"
+
+// fakeBrowserHTML est la page rendue par le faux navigateur
+const fakeBrowserHTML = "<html><head><title>Soupe</title></head><body><h1>Soupe de légumes</h1></body></html>"
+
+// TestMain remplace le navigateur headless quand le binaire de test est lancé par headlessFetcher :
+// les commandes CDP arrivent sur le descripteur 3, les réponses repartent sur le descripteur 4
+func TestMain(m *testing.M) {
+	switch os.Getenv(fakeBrowserEnv) {
+	case "done":
+		serveFakeBrowser(os.NewFile(3, "commands"), os.NewFile(4, "events"), 1)
+		os.Exit(0)
+	case "stuck":
+		serveFakeBrowser(os.NewFile(3, "commands"), os.NewFile(4, "events"), -1)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// serveFakeBrowser répond aux commandes CDP comme Chrome avec --remote-debugging-pipe, jusqu'à Browser.close
+// Le challenge est résolu après pollsBeforeDone évaluations (jamais si négatif) ; un événement précède
+// chaque réponse, comme les notifications du navigateur. Retourne les commandes reçues ("session méthode")
+func serveFakeBrowser(r io.Reader, w io.Writer, pollsBeforeDone int) []string {
+	var received []string
+	reader := bufio.NewReader(r)
+	polls := 0
+	send := func(message interface{}) {
+		raw, _ := json.Marshal(message)
+		w.Write(append(raw, 0))
+	}
+
+	for {
+		raw, err := reader.ReadBytes(0)
+		if err != nil {
+			return received
+		}
+		var command struct {
+			ID        int    `json:"id"`
+			SessionID string `json:"sessionId"`
+			Method    string `json:"method"`
+			Params    struct {
+				Expression string `json:"expression"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(raw[:len(raw)-1], &command); err != nil {
+			return received
+		}
+		received = append(received, command.SessionID+" "+command.Method)
+		send(map[string]interface{}{"method": "Page.frameNavigated", "params": map[string]interface{}{}})
+
+		var result interface{}
+		switch command.Method {
+		case "Target.createTarget":
+			result = map[string]string{"targetId": "target-1"}
+		case "Target.attachToTarget":
+			result = map[string]string{"sessionId": "session-1"}
+		case "Runtime.evaluate":
+			var value interface{} = fakeBrowserHTML
+			if command.Params.Expression == challengeDone {
+				polls++
+				value = pollsBeforeDone >= 0 && polls > pollsBeforeDone
+			}
+			result = map[string]interface{}{"result": map[string]interface{}{"value": value}}
+		case "Storage.setCookies":
+			result = map[string]interface{}{}
+		case "Storage.getCookies":
+			result = map[string]interface{}{"cookies": []map[string]interface{}{
+				{"name": "cf_clearance", "value": "ok", "domain": "example.com", "path": "/", "expires": 1893456000.5, "httpOnly": true, "secure": true},
+				{"name": "session", "value": "abc", "domain": "example.com", "path": "/", "expires": -1},
+			}}
+		case "Browser.close":
+			send(map[string]interface{}{"id": command.ID, "result": map[string]interface{}{}})
+			return received
+		default:
+			send(map[string]interface{}{"id": command.ID, "error": map[string]string{"message": "'" + command.Method + "' wasn't found"}})
+			continue
+		}
+		send(map[string]interface{}{"id": command.ID, "sessionId": command.SessionID, "result": result})
+	}
+}
+
+// newFakeBrowserSession relie une session CDP à un faux navigateur par des pipes en mémoire
+// La fonction retournée ferme la session et retourne les commandes reçues par le navigateur
+func newFakeBrowserSession(pollsBeforeDone int) (*cdpSession, func() []string) {
+	commandsRead, commandsWrite := io.Pipe()
+	eventsRead, eventsWrite := io.Pipe()
+	received := make(chan []string, 1)
+	go func() {
+		received <- serveFakeBrowser(commandsRead, eventsWrite, pollsBeforeDone)
+		eventsWrite.Close()
+	}()
+
+	session := &cdpSession{w: commandsWrite, r: bufio.NewReader(eventsRead)}
+	return session, func() []string {
+		commandsWrite.Close()
+		return <-received
+	}
+}
+
+// Test de la session CDP : onglet, attente du challenge, HTML et cookies, événements intercalés ignorés
+func TestCDPSessionLoad(t *testing.T) {
+	session, closeSession := newFakeBrowserSession(1)
+
+	html, cookies, err := session.load(context.Background(), "https://example.com/recette", nil)
+	require.NoError(t, err)
+	assert.Equal(t, fakeBrowserHTML, html)
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "cf_clearance", cookies[0].Name)
+	assert.Equal(t, time.Unix(1893456000, 5e8), cookies[0].Expires)
+
+	// Les évaluations visent l'onglet attaché, les autres commandes le navigateur
+	assert.Equal(t, []string{
+		" Target.createTarget",
+		" Target.attachToTarget",
+		"session-1 Runtime.evaluate",
+		"session-1 Runtime.evaluate",
+		"session-1 Runtime.evaluate",
+		" Storage.getCookies",
+	}, closeSession())
+}
+
+// Les cookies du jar sont posés dans le navigateur avant l'ouverture de l'onglet
+func TestCDPSessionLoadWithCookies(t *testing.T) {
+	session, closeSession := newFakeBrowserSession(0)
+
+	_, _, err := session.load(context.Background(), "https://example.com/recette", []*http.Cookie{{Name: "session", Value: "abc123"}})
+	require.NoError(t, err)
+	received := closeSession()
+	require.NotEmpty(t, received)
+	assert.Equal(t, " Storage.setCookies", received[0])
+	assert.Equal(t, " Target.createTarget", received[1])
+}
+
+// Test de bout en bout : le navigateur est un processus lancé avec les pipes des descripteurs 3 et 4
+func TestHeadlessFetcherFetch(t *testing.T) {
+	t.Setenv(fakeBrowserEnv, "done")
+	fetcher := newHeadlessFetcher(os.Args[0], 10*time.Second)
+
+	body, header, err := fetcher.Fetch(context.Background(), "https://example.com/recette")
+	require.NoError(t, err)
+	assert.Equal(t, fakeBrowserHTML, string(body))
+	assert.Equal(t, "text/html; charset=utf-8", header.Get("Content-Type"))
+
+	response := http.Response{Header: header}
+	cookies := response.Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "cf_clearance", cookies[0].Name)
+	assert.Equal(t, "ok", cookies[0].Value)
+}
+
+// Un challenge jamais résolu est interrompu à l'expiration du délai, navigateur arrêté
+func TestHeadlessFetcherTimeout(t *testing.T) {
+	t.Setenv(fakeBrowserEnv, "stuck")
+	fetcher := newHeadlessFetcher(os.Args[0], time.Second)
+
+	start := time.Now()
+	_, _, err := fetcher.Fetch(context.Background(), "https://example.com/recette")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "challenge non résolu")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// Un navigateur introuvable est signalé sans attendre le délai
+func TestHeadlessFetcherMissingBrowser(t *testing.T) {
+	fetcher := newHeadlessFetcher("scraper-test-navigateur-absent", 10*time.Second)
+
+	_, _, err := fetcher.Fetch(context.Background(), "https://example.com/recette")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lancement du navigateur")
+}
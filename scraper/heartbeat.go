@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval est la période de vérification du superviseur des workers
+const heartbeatInterval = 5 * time.Second
+
+// workerActivity décrit la dernière activité connue d'un worker
+type workerActivity struct {
+	Busy   bool      // Le worker traite une recette
+	URL    string    // Recette en cours de traitement
+	Since  time.Time // Début de la tâche en cours (ou fin de la dernière)
+	cancel context.CancelFunc
+	warned bool // Avertissement déjà émis pour la tâche en cours
+}
+
+// stuckWorker décrit un worker bloqué sur une recette au-delà du seuil
+type stuckWorker struct {
+	WorkerID int
+	URL      string
+	Elapsed  time.Duration
+}
+
+// workerMonitor enregistre le heartbeat de chaque worker
+// Thread-safe : mis à jour par les workers, lu par le superviseur
+type workerMonitor struct {
+	mu      sync.Mutex
+	workers map[int]*workerActivity
+}
+
+// newWorkerMonitor crée un moniteur vide
+func newWorkerMonitor() *workerMonitor {
+	return &workerMonitor{workers: make(map[int]*workerActivity)}
+}
+
+// Begin signale qu'un worker commence une recette (cancel permet d'interrompre sa requête)
+func (m *workerMonitor) Begin(workerID int, url string, cancel context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers[workerID] = &workerActivity{
+		Busy:   true,
+		URL:    url,
+		Since:  time.Now(),
+		cancel: cancel,
+	}
+}
+
+// End signale qu'un worker a terminé sa recette
+func (m *workerMonitor) End(workerID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers[workerID] = &workerActivity{Since: time.Now()}
+}
+
+// Stuck retourne les workers occupés depuis plus de threshold, triés par ID
+func (m *workerMonitor) Stuck(threshold time.Duration, now time.Time) []stuckWorker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stuck []stuckWorker
+	for id, activity := range m.workers {
+		if elapsed := now.Sub(activity.Since); activity.Busy && elapsed > threshold {
+			stuck = append(stuck, stuckWorker{WorkerID: id, URL: activity.URL, Elapsed: elapsed})
+		}
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].WorkerID < stuck[j].WorkerID })
+	return stuck
+}
+
+// cancelStuck interrompt la requête d'un worker bloqué (une seule fois par tâche)
+// Retourne false si l'avertissement a déjà été émis
+func (m *workerMonitor) cancelStuck(workerID int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	activity, ok := m.workers[workerID]
+	if !ok || !activity.Busy || activity.warned {
+		return false
+	}
+	activity.warned = true
+	if activity.cancel != nil {
+		activity.cancel()
+	}
+	return true
+}
+
+// supervise vérifie périodiquement les heartbeats jusqu'à la fermeture de stop
+// Les workers bloqués sont signalés et leur requête en cours est annulée
+func (m *workerMonitor) supervise(interval, threshold time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			for _, worker := range m.Stuck(threshold, now) {
+				if m.cancelStuck(worker.WorkerID) {
+					logWorkerStuck(worker.WorkerID, worker.URL, worker.Elapsed)
+				}
+			}
+		}
+	}
+}
+
+// contextTransport rattache un contexte annulable à chaque requête d'un collecteur
+// Colly v1 ne propage pas de contexte : c'est le seul moyen d'interrompre une requête en vol
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip exécute la requête avec le contexte du transport
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
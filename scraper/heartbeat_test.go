@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de la détection des workers occupés au-delà du seuil
+func TestWorkerMonitorStuck(t *testing.T) {
+	monitor := newWorkerMonitor()
+	monitor.Begin(1, "https://example.com/a", nil)
+	monitor.Begin(2, "https://example.com/b", nil)
+	monitor.End(2)
+
+	// Worker 2 inactif : jamais signalé, même après le seuil
+	stuck := monitor.Stuck(time.Minute, time.Now().Add(2*time.Minute))
+	require.Len(t, stuck, 1)
+	assert.Equal(t, 1, stuck[0].WorkerID)
+	assert.Equal(t, "https://example.com/a", stuck[0].URL)
+
+	assert.Empty(t, monitor.Stuck(time.Minute, time.Now()))
+}
+
+// Test avec un serveur qui ne répond jamais : le worker est détecté et débloqué
+func TestSupervisorCancelsHangingWorker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done() // Bloque jusqu'à l'annulation côté client
+	}))
+	defer server.Close()
+
+	monitor := newWorkerMonitor()
+	stop := make(chan struct{})
+	defer close(stop)
+	go monitor.supervise(20*time.Millisecond, 100*time.Millisecond, stop)
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	workerStats := &WorkerStats{WorkerID: 7}
+
+	done := make(chan struct{})
+	go func() {
+		processRecipeReusable(RecipeData{URL: server.URL + "/recipe", Title: "Bloquée"}, newCollyFetcher(stats), stats, completedRecipes, workerStats, monitor, nil)
+		close(done)
+	}()
+
+	// Le worker doit être signalé bloqué puis libéré bien avant le timeout HTTP
+	select {
+	case <-done:
+	case <-time.After(opts.RequestTimeout / 2):
+		t.Fatal("le worker bloqué n'a pas été débloqué par le superviseur")
+	}
+
+	assert.Equal(t, int64(1), stats.RecipesFailed)
+	assert.Empty(t, completedRecipes)
+	assert.Empty(t, monitor.Stuck(0, time.Now()), "le worker doit être marqué inactif après annulation")
+}
+
+func TestStuckThresholdOption(t *testing.T) {
+	o, err := parseOptions([]string{"-stuck-threshold", "2m"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, o.StuckThreshold)
+}
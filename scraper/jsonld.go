@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Stratégies d'extraction des recettes (-extractor)
+const (
+	extractorCSS    = "css"    // Sélecteurs CSS AllRecipes uniquement (défaut historique)
+	extractorJSONLD = "jsonld" // Données structurées schema.org uniquement
+	extractorAuto   = "auto"   // JSON-LD d'abord, sélecteurs CSS si la page n'en a pas
+)
+
+// parseExtractor valide la valeur de -extractor
+func parseExtractor(value string) (string, error) {
+	switch value {
+	case extractorCSS, extractorJSONLD, extractorAuto:
+		return value, nil
+	}
+	return "", fmt.Errorf("stratégie d'extraction invalide %q: css, jsonld ou auto attendu", value)
+}
+
+// jsonLDRecipe est le sous-ensemble d'un objet schema.org/Recipe utilisé par le scraper
+type jsonLDRecipe struct {
+	Ingredients  []Ingredient
+	Instructions []Instruction
+	Image        string
+	Categories   []string // recipeCategory (type de plat)
+	Cuisines     []string // recipeCuisine
+	Details      jsonLDDetails
+}
+
+// jsonLDDetails regroupe les temps, la nutrition et la note d'un objet Recipe
+// Ils sont repris quelle que soit la stratégie d'extraction : les sélecteurs CSS ne les lisent pas
+type jsonLDDetails struct {
+	PrepTimeMinutes  int        // prepTime (durée ISO 8601)
+	CookTimeMinutes  int        // cookTime
+	TotalTimeMinutes int        // totalTime, ou prepTime + cookTime à défaut
+	Nutrition        *Nutrition // nutrition (NutritionInformation), nil si aucune valeur
+	Rating           *Rating    // aggregateRating, nil sans note
+}
+
+// apply renseigne les temps, la nutrition et la note de la recette
+func (d jsonLDDetails) apply(recipe *Recipe) {
+	recipe.PrepTimeMinutes = d.PrepTimeMinutes
+	recipe.CookTimeMinutes = d.CookTimeMinutes
+	recipe.TotalTimeMinutes = d.TotalTimeMinutes
+	recipe.Nutrition = d.Nutrition
+	recipe.Rating = d.Rating
+}
+
+// jsonLDNode est un objet JSON-LD quelconque, dont on ne lit que les champs utiles
+type jsonLDNode struct {
+	Type         json.RawMessage   `json:"@type"`
+	Graph        []json.RawMessage `json:"@graph"`
+	Ingredients  []string          `json:"recipeIngredient"`
+	Instructions json.RawMessage   `json:"recipeInstructions"`
+	Image        json.RawMessage   `json:"image"`
+	Category     json.RawMessage   `json:"recipeCategory"`
+	Cuisine      json.RawMessage   `json:"recipeCuisine"`
+	PrepTime     json.RawMessage   `json:"prepTime"`
+	CookTime     json.RawMessage   `json:"cookTime"`
+	TotalTime    json.RawMessage   `json:"totalTime"`
+	Nutrition    json.RawMessage   `json:"nutrition"`
+	Rating       json.RawMessage   `json:"aggregateRating"`
+}
+
+// parseJSONLDRecipe cherche un objet Recipe dans le contenu d'une balise <script type="application/ld+json">
+// Le contenu peut être un objet, un tableau d'objets ou un objet @graph ; nil si aucune recette n'est trouvée
+func parseJSONLDRecipe(content string) *jsonLDRecipe {
+	var raw json.RawMessage
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil
+	}
+	return findJSONLDRecipe(raw)
+}
+
+// findJSONLDRecipe parcourt récursivement les tableaux et les @graph
+func findJSONLDRecipe(raw json.RawMessage) *jsonLDRecipe {
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		for _, item := range list {
+			if recipe := findJSONLDRecipe(item); recipe != nil {
+				return recipe
+			}
+		}
+		return nil
+	}
+
+	var node jsonLDNode
+	if json.Unmarshal(raw, &node) != nil {
+		return nil
+	}
+	for _, item := range node.Graph {
+		if recipe := findJSONLDRecipe(item); recipe != nil {
+			return recipe
+		}
+	}
+	if !jsonLDHasType(node.Type, "Recipe") {
+		return nil
+	}
+
+	recipe := &jsonLDRecipe{
+		Image:      jsonLDImage(node.Image),
+		Categories: jsonLDStrings(node.Category),
+		Cuisines:   jsonLDStrings(node.Cuisine),
+		Details: jsonLDDetails{
+			PrepTimeMinutes:  jsonLDMinutes(node.PrepTime),
+			CookTimeMinutes:  jsonLDMinutes(node.CookTime),
+			TotalTimeMinutes: jsonLDMinutes(node.TotalTime),
+			Nutrition:        jsonLDNutrition(node.Nutrition),
+			Rating:           jsonLDRating(node.Rating),
+		},
+	}
+	if recipe.Details.TotalTimeMinutes == 0 {
+		recipe.Details.TotalTimeMinutes = recipe.Details.PrepTimeMinutes + recipe.Details.CookTimeMinutes
+	}
+	for _, text := range node.Ingredients {
+		if text = strings.TrimSpace(text); text != "" {
+			// Même convention que les sélecteurs CSS : texte complet dans Quantity
+			recipe.Ingredients = append(recipe.Ingredients, Ingredient{Quantity: text, Name: text})
+		}
+	}
+	for _, text := range jsonLDSteps(node.Instructions) {
+		recipe.Instructions = append(recipe.Instructions, Instruction{
+			Number:      strconv.Itoa(len(recipe.Instructions) + 1),
+			Description: text,
+		})
+	}
+	return recipe
+}
+
+// jsonLDHasType indique si @type (chaîne ou tableau) contient le type attendu
+func jsonLDHasType(raw json.RawMessage, want string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == want
+	}
+	var types []string
+	if json.Unmarshal(raw, &types) == nil {
+		for _, t := range types {
+			if t == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDStrings lit une valeur texte ou une liste de textes (recipeCategory, recipeCuisine), sans les vides
+func jsonLDStrings(raw json.RawMessage) []string {
+	var values []string
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		values = []string{single}
+	} else if json.Unmarshal(raw, &values) != nil {
+		return nil
+	}
+	var texts []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			texts = append(texts, value)
+		}
+	}
+	return texts
+}
+
+// jsonLDText lit une valeur texte ou numérique ("250 calories", 4.5), "" pour toute autre forme
+func jsonLDText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+	var number json.Number
+	if json.Unmarshal(raw, &number) == nil {
+		return number.String()
+	}
+	return ""
+}
+
+// isoDuration reconnaît une durée ISO 8601 (PT1H30M, P0DT0H20M, PT90M...)
+var isoDuration = regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// jsonLDMinutes convertit une durée ISO 8601 (prepTime, cookTime, totalTime) en minutes arrondies
+// Une durée absente ou illisible donne 0, comme un temps inconnu
+func jsonLDMinutes(raw json.RawMessage) int {
+	match := isoDuration.FindStringSubmatch(jsonLDText(raw))
+	if match == nil {
+		return 0
+	}
+	var minutes float64
+	for i, unit := range []float64{24 * 60, 60, 1, 1.0 / 60} {
+		if value, err := strconv.ParseFloat(match[i+1], 64); err == nil {
+			minutes += value * unit
+		}
+	}
+	return int(math.Round(minutes))
+}
+
+// jsonLDNutrition lit les valeurs d'un objet NutritionInformation, unités comprises ("12 g")
+func jsonLDNutrition(raw json.RawMessage) *Nutrition {
+	var node struct {
+		Calories      json.RawMessage `json:"calories"`
+		Fat           json.RawMessage `json:"fatContent"`
+		Carbohydrates json.RawMessage `json:"carbohydrateContent"`
+		Protein       json.RawMessage `json:"proteinContent"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &node) != nil {
+		return nil
+	}
+	nutrition := Nutrition{
+		Calories:      jsonLDText(node.Calories),
+		Fat:           jsonLDText(node.Fat),
+		Carbohydrates: jsonLDText(node.Carbohydrates),
+		Protein:       jsonLDText(node.Protein),
+	}
+	if nutrition == (Nutrition{}) {
+		return nil
+	}
+	return &nutrition
+}
+
+// jsonLDRating lit un objet AggregateRating (ratingValue, ratingCount ou reviewCount à défaut)
+// Une note absente ou nulle donne nil
+func jsonLDRating(raw json.RawMessage) *Rating {
+	var node struct {
+		Value       json.RawMessage `json:"ratingValue"`
+		RatingCount json.RawMessage `json:"ratingCount"`
+		ReviewCount json.RawMessage `json:"reviewCount"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &node) != nil {
+		return nil
+	}
+	value, err := strconv.ParseFloat(strings.Replace(jsonLDText(node.Value), ",", ".", 1), 64)
+	if err != nil || value <= 0 {
+		return nil
+	}
+	count, err := strconv.Atoi(jsonLDText(node.RatingCount))
+	if err != nil {
+		count, _ = strconv.Atoi(jsonLDText(node.ReviewCount))
+	}
+	return &Rating{Value: value, Count: count}
+}
+
+// jsonLDSteps aplatit recipeInstructions : texte, liste de textes, HowToStep ou HowToSection
+func jsonLDSteps(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		if text = strings.TrimSpace(text); text != "" {
+			return []string{text}
+		}
+		return nil
+	}
+
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		var steps []string
+		for _, item := range list {
+			steps = append(steps, jsonLDSteps(item)...)
+		}
+		return steps
+	}
+
+	var step struct {
+		Text     string          `json:"text"`
+		Elements json.RawMessage `json:"itemListElement"`
+	}
+	if json.Unmarshal(raw, &step) != nil {
+		return nil
+	}
+	if len(step.Elements) > 0 {
+		return jsonLDSteps(step.Elements)
+	}
+	if text = strings.TrimSpace(step.Text); text != "" {
+		return []string{text}
+	}
+	return nil
+}
+
+// jsonLDImage lit image sous forme d'URL, de liste d'URLs ou d'ImageObject
+func jsonLDImage(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var url string
+	if json.Unmarshal(raw, &url) == nil {
+		return url
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		for _, item := range list {
+			if url := jsonLDImage(item); url != "" {
+				return url
+			}
+		}
+		return ""
+	}
+	var object struct {
+		URL string `json:"url"`
+	}
+	json.Unmarshal(raw, &object)
+	return object.URL
+}
+
+// applyExtractor choisit entre l'extraction CSS (déjà dans recipe) et l'extraction JSON-LD selon la stratégie
+func applyExtractor(strategy string, recipe *Recipe, ld *jsonLDRecipe) {
+	useJSONLD := false
+	switch strategy {
+	case extractorJSONLD:
+		useJSONLD = true
+	case extractorAuto:
+		useJSONLD = ld != nil && len(ld.Ingredients) > 0
+	}
+	if !useJSONLD {
+		return
+	}
+
+	recipe.Ingredients = nil
+	recipe.Instructions = nil
+	if ld == nil {
+		return
+	}
+	recipe.Ingredients = ld.Ingredients
+	recipe.Instructions = ld.Instructions
+	if recipe.Image == "" {
+		recipe.Image = ld.Image
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bothFormsHTML contient la même recette en CSS (2 ingrédients) et en JSON-LD (3 ingrédients, étapes différentes,
+// temps, nutrition et note)
+const bothFormsHTML = `<html><head>
+<script type="application/ld+json">{"@context":"https://schema.org","@type":"WebSite","name":"Site"}</script>
+<script type="application/ld+json">{"@context":"https://schema.org","@graph":[
+	{"@type":"BreadcrumbList"},
+	{"@type":["Recipe","NewsArticle"],"name":"Soupe de légumes",
+	 "image":{"@type":"ImageObject","url":"https://img.example/ld.jpg"},
+	 "prepTime":"PT15M","cookTime":"PT25M",
+	 "nutrition":{"@type":"NutritionInformation","calories":"120 kcal","proteinContent":"3 g"},
+	 "aggregateRating":{"@type":"AggregateRating","ratingValue":"4.6","ratingCount":"212"},
+	 "recipeIngredient":["2 carottes","1 oignon","1 l d'eau"],
+	 "recipeInstructions":[{"@type":"HowToSection","itemListElement":[
+		{"@type":"HowToStep","text":"Éplucher et couper les légumes."},
+		{"@type":"HowToStep","text":"Cuire 25 minutes dans l'eau."}]}]}
+]}</script>
+</head><body>
+<h1 class="article-heading">Soupe de légumes</h1>
+<ul class="mm-recipes-structured-ingredients__list">
+	<li class="mm-recipes-structured-ingredients__list-item"><span data-ingredient-quantity="true">2</span> <span data-ingredient-name="true">carottes</span></li>
+	<li class="mm-recipes-structured-ingredients__list-item"><span data-ingredient-quantity="true">1</span> <span data-ingredient-name="true">oignon</span></li>
+</ul>
+<div class="mm-recipes-steps__content"><ol class="mntl-sc-block">
+	<li><p class="mntl-sc-block-html">Éplucher les légumes.</p></li>
+	<li><p class="mntl-sc-block-html">Cuire 20 minutes.</p></li>
+</ol></div>
+</body></html>`
+
+// scrapeWithExtractor scrape une page servie par un fetcher de test avec la stratégie donnée
+func scrapeWithExtractor(t *testing.T, extractor, page string) (Recipe, *recipeExtraction) {
+	fetcher := fetcherFunc(func(ctx context.Context, pageURL string) ([]byte, http.Header, error) {
+		return htmlPage(page)
+	})
+
+	previous := opts
+	defer func() { opts = previous }()
+	opts.EmptyRetries = 0
+
+	completedRecipes := make(chan Recipe, 1)
+	recipe := Recipe{Name: "Soupe", Page: "https://www.allrecipes.com/recipe/1/soupe/"}
+	extraction := scrapeRecipeDetails(context.Background(), fetcher, &recipe, extractor, completedRecipes, NewScrapingStats(1), nil)
+	return recipe, extraction
+}
+
+// Test de chaque stratégie d'extraction sur une page contenant les deux formes
+func TestExtractorStrategies(t *testing.T) {
+	recipe, _ := scrapeWithExtractor(t, extractorCSS, bothFormsHTML)
+	require.Len(t, recipe.Ingredients, 2)
+	assert.Equal(t, "carottes", recipe.Ingredients[0].Name)
+	assert.Equal(t, "Cuire 20 minutes.", recipe.Instructions[1].Description)
+
+	// Temps, nutrition et note viennent du JSON-LD même avec les sélecteurs CSS
+	assert.Equal(t, 15, recipe.PrepTimeMinutes)
+	assert.Equal(t, 25, recipe.CookTimeMinutes)
+	assert.Equal(t, 40, recipe.TotalTimeMinutes)
+	assert.Equal(t, &Nutrition{Calories: "120 kcal", Protein: "3 g"}, recipe.Nutrition)
+	assert.Equal(t, &Rating{Value: 4.6, Count: 212}, recipe.Rating)
+
+	for _, extractor := range []string{extractorJSONLD, extractorAuto} {
+		recipe, extraction := scrapeWithExtractor(t, extractor, bothFormsHTML)
+		require.Len(t, recipe.Ingredients, 3, extractor)
+		assert.Equal(t, "1 l d'eau", recipe.Ingredients[2].Name)
+		require.Len(t, recipe.Instructions, 2)
+		assert.Equal(t, Instruction{Number: "2", Description: "Cuire 25 minutes dans l'eau."}, recipe.Instructions[1])
+		assert.NoError(t, extraction.Err)
+	}
+}
+
+// Test d'une page sans JSON-LD : auto se replie sur les sélecteurs CSS, jsonld la considère vide
+func TestExtractorWithoutJSONLD(t *testing.T) {
+	recipe, extraction := scrapeWithExtractor(t, extractorAuto, recipePageHTML)
+	assert.Len(t, recipe.Ingredients, 2)
+	assert.NoError(t, extraction.Err)
+
+	recipe, extraction = scrapeWithExtractor(t, extractorJSONLD, recipePageHTML)
+	assert.Empty(t, recipe.Ingredients)
+	assert.ErrorIs(t, extraction.Err, errEmptyRecipe)
+}
+
+// Test des formes de recipeInstructions et d'image acceptées
+func TestParseJSONLDRecipe(t *testing.T) {
+	recipe := parseJSONLDRecipe(`[{"@type":"Recipe","image":["https://img.example/a.jpg"],
+		"recipeIngredient":[" sel ",""],"recipeInstructions":"Tout mélanger."}]`)
+	require.NotNil(t, recipe)
+	assert.Equal(t, []Ingredient{{Quantity: "sel", Name: "sel"}}, recipe.Ingredients)
+	assert.Equal(t, []Instruction{{Number: "1", Description: "Tout mélanger."}}, recipe.Instructions)
+	assert.Equal(t, "https://img.example/a.jpg", recipe.Image)
+
+	assert.Equal(t, jsonLDDetails{}, recipe.Details)
+
+	assert.Nil(t, parseJSONLDRecipe(`{"@type":"Article"}`))
+	assert.Nil(t, parseJSONLDRecipe(`{invalide`))
+
+	o, err := parseOptions([]string{"-extractor", "auto"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, extractorAuto, o.Extractor)
+	_, err = parseOptions([]string{"-extractor", "xpath"}, io.Discard)
+	assert.Error(t, err)
+}
+
+// Test des durées ISO 8601, de la nutrition et de la note sous leurs différentes formes
+func TestParseJSONLDRecipeDetails(t *testing.T) {
+	for duration, minutes := range map[string]int{
+		`"PT1H30M"`: 90, `"P0DT0H20M"`: 20, `"PT90M"`: 90, `"P1D"`: 1440, `"PT45S"`: 1, `"pt2h"`: 120,
+		`"20 minutes"`: 0, `""`: 0, `12`: 0,
+	} {
+		assert.Equal(t, minutes, jsonLDMinutes(json.RawMessage(duration)), duration)
+	}
+
+	recipe := parseJSONLDRecipe(`{"@type":"Recipe","totalTime":"PT1H","prepTime":"PT10M",
+		"nutrition":{"calories":250,"fatContent":"12 g","carbohydrateContent":"30 g"},
+		"aggregateRating":{"ratingValue":4,"reviewCount":"8"}}`)
+	require.NotNil(t, recipe)
+	assert.Equal(t, jsonLDDetails{
+		PrepTimeMinutes:  10,
+		TotalTimeMinutes: 60,
+		Nutrition:        &Nutrition{Calories: "250", Fat: "12 g", Carbohydrates: "30 g"},
+		Rating:           &Rating{Value: 4, Count: 8},
+	}, recipe.Details)
+
+	// Nutrition vide et note nulle sont absentes
+	recipe = parseJSONLDRecipe(`{"@type":"Recipe","nutrition":{"@type":"NutritionInformation"},
+		"aggregateRating":{"ratingValue":"0","ratingCount":"0"}}`)
+	require.NotNil(t, recipe)
+	assert.Nil(t, recipe.Details.Nutrition)
+	assert.Nil(t, recipe.Details.Rating)
+}
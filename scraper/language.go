@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageStopwords associe chaque code ISO 639-1 à ses mots outils les plus fréquents
+// Détection volontairement légère : les mots outils suffisent à départager des textes de recettes
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "with", "until", "into", "for", "a", "or", "it", "is", "on", "add", "over", "minutes", "about", "from", "heat"},
+	"fr": {"le", "la", "les", "et", "de", "des", "du", "un", "une", "dans", "avec", "pour", "jusqu'à", "au", "aux", "sur", "en", "ajouter", "feu", "pendant"},
+	"es": {"el", "la", "los", "las", "y", "de", "del", "con", "en", "un", "una", "hasta", "para", "por", "al", "que", "agregar", "minutos", "fuego", "o"},
+	"de": {"der", "die", "das", "und", "mit", "in", "den", "dem", "ein", "eine", "bis", "auf", "für", "von", "zu", "im", "hinzufügen", "minuten", "oder", "ist"},
+	"it": {"il", "lo", "la", "gli", "le", "e", "di", "del", "della", "con", "in", "un", "una", "per", "fino", "al", "nel", "aggiungere", "minuti", "fuoco"},
+	"pt": {"o", "a", "os", "as", "e", "de", "do", "da", "com", "em", "um", "uma", "até", "para", "por", "no", "na", "adicionar", "minutos", "fogo"},
+}
+
+// languageMinHits est le nombre minimal de mots outils reconnus pour retenir une langue
+const languageMinHits = 3
+
+// languageIndex associe chaque mot outil aux langues qui l'utilisent
+var languageIndex = buildLanguageIndex()
+
+// buildLanguageIndex construit l'index inverse de languageStopwords
+func buildLanguageIndex() map[string][]string {
+	index := make(map[string][]string)
+	for language, words := range languageStopwords {
+		for _, word := range words {
+			index[word] = append(index[word], language)
+		}
+	}
+	return index
+}
+
+// detectLanguage retourne le code ISO 639-1 de la langue du texte, vide si elle n'est pas reconnue
+// Chaque mot outil rapporte 1 point, partagé entre les langues qui l'utilisent (ex: "la" en français, espagnol, italien)
+func detectLanguage(text string) string {
+	scores := make(map[string]float64)
+	hits := 0
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		languages := languageIndex[word]
+		if len(languages) == 0 {
+			// Élision française (l'huile, d'eau) : le mot outil est la lettre avant l'apostrophe
+			if prefix, _, found := strings.Cut(word, "'"); found && (prefix == "l" || prefix == "d") {
+				languages = []string{"fr"}
+			}
+		}
+		if len(languages) == 0 {
+			continue
+		}
+		hits++
+		for _, language := range languages {
+			scores[language] += 1 / float64(len(languages))
+		}
+	}
+	if hits < languageMinHits {
+		return ""
+	}
+
+	best, bestScore := "", 0.0
+	for language, score := range scores {
+		// Départage stable en cas d'égalité
+		if score > bestScore || (score == bestScore && language < best) {
+			best, bestScore = language, score
+		}
+	}
+	return best
+}
+
+// detectRecipeLanguage détecte la langue d'une recette à partir de son nom et de ses instructions
+func detectRecipeLanguage(recipe Recipe) string {
+	var text strings.Builder
+	text.WriteString(recipe.Name)
+	for _, instruction := range recipe.Instructions {
+		text.WriteByte(' ')
+		text.WriteString(instruction.Description)
+	}
+	return detectLanguage(text.String())
+}
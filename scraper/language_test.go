@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test de la détection de langue sur des textes de recettes
+func TestDetectLanguage(t *testing.T) {
+	cases := map[string]string{
+		"Preheat the oven. Mix the flour and sugar in a bowl, then bake for 20 minutes until golden.":          "en",
+		"Préchauffer le four. Mélanger la farine et le sucre dans un saladier, puis enfourner jusqu'à dorure.": "fr",
+		"Faire revenir l'oignon dans l'huile d'olive avec une pincée de sel pendant 5 minutes.":                "fr",
+		"Mezclar la harina con el azúcar y hornear hasta que esté dorado, por 20 minutos.":                     "es",
+		"Die Butter mit dem Zucker schaumig rühren und bis zum Kochen auf den Herd stellen.":                   "de",
+		"Soup": "",
+		"":     "",
+	}
+	for text, expected := range cases {
+		assert.Equal(t, expected, detectLanguage(text), text)
+	}
+}
+
+// Test de la détection sur une recette complète (nom et instructions)
+func TestDetectRecipeLanguage(t *testing.T) {
+	english := Recipe{
+		Name: "Easy Chicken Soup",
+		Instructions: []Instruction{
+			{Number: "1", Description: "Bring the broth to a boil in a large pot."},
+			{Number: "2", Description: "Add the chicken and simmer for 30 minutes."},
+		},
+	}
+	french := Recipe{
+		Name: "Soupe de poulet facile",
+		Instructions: []Instruction{
+			{Number: "1", Description: "Porter le bouillon à ébullition dans une grande casserole."},
+			{Number: "2", Description: "Ajouter le poulet et laisser mijoter pendant 30 minutes."},
+		},
+	}
+	assert.Equal(t, "en", detectRecipeLanguage(english))
+	assert.Equal(t, "fr", detectRecipeLanguage(french))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// lastModifiedFilename est le fichier où sont persistés les en-têtes Last-Modified entre deux exécutions
+const lastModifiedFilename = "last_modified.json"
+
+// lastModifiedStore mémorise l'en-tête Last-Modified de chaque page de catégorie
+// Une date reçue n'est retenue qu'une fois toutes les recettes trouvées dans sa catégorie terminées :
+// une catégorie dont des recettes ont échoué est re-parcourue à l'exécution suivante
+// Thread-safe grâce au Mutex pour les accès concurrents des callbacks Colly et des workers
+type lastModifiedStore struct {
+	mu          sync.Mutex
+	path        string                     // Fichier de persistance
+	entries     map[string]string          // URL → valeur de l'en-tête Last-Modified
+	pending     map[string]pendingModified // URL → date reçue pendant l'exécution, pas encore retenue
+	outstanding map[string]int             // Catégorie → recettes trouvées pas encore terminées
+}
+
+// pendingModified est une date Last-Modified reçue pour une page de la catégorie Category
+type pendingModified struct {
+	Value    string
+	Category string
+}
+
+// loadLastModifiedStore charge le store depuis le disque (un fichier absent donne un store vide)
+func loadLastModifiedStore(path string) (*lastModifiedStore, error) {
+	store := &lastModifiedStore{
+		path:        path,
+		entries:     make(map[string]string),
+		pending:     make(map[string]pendingModified),
+		outstanding: make(map[string]int),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &store.entries); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Get retourne la dernière valeur Last-Modified connue pour une URL
+func (s *lastModifiedStore) Get(url string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[url]
+}
+
+// Set note la valeur Last-Modified d'une page de la catégorie category, retenue par Save si la catégorie est terminée
+func (s *lastModifiedStore) Set(url, category, lastModified string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[url] = pendingModified{Value: lastModified, Category: category}
+}
+
+// RecipeQueued compte une recette trouvée dans la catégorie category
+func (s *lastModifiedStore) RecipeQueued(category string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outstanding[category]++
+}
+
+// RecipeCompleted compte une recette de la catégorie category terminée (acceptée ou mise en quarantaine)
+func (s *lastModifiedStore) RecipeCompleted(category string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outstanding[category] > 0 {
+		s.outstanding[category]--
+	}
+}
+
+// trackCompleted décompte les recettes terminées de leur catégorie avant de les transmettre au channel retourné
+func (s *lastModifiedStore) trackCompleted(completed <-chan Recipe) <-chan Recipe {
+	tracked := make(chan Recipe, cap(completed))
+	go func() {
+		defer close(tracked)
+		for recipe := range completed {
+			s.RecipeCompleted(recipe.Category)
+			tracked <- recipe
+		}
+	}()
+	return tracked
+}
+
+// Save persiste le store sur le disque
+// Seules les dates des catégories sans recette en échec, débordée ou non visitée sont retenues
+func (s *lastModifiedStore) Save() error {
+	s.mu.Lock()
+	for url, modified := range s.pending {
+		if s.outstanding[modified.Category] == 0 {
+			s.entries[url] = modified.Value
+			delete(s.pending, url)
+		}
+	}
+	content, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, content, 0644)
+}
+
+// isNotModified indique si l'erreur retournée par Colly correspond à une réponse 304
+func isNotModified(err error) bool {
+	return err != nil && err.Error() == http.StatusText(http.StatusNotModified)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de la persistance du store Last-Modified
+func TestLastModifiedStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lastModifiedFilename)
+
+	// Un fichier absent donne un store vide
+	store, err := loadLastModifiedStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, "", store.Get("https://example.com/a"))
+
+	store.Set("https://example.com/a", "a", "Wed, 21 Oct 2015 07:28:00 GMT")
+	require.NoError(t, store.Save())
+
+	reloaded, err := loadLastModifiedStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", reloaded.Get("https://example.com/a"))
+}
+
+// Test des requêtes conditionnelles contre un serveur renvoyant 304
+func TestConditionalCategoryRequest(t *testing.T) {
+	const lastModifiedValue = "Wed, 21 Oct 2015 07:28:00 GMT"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModifiedValue {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Last-Modified", lastModifiedValue)
+		w.Write([]byte(`<html><body><div class="mntl-taxonomysc-article-list-group">
+			<a class="mntl-card" href="/recipe/1"><span class="card__title-text">Soupe</span></a>
+		</div></body></html>`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), lastModifiedFilename)
+	store, err := loadLastModifiedStore(path)
+	require.NoError(t, err)
+
+	// Première exécution : la page est traitée et sa date mémorisée
+	stats := NewScrapingStats(1)
+	recipeURLs := make(chan RecipeData, 10)
+	collector := createMainCollectorWithPagination(stats, recipeURLs, 1, store, nil)
+	require.NoError(t, collector.Visit(server.URL+"/category"))
+	assert.Len(t, recipeURLs, 1)
+	assert.Equal(t, int64(0), stats.PagesUnchanged)
+	// Sans la recette terminée, la date n'est pas retenue
+	require.NoError(t, store.Save())
+	assert.Equal(t, "", store.Get(server.URL+"/category"))
+	store.RecipeCompleted((<-recipeURLs).Category)
+	require.NoError(t, store.Save())
+
+	// Deuxième exécution : 304, aucune recette re-queuée
+	store, err = loadLastModifiedStore(path)
+	require.NoError(t, err)
+	stats = NewScrapingStats(1)
+	recipeURLs = make(chan RecipeData, 10)
+	collector = createMainCollectorWithPagination(stats, recipeURLs, 1, store, nil)
+	err = collector.Visit(server.URL + "/category")
+	assert.True(t, isNotModified(err))
+	assert.Len(t, recipeURLs, 0)
+	assert.Equal(t, int64(1), stats.PagesUnchanged)
+	assert.Equal(t, int64(0), stats.RecipesFound)
+}
+
+// La date d'une catégorie n'est retenue qu'une fois toutes ses recettes terminées
+func TestLastModifiedStoreWaitsForRecipes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lastModifiedFilename)
+	store, err := loadLastModifiedStore(path)
+	require.NoError(t, err)
+
+	store.Set("https://example.com/soups", "soups", "Wed, 21 Oct 2015 07:28:00 GMT")
+	store.Set("https://example.com/desserts", "desserts", "Thu, 22 Oct 2015 07:28:00 GMT")
+	store.RecipeQueued("soups")
+	store.RecipeQueued("desserts")
+	store.RecipeQueued("desserts")
+
+	// Une recette de desserts a échoué : la catégorie sera re-parcourue
+	completed := make(chan Recipe, 2)
+	completed <- Recipe{Name: "Soupe", Category: "soups"}
+	completed <- Recipe{Name: "Tarte", Category: "desserts"}
+	close(completed)
+	for range store.trackCompleted(completed) {
+	}
+	require.NoError(t, store.Save())
+
+	reloaded, err := loadLastModifiedStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", reloaded.Get("https://example.com/soups"))
+	assert.Equal(t, "", reloaded.Get("https://example.com/desserts"))
+}
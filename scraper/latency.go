@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples borne la mémoire du réservoir de latences
+// Au-delà, les échantillons sont remplacés aléatoirement (reservoir sampling)
+const maxLatencySamples = 10000
+
+// requestTimings mesure la durée réseau des requêtes de tous les collecteurs (comme sharedCookieJar)
+var requestTimings = newTimingTransport(newDecodingTransport(http.DefaultTransport))
+
+// timingTransport mesure chaque requête de l'envoi à la lecture complète du corps de la réponse
+// Colly applique le délai de LimitRule avant OnResponse : une mesure faite dans les handlers l'inclurait
+// La mesure est remise aux destinataires portés par le contexte de la requête (withRequestRecorder)
+type timingTransport struct {
+	base http.RoundTripper
+}
+
+// newTimingTransport crée un transport mesurant les requêtes exécutées par base
+func newTimingTransport(base http.RoundTripper) *timingTransport {
+	return &timingTransport{base: base}
+}
+
+// RoundTrip exécute la requête ; la durée est remise quand le corps est lu ou fermé
+// Chaque saut d'une redirection est une requête mesurée séparément
+func (t *timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+	pageURL := req.URL.String()
+	body := &timedBody{ReadCloser: resp.Body}
+	body.done = func() {
+		reportRequest(ctx, requestTiming{URL: pageURL, Duration: time.Since(start), Size: body.size})
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// timedBody appelle done une seule fois, à la fin de la lecture ou à la fermeture du corps
+type timedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+	size int // Octets lus (décompressés par decodingTransport)
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
+}
+
+// requestTiming est la mesure d'une requête remise par timingTransport
+type requestTiming struct {
+	URL      string
+	Duration time.Duration
+	Size     int
+}
+
+// requestRecorderKey est la clé de contexte des destinataires des mesures de requêtes
+type requestRecorderKey struct{}
+
+// withRequestRecorder ajoute record aux destinataires des mesures des requêtes faites avec ctx
+// Les destinataires déjà présents dans ctx reçoivent toujours les mesures
+func withRequestRecorder(ctx context.Context, record func(requestTiming)) context.Context {
+	previous, _ := ctx.Value(requestRecorderKey{}).(func(requestTiming))
+	return context.WithValue(ctx, requestRecorderKey{}, func(timing requestTiming) {
+		if previous != nil {
+			previous(timing)
+		}
+		record(timing)
+	})
+}
+
+// reportRequest remet la mesure d'une requête aux destinataires de ctx (sans effet s'il n'y en a pas)
+func reportRequest(ctx context.Context, timing requestTiming) {
+	if record, ok := ctx.Value(requestRecorderKey{}).(func(requestTiming)); ok {
+		record(timing)
+	}
+}
+
+// withResponseTimes comptabilise dans stats la durée des requêtes faites avec ctx
+func withResponseTimes(ctx context.Context, stats *ScrapingStats) context.Context {
+	return withRequestRecorder(ctx, func(timing requestTiming) {
+		stats.RecordResponseTime(timing.Duration)
+	})
+}
+
+// recipePhases mesure les phases du traitement d'une recette
+// HTTP : envoi de la requête → lecture du corps ; Parse : analyse de la page (parseRecipePage)
+type recipePhases struct {
+	HTTP  time.Duration
+	Parse time.Duration
+}
+
+// withHTTPDuration demande au Fetcher d'ajouter à d la durée réseau de la page récupérée avec ctx
+// Le délai de politesse des collecteurs, appliqué avant la requête, n'y est pas compté
+func withHTTPDuration(ctx context.Context, d *time.Duration) context.Context {
+	return withRequestRecorder(ctx, func(timing requestTiming) {
+		*d += timing.Duration
+	})
+}
+
+// RecordResponseTime ajoute une durée de réponse au réservoir d'échantillons
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) RecordResponseTime(d time.Duration) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	s.latencySeen++
+	if len(s.latencySamples) < maxLatencySamples {
+		s.latencySamples = append(s.latencySamples, d)
+		return
+	}
+	// Chaque durée observée a la même probabilité d'être conservée
+	if i := rand.Int63n(s.latencySeen); i < maxLatencySamples {
+		s.latencySamples[i] = d
+	}
+}
+
+// percentile retourne le p-ième centile (0 < p <= 1) d'une liste triée, méthode du rang le plus proche
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// computeLatencyPercentiles calcule p50/p90/p99 à partir du réservoir (appelant verrouillé)
+func (s *ScrapingStats) computeLatencyPercentiles() {
+	sorted := make([]time.Duration, len(s.latencySamples))
+	copy(sorted, s.latencySamples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	s.LatencyP50 = percentile(sorted, 0.50)
+	s.LatencyP90 = percentile(sorted, 0.90)
+	s.LatencyP99 = percentile(sorted, 0.99)
+}
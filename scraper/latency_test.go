@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test des centiles sur des durées connues (1ms à 100ms, dans le désordre)
+func TestLatencyPercentiles(t *testing.T) {
+	stats := NewScrapingStats(1)
+	for i := 100; i >= 1; i-- {
+		stats.RecordResponseTime(time.Duration(i) * time.Millisecond)
+	}
+	stats.CalculateFinalStats()
+
+	detailed := stats.GetDetailedStats()
+	assert.Equal(t, 50*time.Millisecond, detailed.LatencyP50)
+	assert.Equal(t, 90*time.Millisecond, detailed.LatencyP90)
+	assert.Equal(t, 99*time.Millisecond, detailed.LatencyP99)
+}
+
+func TestPercentileEdgeCases(t *testing.T) {
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.5))
+
+	single := []time.Duration{7 * time.Millisecond}
+	assert.Equal(t, 7*time.Millisecond, percentile(single, 0.5))
+	assert.Equal(t, 7*time.Millisecond, percentile(single, 0.99))
+}
+
+// Le réservoir reste borné quel que soit le nombre de réponses
+func TestLatencyReservoirBounded(t *testing.T) {
+	stats := NewScrapingStats(1)
+	for i := 0; i < maxLatencySamples+500; i++ {
+		stats.RecordResponseTime(time.Millisecond)
+	}
+	assert.Len(t, stats.latencySamples, maxLatencySamples)
+	assert.Equal(t, int64(maxLatencySamples+500), stats.latencySeen)
+
+	stats.CalculateFinalStats()
+	assert.Equal(t, time.Millisecond, stats.GetDetailedStats().LatencyP99)
+}
+
+// Les collecteurs mesurent le temps entre OnRequest et OnResponse
+func TestCollectorRecordsLatency(t *testing.T) {
+	useTestCookieJar(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	collector := createRecipeCollector(context.Background(), stats)
+	require.NoError(t, collector.Visit(server.URL+"/recipe"))
+
+	// Le délai de politesse du collecteur (2s) n'est pas compté dans la latence
+	stats.CalculateFinalStats()
+	assert.GreaterOrEqual(t, stats.GetDetailedStats().LatencyP50, 50*time.Millisecond)
+	assert.Less(t, stats.GetDetailedStats().LatencyP50, time.Second)
+}
+
+// Chaque saut d'une redirection est remis au contexte de la requête, la page cumule leurs durées
+func TestTimingTransportRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var timings []requestTiming
+	var total time.Duration
+	ctx := withRequestRecorder(withHTTPDuration(context.Background(), &total), func(timing requestTiming) {
+		timings = append(timings, timing)
+	})
+	client := &http.Client{Transport: &contextTransport{ctx: ctx, base: newTimingTransport(http.DefaultTransport)}}
+	resp, err := client.Get(server.URL + "/old")
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, timings, 2)
+	assert.Equal(t, server.URL+"/old", timings[0].URL)
+	assert.Equal(t, server.URL+"/new", timings[1].URL)
+	assert.Equal(t, 2, timings[1].Size)
+	assert.GreaterOrEqual(t, timings[1].Duration, 30*time.Millisecond)
+	assert.Equal(t, timings[0].Duration+timings[1].Duration, total)
+	assert.GreaterOrEqual(t, total, 60*time.Millisecond)
+}
+
+// Une requête sans destinataire dans son contexte n'est pas mesurée
+func TestReportRequestWithoutRecorder(t *testing.T) {
+	assert.NotPanics(t, func() {
+		reportRequest(context.Background(), requestTiming{Duration: time.Second})
+	})
+}
+
+// Les temps HTTP et de parsing d'une recette sont mesurés séparément
+func TestRecipePhaseDurations(t *testing.T) {
+	useTestCookieJar(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	workerStats := &WorkerStats{WorkerID: 1}
+	processRecipeReusable(RecipeData{URL: server.URL + "/recipe", Title: "Soupe"}, newCollyFetcher(stats), stats, completedRecipes, workerStats, nil, nil)
+	require.Len(t, completedRecipes, 1)
+
+	// Le délai de politesse du collecteur (2s) n'est compté ni dans l'une ni dans l'autre phase
+	assert.GreaterOrEqual(t, workerStats.HTTPDuration, 100*time.Millisecond)
+	assert.Less(t, workerStats.HTTPDuration, time.Second)
+	assert.Greater(t, workerStats.ParseDuration, time.Duration(0))
+	assert.Less(t, workerStats.ParseDuration, workerStats.HTTPDuration)
+
+	detailed := stats.GetDetailedStats()
+	assert.Equal(t, workerStats.HTTPDuration, detailed.TotalHTTPDuration)
+	assert.Equal(t, workerStats.ParseDuration, detailed.TotalParseDuration)
+}
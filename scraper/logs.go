@@ -5,9 +5,13 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/maxime-louis14/api-golang/logger"
 )
 
 // Variables globales pour le logging dans un fichier
@@ -17,8 +21,18 @@ var (
 	logInited bool
 )
 
+// redactURI remplace le mot de passe de chaque URI du texte (sitemap, warm-up, erreurs HTTP...) par *** avant qu'il soit loggé
+var redactURI = logger.RedactURI
+
+// requestIDEnv est la variable d'environnement portant le request ID de l'appel API ayant lancé le scraper
+const requestIDEnv = "SCRAPER_REQUEST_ID"
+
+// traceParentEnv porte le contexte de trace W3C du span de l'API ayant lancé le scraper (tracing.TraceParentEnv)
+const traceParentEnv = "TRACEPARENT"
+
 // initLogger initialise le système de logging vers un fichier unique
-func initLogger() error {
+// dir: répertoire du fichier scraper.log, créé si nécessaire (vide = répertoire courant)
+func initLogger(dir string) error {
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
@@ -27,22 +41,39 @@ func initLogger() error {
 	}
 
 	// Nom du fichier de log fixe
-	logFilename := "scraper.log"
+	logFilename := filepath.Join(dir, "scraper.log")
 
 	var err error
+	if dir != "" {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("erreur lors de la création du répertoire de sortie: %v", err)
+		}
+	}
 	// Ouvrir en mode append pour ne pas écraser les logs précédents
 	logFile, err = os.OpenFile(logFilename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return fmt.Errorf("erreur lors de l'ouverture du fichier de log: %v", err)
 	}
 
-	// Écrire à la fois dans le fichier ET dans stdout (pour Docker)
-	log.SetOutput(io.MultiWriter(os.Stdout, logFile))
+	// Écrire à la fois dans le fichier ET dans stderr (pour Docker) : stdout reste réservé aux données (-output -)
+	// Avec -progress, la console est réservée à la ligne de progression : les logs vont uniquement dans le fichier
+	if opts.Progress {
+		log.SetOutput(logFile)
+	} else {
+		log.SetOutput(io.MultiWriter(os.Stderr, logFile))
+	}
 
 	// Ajouter un séparateur pour indiquer le début d'une nouvelle exécution
 	separator := strings.Repeat("=", 80)
 	log.Printf("\n%s\n", separator)
 	log.Printf("🚀 NOUVELLE EXÉCUTION - %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	// Exécution lancée par l'API : le request ID permet de relier ce log à la requête d'origine
+	if requestID := os.Getenv(requestIDEnv); requestID != "" {
+		log.Printf("🔗 Request ID: %s\n", requestID)
+	}
+	if traceParent := os.Getenv(traceParentEnv); traceParent != "" {
+		log.Printf("🧭 Trace: %s\n", traceParent)
+	}
 	log.Printf("%s\n\n", separator)
 
 	logInited = true
@@ -70,7 +101,7 @@ func logInfo(format string, args ...interface{}) {
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
-	log.Printf(format, args...)
+	log.Print(redactURI(fmt.Sprintf(format, args...)))
 }
 
 // logConfig enregistre un message de configuration
@@ -88,6 +119,41 @@ func logResponse(url string, duration time.Duration, size int) {
 	logInfo("✅ Réponse reçue en %v pour %s (Taille: %d bytes)\n", duration, url, size)
 }
 
+// logWarmupRequest enregistre une visite de warm-up
+func logWarmupRequest(url string) {
+	logInfo("🍪 Visite de warm-up pour obtenir les cookies de session: %s\n", url)
+}
+
+// logWarmupError enregistre une erreur de warm-up
+func logWarmupError(url string, err error) {
+	logInfo("⚠️  Erreur lors de la visite de warm-up %s: %v\n", url, err)
+}
+
+// logHeadlessFallback enregistre le passage d'une URL bloquée par le navigateur headless
+func logHeadlessFallback(url string, blocked int) {
+	logInfo("🧭 %d réponses 403 pour %s : récupération par le navigateur headless\n", blocked, url)
+}
+
+// logHeadlessError enregistre l'échec du navigateur headless (la réponse 403 est conservée)
+func logHeadlessError(url string, err error) {
+	logInfo("⚠️  Échec du navigateur headless pour %s: %v\n", url, err)
+}
+
+// logPageUnchanged enregistre une page de catégorie non modifiée (304)
+func logPageUnchanged(url string) {
+	logInfo("♻️  Page inchangée depuis la dernière exécution (304): %s\n", url)
+}
+
+// logSitemapLoaded enregistre le nombre de recettes extraites d'un sitemap
+func logSitemapLoaded(url string, count int) {
+	logInfo("🗺️  Sitemap %s: %d recettes à traiter\n", url, count)
+}
+
+// logSitemapError enregistre l'échec d'un sitemap
+func logSitemapError(url string, err error) {
+	logInfo("❌ Erreur lors de la lecture du sitemap %s: %v\n", url, err)
+}
+
 // logRecipeFound enregistre une recette trouvée
 func logRecipeFound(recipeNum int64, title string) {
 	logInfo("📝 Recette #%d ajoutée à la queue: '%s'\n", recipeNum, title)
@@ -98,6 +164,143 @@ func logRecipeQueueFull(title string) {
 	logInfo("⚠️  Channel plein, recette ignorée: '%s'\n", title)
 }
 
+// logDuplicateRecipe enregistre une recette ignorée car déjà collectée sous le même identifiant
+func logDuplicateRecipe(title, page string) {
+	logInfo("🔁 Recette déjà collectée, doublon ignoré: '%s' (%s)\n", title, page)
+}
+
+// logRecipeSpilled enregistre une recette mise de côté dans le fichier de débordement
+func logRecipeSpilled(title string) {
+	logInfo("💾 Channel plein, recette mise de côté dans %s: '%s'\n", spilloverFilename, title)
+}
+
+// logSpilloverError enregistre l'échec d'écriture d'une recette dans le fichier de débordement
+func logSpilloverError(title string, err error) {
+	logInfo("❌ Channel plein et écriture dans %s impossible, recette ignorée: '%s' (%v)\n", spilloverFilename, title, err)
+}
+
+// logConfigEnvUnset signale une variable d'environnement référencée par la configuration mais absente
+func logConfigEnvUnset(path, name string) {
+	logInfo("⚠️  %s: variable d'environnement %s non définie, remplacée par une chaîne vide\n", path, name)
+}
+
+// logConfigReloaded signale la prise en compte d'une configuration modifiée (-watch-config)
+func logConfigReloaded(path string, categories int) {
+	logInfo("🔁 Configuration %s rechargée: %d catégories\n", path, categories)
+}
+
+// logConfigReloadError signale une configuration modifiée invalide, la précédente reste active
+func logConfigReloadError(err error) {
+	logInfo("⚠️  Configuration invalide, la configuration précédente est conservée: %v\n", err)
+}
+
+// logScheduleStart signale le démarrage du mode planifié (-schedule)
+func logScheduleStart(spec string, next time.Time) {
+	logInfo("⏰ Mode planifié (%s): première exécution le %s\n", spec, next.Format("2006-01-02 15:04:05"))
+}
+
+// logScheduleRunStart signale le début d'une exécution planifiée
+func logScheduleRunStart() {
+	logInfo("⏰ Début de l'exécution planifiée\n")
+}
+
+// logScheduleNextRun indique la prochaine exécution planifiée
+func logScheduleNextRun(next time.Time) {
+	logInfo("⏰ Prochaine exécution le %s\n", next.Format("2006-01-02 15:04:05"))
+}
+
+// logScheduleSkipped signale une échéance ignorée car l'exécution précédente n'est pas terminée
+func logScheduleSkipped(next time.Time) {
+	logInfo("⏭️  Exécution précédente encore en cours: échéance ignorée, prochaine le %s\n", next.Format("2006-01-02 15:04:05"))
+}
+
+// logScheduleStop signale l'arrêt du mode planifié
+func logScheduleStop() {
+	logInfo("🛑 Arrêt demandé: fin du mode planifié après l'exécution en cours\n")
+}
+
+// logDebugHTMLSaved signale le HTML d'une recette en échec sauvegardé (-debug-html)
+func logDebugHTMLSaved(url, path string) {
+	logInfo("🐛 HTML de %s sauvegardé dans %s\n", url, path)
+}
+
+// logDebugHTMLError signale une page en échec qui n'a pas pu être sauvegardée
+func logDebugHTMLError(url string, err error) {
+	logInfo("⚠️  Impossible de sauvegarder le HTML de %s: %v\n", url, err)
+}
+
+// logDebugHTMLLimit signale que la limite de pages sauvegardées est atteinte
+func logDebugHTMLLimit(max int, dir string) {
+	logInfo("⚠️  %d pages déjà sauvegardées dans %s, les suivantes sont ignorées\n", max, dir)
+}
+
+// logEmptyRecipeRetry signale une page de recette sans ingrédient retéléchargée
+func logEmptyRecipeRetry(url string, attempt, max int) {
+	logInfo("🔁 Aucun ingrédient extrait de %s: nouvelle tentative %d/%d\n", url, attempt, max)
+}
+
+// logBudgetExhausted signale que le budget de requêtes est consommé (une seule fois par exécution)
+func logBudgetExhausted(max int64) {
+	logInfo("🧮 Budget de %d requêtes atteint: plus aucune nouvelle requête, finalisation en cours\n", max)
+}
+
+// logBudgetSummary indique combien de recettes n'ont pas été visitées faute de budget
+func logBudgetSummary(max, skipped int64) {
+	logInfo("🧮 Budget de %d requêtes consommé: %d recettes trouvées non visitées\n", max, skipped)
+}
+
+// logSpilloverSummary indique combien de recettes sont à reprendre avec -resume
+func logSpilloverSummary(count int, path string) {
+	logInfo("💾 %d recette(s) mise(s) de côté dans %s, relancer avec -resume pour les traiter\n", count, path)
+}
+
+// logStatusError enregistre un échec d'écriture de status.json (la progression n'est plus visible depuis l'API)
+func logStatusError(path string, err error) {
+	logInfo("⚠️  Impossible d'écrire %s: %v\n", path, err)
+}
+
+// logFailedRecipesSaved indique où trouver les recettes en échec
+func logFailedRecipesSaved(count int, path string) {
+	logInfo("📋 %d recette(s) en échec listée(s) dans %s\n", count, path)
+}
+
+// logRecipeInvalid indique qu'une recette est écartée de la sortie faute de champs obligatoires
+func logRecipeInvalid(recipeName string, missing []string) {
+	logInfo("🚫 Recette incomplète mise en quarantaine: %s (manquant: %s)\n", recipeName, strings.Join(missing, ", "))
+}
+
+// logInvalidRecipesSaved indique combien de recettes incomplètes ont été écrites dans invalid.json
+func logInvalidRecipesSaved(count int, path string) {
+	logInfo("🚫 %d recette(s) incomplète(s) mise(s) en quarantaine dans %s\n", count, path)
+}
+
+// logInvalidRecipesSaveError enregistre l'échec de l'écriture de invalid.json
+func logInvalidRecipesSaveError(path string, err error) {
+	logInfo("⚠️  Impossible de sauvegarder %s: %v\n", path, err)
+}
+
+// logResume enregistre la reprise des recettes d'un fichier de débordement
+func logResume(count int, path string) {
+	logInfo("♻️  Reprise de %d recette(s) depuis %s\n", count, path)
+}
+
+// logAdaptiveDelay enregistre l'augmentation du délai entre requêtes après un blocage
+func logAdaptiveDelay(statusCode int, previous, delay time.Duration) {
+	logInfo("🐢 Statut %d: délai entre requêtes augmenté de %v à %v\n", statusCode, previous, delay)
+}
+
+// logCircuitState enregistre un changement d'état du disjoncteur
+func logCircuitState(from, to string, cooldown time.Duration) {
+	switch to {
+	case circuitOpen:
+		logInfo("🛑 Trop de blocages: circuit ouvert, requêtes suspendues pendant %v\n", cooldown)
+	case circuitHalfOpen:
+		logInfo("🔌 Fin de la pause: circuit semi-ouvert, requête de test\n")
+	default:
+		logInfo("✅ Circuit refermé (%s → %s), reprise des requêtes\n", from, to)
+	}
+}
+
 // logPagination enregistre une page de pagination
 func logPagination(category string, pageNum, maxPages int, url string) {
 	logInfo("📄 Page suivante trouvée pour %s (page %d/%d): %s\n", category, pageNum, maxPages, url)
@@ -145,13 +348,14 @@ func logWorkerSteps() {
 
 // logWorkerHTTPComplete enregistre la fin de la requête HTTP
 func logWorkerHTTPComplete(duration time.Duration) {
-	logInfo("   ✅ Requête HTTP terminée en %v (délai inclus)\n", duration)
+	logInfo("   ✅ Requête HTTP terminée en %v\n", duration)
 }
 
 // logWorkerComplete enregistre la fin du traitement d'un worker
-func logWorkerComplete(workerID int, totalDuration, httpDuration time.Duration, recipeTitle string) {
-	logInfo("⏱️  Worker #%d terminé en %v (HTTP: %v, Parsing: %v): %s\n",
-		workerID, totalDuration, httpDuration, totalDuration-httpDuration, recipeTitle)
+// Le reste du temps total correspond aux délais de politesse du collecteur
+func logWorkerComplete(workerID int, totalDuration, httpDuration, parseDuration time.Duration, recipeTitle string) {
+	logInfo("⏱️  Worker #%d terminé en %v (HTTP: %v, Parsing: %v, Attente: %v): %s\n",
+		workerID, totalDuration, httpDuration, parseDuration, totalDuration-httpDuration-parseDuration, recipeTitle)
 }
 
 // logWorkerError enregistre une erreur de worker
@@ -159,6 +363,11 @@ func logWorkerError(workerID int, recipeTitle string, err error) {
 	logInfo("❌ Worker #%d - Erreur lors de la visite de la page de recette '%s': %v\n", workerID, recipeTitle, err)
 }
 
+// logWorkerStuck enregistre un worker bloqué détecté par le superviseur
+func logWorkerStuck(workerID int, url string, elapsed time.Duration) {
+	logInfo("⚠️  Worker #%d bloqué depuis %v sur %s - annulation de la requête\n", workerID, elapsed.Round(time.Second), url)
+}
+
 // logWorkerQueue enregistre la taille de la queue
 func logWorkerQueue(workerID int, queueLength int) {
 	if queueLength > 0 {
@@ -166,6 +375,23 @@ func logWorkerQueue(workerID int, queueLength int) {
 	}
 }
 
+// logWorkerSizing enregistre le détail du dimensionnement du pool (-debug-workers)
+func logWorkerSizing(sizing workerSizing) {
+	logInfo("🔧 Dimensionnement des workers: %d CPU logiques, %d cœurs physiques détectés, ratio %d, heuristique %d → %d workers\n",
+		sizing.LogicalCPU, sizing.PhysicalCores, sizing.AdaptiveRatio, sizing.Calculated, sizing.Workers)
+	if sizing.Corrected {
+		logInfo("⚠️  Détection des cœurs physiques incohérente, nombre de CPU logiques utilisé\n")
+	}
+	if sizing.Override {
+		logInfo("🔧 SCRAPER_WORKERS défini: heuristique ignorée\n")
+	}
+}
+
+// logWorkerOverrideError enregistre une valeur SCRAPER_WORKERS ignorée
+func logWorkerOverrideError(message string) {
+	logInfo("⚠️  %s, heuristique utilisée\n", message)
+}
+
 // logWorkerInit enregistre l'initialisation des workers
 func logWorkerInit(count int) {
 	logInfo("🏭 Initialisation de %d workers pour le traitement des recettes\n", count)
@@ -266,11 +492,31 @@ func logSaveStart(count int, filename string) {
 	logInfo("💾 Sauvegarde de %d recettes dans %s...\n", count, filename)
 }
 
+// logOutputUnchanged signale une exécution -if-modified-since sans page modifiée : la sortie précédente est conservée
+func logOutputUnchanged(filename string) {
+	logInfo("♻️  Toutes les pages de catégories sont inchangées (304): sortie précédente conservée (%s)\n", filename)
+}
+
+// logOutputsPruned liste les fichiers de sortie horodatés supprimés par -keep-last / -keep-days
+func logOutputsPruned(removed []string) {
+	logInfo("🧹 %d ancien(s) fichier(s) de sortie supprimé(s): %s\n", len(removed), strings.Join(removed, ", "))
+}
+
+// logPruneError enregistre l'échec de suppression d'anciens fichiers de sortie (les autres sont supprimés)
+func logPruneError(err error) {
+	logInfo("⚠️  Nettoyage des anciens fichiers de sortie incomplet: %v\n", err)
+}
+
 // logSaveComplete enregistre la fin de la sauvegarde
 func logSaveComplete(duration time.Duration) {
 	logInfo("✅ Sauvegarde terminée en %v\n", duration)
 }
 
+// logStreamError enregistre l'échec de l'écriture des recettes sur stdout (ex: lecteur du pipe fermé)
+func logStreamError(err error) {
+	logInfo("❌ Écriture des recettes sur stdout interrompue: %v\n", err)
+}
+
 // logSaveError enregistre une erreur de sauvegarde
 func logSaveError(err error) {
 	logInfo("Erreur lors de l'enregistrement des recettes: %v\n", err)
@@ -294,22 +540,83 @@ func logDetailedStatsPerformance(totalDuration time.Duration, requestsPerSec, re
 }
 
 // logDetailedStatsRequests enregistre les statistiques de requêtes
-func logDetailedStatsRequests(total, mainPage, recipe int64) {
+func logDetailedStatsRequests(total, mainPage, recipe, unchanged int64) {
 	logInfo("\n🌐 REQUÊTES:\n")
 	logInfo("   Total: %d\n", total)
 	logInfo("   Page principale: %d\n", mainPage)
 	logInfo("   Pages recettes: %d\n", recipe)
+	logInfo("   Pages inchangées (304): %d\n", unchanged)
+}
+
+// logDetailedStatsStatusCodes enregistre la répartition des réponses par code HTTP, triée par code
+func logDetailedStatsStatusCodes(statusCodes map[int]int64) {
+	if len(statusCodes) == 0 {
+		return
+	}
+	codes := make([]int, 0, len(statusCodes))
+	for code := range statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	logInfo("\n📶 CODES HTTP:\n")
+	for _, code := range codes {
+		logInfo("   %d: %d\n", code, statusCodes[code])
+	}
+}
+
+// logDetailedStatsLatency enregistre les centiles de temps de réponse (rien si aucune réponse mesurée)
+func logDetailedStatsLatency(p50, p90, p99 time.Duration) {
+	if p99 == 0 {
+		return
+	}
+	logInfo("\n⏳ TEMPS DE RÉPONSE:\n")
+	logInfo("   p50: %v\n", p50.Round(time.Millisecond))
+	logInfo("   p90: %v\n", p90.Round(time.Millisecond))
+	logInfo("   p99: %v\n", p99.Round(time.Millisecond))
+}
+
+// logDetailedStatsFailures enregistre la répartition des recettes en échec par catégorie
+func logDetailedStatsFailures(httpErrors, timeouts, empty, parse int64) {
+	if httpErrors+timeouts+empty+parse == 0 {
+		return
+	}
+	logInfo("\n🩺 ÉCHECS PAR CATÉGORIE:\n")
+	logInfo("   Erreurs HTTP: %d\n", httpErrors)
+	logInfo("   Timeouts: %d\n", timeouts)
+	logInfo("   Pages vides: %d\n", empty)
+	logInfo("   Pages illisibles: %d\n", parse)
+}
+
+// logDetailedStatsRuntime enregistre les pics de mémoire et de goroutines
+func logDetailedStatsRuntime(peakHeapBytes uint64, peakGoroutines int) {
+	logInfo("\n🧠 RESSOURCES:\n")
+	logInfo("   Pic mémoire (tas): %.1f Mo\n", float64(peakHeapBytes)/(1<<20))
+	logInfo("   Pic de goroutines: %d\n", peakGoroutines)
 }
 
 // logDetailedStatsRecipes enregistre les statistiques de recettes
-func logDetailedStatsRecipes(found, completed, failed int64, successRate float64) {
+func logDetailedStatsRecipes(found, completed, failed, invalid int64, successRate float64) {
 	logInfo("\n📝 RECETTES:\n")
 	logInfo("   Trouvées: %d\n", found)
 	logInfo("   Complétées: %d\n", completed)
 	logInfo("   Échouées: %d\n", failed)
+	logInfo("   Incomplètes (quarantaine): %d\n", invalid)
 	logInfo("   Taux de succès: %.1f%%\n", successRate)
 }
 
+// logDetailedStatsPhases enregistre la répartition du temps des recettes entre HTTP et parsing
+func logDetailedStatsPhases(httpDuration, parseDuration time.Duration, completed int64) {
+	logInfo("\n🔬 RÉPARTITION DU TEMPS (recettes):\n")
+	logInfo("   HTTP: %v\n", httpDuration.Round(time.Millisecond))
+	logInfo("   Parsing: %v\n", parseDuration.Round(time.Millisecond))
+	if completed > 0 {
+		logInfo("   Moyenne par recette: HTTP %v, parsing %v\n",
+			(httpDuration / time.Duration(completed)).Round(time.Microsecond),
+			(parseDuration / time.Duration(completed)).Round(time.Microsecond))
+	}
+}
+
 // logDetailedStatsConfig enregistre la configuration automatique
 func logDetailedStatsConfig(logicalCPU, physicalCores, adaptiveRatio, calculatedWorkers, finalWorkers int) {
 	logInfo("\n💻 CONFIGURATION AUTOMATIQUE:\n")
@@ -330,16 +637,25 @@ func logDetailedStatsWorkersHeader() {
 	logInfo("\n📈 PERFORMANCE PAR WORKER:\n")
 }
 
-// logDetailedStatsAnalysis enregistre l'analyse de performance
+// logDetailedStatsAnalysis enregistre l'analyse de performance (avgRequestsPerRecipe négatif : aucune recette complétée)
 func logDetailedStatsAnalysis(avgRequestsPerRecipe, requestsPerSec float64, avgTimePerRecipe float64) {
 	logInfo("\n💡 ANALYSE DE PERFORMANCE:\n")
-	logInfo("   Requêtes moyennes par recette: %.1f\n", avgRequestsPerRecipe)
+	if avgRequestsPerRecipe >= 0 {
+		logInfo("   Requêtes moyennes par recette: %.1f\n", avgRequestsPerRecipe)
+	} else {
+		logInfo("   Requêtes moyennes par recette: n/a\n")
+	}
 	logInfo("   Débit estimé: %.0f requêtes/seconde\n", requestsPerSec)
 	if avgTimePerRecipe > 0 {
 		logInfo("   Temps moyen par recette: %.2f secondes\n", avgTimePerRecipe)
 	}
 }
 
+// logSuccessRateTooLow enregistre un taux de succès inférieur au seuil -min-success-rate
+func logSuccessRateTooLow(rate, minRate float64) {
+	logInfo("🚨 Taux de succès %.1f%% inférieur au seuil de %.1f%% : sélecteurs probablement cassés\n", rate*100, minRate*100)
+}
+
 // logDetailedStatsFooter enregistre le pied de page des statistiques
 func logDetailedStatsFooter(filename string) {
 	logInfo("\n💾 Fichier de sortie: %s\n", filename)
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// Options regroupe les options de ligne de commande du scraper
+type Options struct {
+	Version         bool   // Afficher les informations de build et quitter
+	JSON            bool   // Avec -version : sortie au format JSON
+	Selftest        string // URL d'une recette dont les champs sont vérifiés avant de quitter (vide = scraping normal)
+	RecipeURL       string // URL d'une recette à scraper seule et écrire en JSON sur stdout (vide = scraping normal)
+	CategoryURL     string // URL d'une catégorie à parcourir seule, recettes écrites en NDJSON sur stdout (vide = scraping normal)
+	CategoryPages   int    // Avec -category : nombre maximum de pages de la catégorie parcourues, première comprise
+	IfModifiedSince bool   // Envoyer If-Modified-Since sur les pages de catégories et ignorer les réponses 304
+	Resume          bool   // Traiter d'abord les recettes de spillover.jsonl laissées par l'exécution précédente
+	ConfigPath      string // Fichier de configuration JSON, YAML ou TOML (catégories), absent = catégories par défaut
+	WatchConfig     bool   // Relire le fichier de configuration avant chaque exécution
+	Schedule        string // Planification cron ou @every (vide = une seule exécution)
+	DetectLanguage  bool   // Détecter la langue de chaque recette (nom et instructions)
+
+	WarmupURLs  []string      // Pages visitées avant le scraping pour obtenir les cookies de session (vide = pas de warm-up, défaut)
+	WarmupDelay time.Duration // Pause après le warm-up (sans effet sans WarmupURLs)
+
+	StuckThreshold time.Duration // Durée au-delà de laquelle un worker occupé est considéré bloqué
+	RequestTimeout time.Duration // Timeout HTTP de chaque requête, appliqué à tous les collecteurs
+	HTTP2          bool          // Négocier HTTP/2 avec les serveurs TLS (désactivable pour certains anti-bots)
+	TLSMin         uint16        // Version TLS minimale des requêtes (0 = défaut de Go)
+
+	DebugWorkers     bool   // Logger le détail du dimensionnement du pool de workers
+	ConcurrencyModel string // Traitement des recettes : pool (workers fixes) ou semaphore (une goroutine par recette)
+
+	OutputDir string // Répertoire de data.json, stats.json et scraper.log (vide = répertoire courant)
+	Compact   bool   // data.json compact : une recette par ligne au lieu du JSON indenté
+	Output    string // Fichier des recettes dans OutputDir, "-" = stdout
+	Format    string // Format des recettes : json (tableau) ou ndjson (une recette par ligne)
+	KeepLast  int    // Fichiers de sortie horodatés gardés après une sauvegarde, les plus récents (0 = tous)
+	KeepDays  int    // Âge maximal en jours des fichiers de sortie horodatés gardés (0 = pas de limite)
+
+	URLBuffer    int // Capacité du channel des URLs de recettes (collecteurs → workers)
+	RecipeBuffer int // Capacité du channel des recettes terminées (workers → agrégation)
+
+	MinSuccessRate float64 // Taux de succès minimal (0-1) en dessous duquel le scraper sort en erreur (0 = désactivé)
+	MaxRequests    int64   // Nombre maximal de requêtes de l'exécution, toutes pages confondues (0 = illimité)
+	Extractor      string  // Source des ingrédients et instructions : css, jsonld ou auto (JSON-LD puis CSS)
+	DebugHTML      bool    // Sauvegarder le HTML des recettes vides ou en erreur dans debug/ (dans -output-dir)
+	EmptyRetries   int     // Nouvelles tentatives sur une page de recette sans ingrédient avant de la compter en échec
+
+	Progress bool // Ligne de progression sur stderr (les logs ne sont alors écrits que dans scraper.log)
+
+	Locale    string // Locale des requêtes (Accept-Language et Referer), vide = en-US historique
+	NoReferer bool   // Ne pas envoyer de Referer simulé
+
+	Headers []customHeader // En-têtes ajoutés à chaque requête (-header, répétable)
+	Cookies []*http.Cookie // Cookies envoyés au site scrapé via le cookie jar (-cookie, répétable)
+
+	Sitemap        string         // URL d'un sitemap à la place du parcours des catégories (vide = catégories)
+	SitemapPattern *regexp.Regexp // Filtre des URLs de recettes extraites du sitemap
+
+	Headless        bool   // Récupérer par un navigateur headless les pages bloquées par un challenge JavaScript
+	HeadlessBrowser string // Navigateur lancé par -headless (Chrome ou Chromium)
+	HeadlessAfter   int    // Nombre de 403 sur une même URL avant de passer par le navigateur
+}
+
+// customHeader est un en-tête HTTP fourni par -header "Nom: Valeur"
+type customHeader struct {
+	Name  string
+	Value string
+}
+
+// headerNamePattern valide un nom d'en-tête HTTP (token RFC 7230)
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// parseHeaderFlag analyse une valeur "Nom: Valeur"
+func parseHeaderFlag(value string) (customHeader, error) {
+	name, headerValue, found := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !found || !headerNamePattern.MatchString(name) {
+		return customHeader{}, fmt.Errorf("en-tête invalide %q: format \"Nom: Valeur\" attendu", value)
+	}
+	headerValue = strings.TrimSpace(headerValue)
+	if strings.ContainsAny(headerValue, "\r\n") {
+		return customHeader{}, fmt.Errorf("en-tête invalide %q: retour à la ligne interdit", value)
+	}
+	return customHeader{Name: http.CanonicalHeaderKey(name), Value: headerValue}, nil
+}
+
+// parseCookieFlag analyse une valeur "nom=valeur"
+func parseCookieFlag(value string) (*http.Cookie, error) {
+	name, cookieValue, found := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	if !found || !headerNamePattern.MatchString(name) || strings.ContainsAny(cookieValue, ";\r\n") {
+		return nil, fmt.Errorf("cookie invalide %q: format \"nom=valeur\" attendu", value)
+	}
+	return &http.Cookie{Name: name, Value: strings.TrimSpace(cookieValue)}, nil
+}
+
+// parseBufferSize valide une capacité de channel (entier strictement positif)
+func parseBufferSize(value string, size *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("taille de file invalide %q: entier positif attendu", value)
+	}
+	*size = n
+	return nil
+}
+
+// parseHeadlessAfter valide la valeur de -headless-after (entier strictement positif)
+func parseHeadlessAfter(value string, after *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("nombre de 403 invalide %q: entier positif attendu", value)
+	}
+	*after = n
+	return nil
+}
+
+// parseRetention valide -keep-last et -keep-days : entier positif ou nul (0 = limite désactivée)
+func parseRetention(value string, keep *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("rétention invalide %q: entier positif ou nul attendu", value)
+	}
+	*keep = n
+	return nil
+}
+
+// parseCategoryURL valide l'URL de -category (URL http(s) absolue)
+func parseCategoryURL(value string) (string, error) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("catégorie invalide %q: URL http(s) absolue attendue", value)
+	}
+	return value, nil
+}
+
+// parseCategoryPages valide la valeur de -category-pages (1 à scraperspec.MaxCategoryPages)
+func parseCategoryPages(value string, pages *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > scraperspec.MaxCategoryPages {
+		return fmt.Errorf("nombre de pages invalide %q: entier entre 1 et %d attendu", value, scraperspec.MaxCategoryPages)
+	}
+	*pages = n
+	return nil
+}
+
+// opts contient les options actives pour l'exécution courante
+var opts = defaultOptions()
+
+// defaultOptions retourne les options par défaut (comportement historique du scraper)
+func defaultOptions() Options {
+	return Options{
+		ConfigPath:       defaultConfigFilename,
+		Output:           "data.json",
+		Format:           formatJSON,
+		WarmupDelay:      2 * time.Second,
+		StuckThreshold:   90 * time.Second,
+		Extractor:        extractorCSS,
+		ConcurrencyModel: concurrencyPool,
+		EmptyRetries:     2,
+		URLBuffer:        2000,
+		CategoryPages:    3,
+		RecipeBuffer:     2000,
+		RequestTimeout:   30 * time.Second,
+		HTTP2:            true,
+		SitemapPattern:   regexp.MustCompile(`/recipe/`),
+		HeadlessBrowser:  defaultHeadlessBrowser,
+		HeadlessAfter:    defaultHeadlessAfter,
+	}
+}
+
+// splitList découpe une liste séparée par des virgules en ignorant les entrées vides
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseOptions analyse les arguments de la ligne de commande
+func parseOptions(args []string, output io.Writer) (Options, error) {
+	o := defaultOptions()
+
+	fs := flag.NewFlagSet("scraper", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.BoolVar(&o.Version, "version", o.Version, "afficher les informations de build et quitter")
+	fs.BoolVar(&o.JSON, "json", o.JSON, "avec -version, afficher les informations de build en JSON")
+	fs.StringVar(&o.Selftest, "selftest", o.Selftest,
+		"scraper une seule recette, afficher un rapport PASS/FAIL par champ et quitter (code 1 en cas d'échec)")
+	fs.StringVar(&o.RecipeURL, "recipe", o.RecipeURL,
+		"scraper une seule recette, l'écrire en JSON sur stdout et quitter (utilisé par POST /scraper/diff)")
+	fs.Func("category", "parcourir une seule catégorie, écrire ses recettes en NDJSON sur stdout et quitter (utilisé par POST /scraper/category)", func(value string) error {
+		categoryURL, err := parseCategoryURL(value)
+		if err != nil {
+			return err
+		}
+		o.CategoryURL = categoryURL
+		return nil
+	})
+	fs.Func("category-pages", "avec -category, nombre maximum de pages parcourues, première comprise (défaut 3, max 20)", func(value string) error {
+		return parseCategoryPages(value, &o.CategoryPages)
+	})
+	fs.BoolVar(&o.IfModifiedSince, "if-modified-since", o.IfModifiedSince,
+		"requêtes conditionnelles sur les catégories (ignore les pages non modifiées depuis la dernière exécution, dates dans last_modified.json de -output-dir)")
+	fs.StringVar(&o.ConfigPath, "config", o.ConfigPath,
+		"fichier de configuration listant les catégories : .json, .yaml/.yml ou .toml (catégories par défaut s'il est absent)")
+	fs.Func("schedule", "rester actif et lancer une exécution selon une planification cron (\"0 */6 * * *\") ou \"@every 6h\"", func(value string) error {
+		if _, err := parseSchedule(value); err != nil {
+			return fmt.Errorf("planification invalide %q: %v", value, err)
+		}
+		o.Schedule = value
+		return nil
+	})
+	fs.BoolVar(&o.WatchConfig, "watch-config", o.WatchConfig,
+		"relire le fichier de configuration avant chaque exécution (configuration précédente conservée s'il est invalide)")
+	fs.BoolVar(&o.DetectLanguage, "detect-language", o.DetectLanguage,
+		"détecter la langue de chaque recette (champ language, code ISO 639-1)")
+	fs.BoolVar(&o.Resume, "resume", o.Resume,
+		"traiter d'abord les recettes mises de côté dans spillover.jsonl quand la file était pleine")
+	fs.Func("warmup", "pages visitées avant le scraping pour obtenir les cookies de session, séparées par des virgules (ex: https://www.allrecipes.com/, désactivé par défaut)", func(value string) error {
+		o.WarmupURLs = splitList(value)
+		return nil
+	})
+	fs.DurationVar(&o.WarmupDelay, "warmup-delay", o.WarmupDelay, "pause après les visites de warm-up (-warmup)")
+	fs.DurationVar(&o.StuckThreshold, "stuck-threshold", o.StuckThreshold,
+		"durée après laquelle un worker bloqué est signalé et sa requête annulée")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", o.RequestTimeout, "timeout HTTP de chaque requête")
+	fs.BoolVar(&o.HTTP2, "http2", o.HTTP2,
+		"négocier HTTP/2 avec les serveurs TLS (-http2=false force HTTP/1.1, repéré par certains anti-bots)")
+	fs.Func("tls-min", "version TLS minimale des requêtes : 1.0, 1.1, 1.2 ou 1.3 (défaut de Go sinon)", func(value string) error {
+		return parseTLSVersion(value, &o.TLSMin)
+	})
+	fs.BoolVar(&o.DebugWorkers, "debug-workers", o.DebugWorkers,
+		"logger le calcul du nombre de workers (CPU logiques, cœurs physiques, ratio, résultat)")
+	fs.Func("concurrency-model", "traitement des recettes : pool (défaut, workers fixes) ou semaphore (une goroutine par recette, même limite)", func(value string) error {
+		model, err := parseConcurrencyModel(value)
+		if err != nil {
+			return err
+		}
+		o.ConcurrencyModel = model
+		return nil
+	})
+
+	fs.StringVar(&o.OutputDir, "output-dir", o.OutputDir,
+		"répertoire de sortie pour data.json, stats.json et scraper.log (créé si nécessaire)")
+	fs.BoolVar(&o.Compact, "compact", o.Compact, "écrire data.json en JSON compact, une recette par ligne")
+	fs.StringVar(&o.Output, "output", o.Output,
+		"fichier des recettes dans le répertoire de sortie ({timestamp} remplacé par l'horodatage de la sauvegarde, ex: data-{timestamp}.json), - pour les écrire sur stdout")
+	fs.Func("format", "format des recettes : json (tableau, défaut) ou ndjson (une recette par ligne)", func(value string) error {
+		format, err := parseFormat(value)
+		if err != nil {
+			return err
+		}
+		o.Format = format
+		return nil
+	})
+	fs.Func("keep-last", "après la sauvegarde, ne garder que les N fichiers de sortie horodatés les plus récents (0 = tous)", func(value string) error {
+		return parseRetention(value, &o.KeepLast)
+	})
+	fs.Func("keep-days", "après la sauvegarde, supprimer les fichiers de sortie horodatés de plus de D jours (0 = aucun)", func(value string) error {
+		return parseRetention(value, &o.KeepDays)
+	})
+	fs.Func("url-buffer", "capacité de la file des URLs de recettes (défaut 2000, réduire pour limiter la mémoire)", func(value string) error {
+		return parseBufferSize(value, &o.URLBuffer)
+	})
+	fs.Func("recipe-buffer", "capacité de la file des recettes terminées (défaut 2000)", func(value string) error {
+		return parseBufferSize(value, &o.RecipeBuffer)
+	})
+	fs.Func("min-success-rate", "taux de succès minimal entre 0 et 1 (ex: 0.8), sortie en erreur en dessous (0 = désactivé)", func(value string) error {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return fmt.Errorf("taux invalide %q: nombre entre 0 et 1 attendu", value)
+		}
+		o.MinSuccessRate = rate
+		return nil
+	})
+	fs.Func("max-requests", "nombre maximal de requêtes de l'exécution (catégories, pagination, recettes), 0 = illimité", func(value string) error {
+		max, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || max < 0 {
+			return fmt.Errorf("budget invalide %q: entier positif ou nul attendu", value)
+		}
+		o.MaxRequests = max
+		return nil
+	})
+	fs.Func("extractor", "extraction des recettes : css (défaut), jsonld ou auto (JSON-LD puis sélecteurs CSS) ; temps, nutrition et note viennent toujours du JSON-LD", func(value string) error {
+		extractor, err := parseExtractor(value)
+		if err != nil {
+			return err
+		}
+		o.Extractor = extractor
+		return nil
+	})
+	fs.BoolVar(&o.DebugHTML, "debug-html", o.DebugHTML,
+		"sauvegarder le HTML des recettes vides ou en erreur dans debug/<slug>.html (100 pages maximum)")
+	fs.Func("empty-retries", "nouvelles tentatives sur une recette sans ingrédient avant de la compter en échec (défaut 2)", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("nombre de tentatives invalide %q: entier positif ou nul attendu", value)
+		}
+		o.EmptyRetries = n
+		return nil
+	})
+	fs.BoolVar(&o.Progress, "progress", o.Progress,
+		"afficher une ligne de progression avec estimation du temps restant (logs uniquement dans scraper.log)")
+
+	fs.StringVar(&o.Locale, "locale", o.Locale,
+		"locale des requêtes, ex: fr-FR (en-tête Accept-Language et Referer Google du pays)")
+	fs.BoolVar(&o.NoReferer, "no-referer", o.NoReferer, "ne pas envoyer d'en-tête Referer simulé")
+	fs.Func("header", "en-tête \"Nom: Valeur\" ajouté à chaque requête (répétable)", func(value string) error {
+		header, err := parseHeaderFlag(value)
+		if err != nil {
+			return err
+		}
+		o.Headers = append(o.Headers, header)
+		return nil
+	})
+	fs.Func("cookie", "cookie \"nom=valeur\" envoyé au site scrapé, comme un cookie de session (répétable)", func(value string) error {
+		cookie, err := parseCookieFlag(value)
+		if err != nil {
+			return err
+		}
+		o.Cookies = append(o.Cookies, cookie)
+		return nil
+	})
+	fs.StringVar(&o.Sitemap, "sitemap", o.Sitemap,
+		"URL d'un sitemap (ou index de sitemaps, gzip accepté) listant les recettes, à la place des catégories")
+	fs.Func("sitemap-pattern", "expression régulière des URLs de recettes retenues dans le sitemap (défaut /recipe/)", func(value string) error {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return err
+		}
+		o.SitemapPattern = pattern
+		return nil
+	})
+
+	fs.BoolVar(&o.Headless, "headless", o.Headless,
+		"récupérer par un navigateur headless les pages bloquées (403 répétés, challenge Cloudflare), puis continuer avec ses cookies")
+	fs.StringVar(&o.HeadlessBrowser, "headless-browser", o.HeadlessBrowser,
+		"avec -headless, navigateur Chrome ou Chromium à lancer (chemin ou nom dans le PATH)")
+	fs.Func("headless-after", "avec -headless, nombre de 403 sur une même URL avant de passer par le navigateur (défaut 2)", func(value string) error {
+		return parseHeadlessAfter(value, &o.HeadlessAfter)
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return o, err
+	}
+	return o, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressInterval est la période de rafraîchissement de la ligne de progression
+const progressInterval = 3 * time.Second
+
+// estimateRemaining estime le temps restant à partir du rythme observé
+// Retourne false tant qu'aucune recette n'a été traitée (rythme inconnu)
+func estimateRemaining(processed, found int64, elapsed time.Duration) (time.Duration, bool) {
+	if processed <= 0 || elapsed <= 0 {
+		return 0, false
+	}
+	remaining := found - processed
+	if remaining <= 0 {
+		return 0, true
+	}
+	perRecipe := elapsed / time.Duration(processed)
+	return perRecipe * time.Duration(remaining), true
+}
+
+// formatProgress construit la ligne de progression (sans retour à la ligne)
+func formatProgress(completed, failed, found int64, elapsed time.Duration) string {
+	processed := completed + failed
+	percent := 0.0
+	if found > 0 {
+		percent = float64(processed) / float64(found) * 100
+	}
+
+	eta := "?"
+	if remaining, ok := estimateRemaining(processed, found, elapsed); ok {
+		eta = remaining.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("⏳ %d/%d recettes (%.0f%%) - %d échecs - écoulé %v - restant ~%s",
+		completed, found, percent, failed, elapsed.Round(time.Second), eta)
+}
+
+// writeProgress réécrit la ligne de progression en place grâce à \r
+func writeProgress(w io.Writer, stats *ScrapingStats) {
+	stats.Mutex.RLock()
+	completed, failed, found := stats.RecipesCompleted, stats.RecipesFailed, stats.RecipesFound
+	elapsed := time.Since(stats.StartTime)
+	stats.Mutex.RUnlock()
+
+	// Les espaces finaux effacent les restes d'une ligne précédente plus longue
+	fmt.Fprintf(w, "\r%-100s", formatProgress(completed, failed, found, elapsed))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test du calcul du temps restant
+func TestEstimateRemaining(t *testing.T) {
+	// 10 recettes en 20s → 2s par recette, 40 restantes → 80s
+	eta, ok := estimateRemaining(10, 50, 20*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 80*time.Second, eta)
+
+	// Rythme inconnu tant qu'aucune recette n'est traitée
+	_, ok = estimateRemaining(0, 50, 20*time.Second)
+	assert.False(t, ok)
+
+	// Tout est traité (ou plus que trouvé pendant la découverte) : rien à attendre
+	eta, ok = estimateRemaining(50, 50, time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), eta)
+	eta, _ = estimateRemaining(60, 50, time.Minute)
+	assert.Equal(t, time.Duration(0), eta)
+}
+
+// Test du format de la ligne de progression
+func TestFormatProgress(t *testing.T) {
+	line := formatProgress(8, 2, 40, 20*time.Second)
+	assert.Contains(t, line, "8/40 recettes (25%)")
+	assert.Contains(t, line, "2 échecs")
+	assert.Contains(t, line, "restant ~1m0s")
+
+	assert.Contains(t, formatProgress(0, 0, 0, time.Second), "restant ~?")
+}
+
+// Test de la réécriture en place de la ligne
+func TestWriteProgress(t *testing.T) {
+	stats := NewScrapingStats(1)
+	stats.IncrementRecipesFound()
+	stats.IncrementRecipesCompleted()
+
+	var buf bytes.Buffer
+	writeProgress(&buf, stats)
+	assert.True(t, strings.HasPrefix(buf.String(), "\r"))
+	assert.NotContains(t, buf.String(), "\n")
+	assert.Contains(t, buf.String(), "1/1 recettes (100%)")
+}
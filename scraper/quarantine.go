@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// invalidFilename reçoit les recettes écartées de data.json faute d'un champ obligatoire
+const invalidFilename = "invalid.json"
+
+// defaultRequiredFields sont les champs obligatoires quand required_fields n'est pas configuré
+var defaultRequiredFields = []string{"name", "ingredients", "instructions"}
+
+// recipeFieldPresent indique, pour chaque champ pouvant être rendu obligatoire (nom json), s'il est renseigné
+var recipeFieldPresent = map[string]func(Recipe) bool{
+	"name":             func(r Recipe) bool { return strings.TrimSpace(r.Name) != "" },
+	"page":             func(r Recipe) bool { return r.Page != "" },
+	"image":            func(r Recipe) bool { return r.Image != "" },
+	"category":         func(r Recipe) bool { return r.Category != "" },
+	"language":         func(r Recipe) bool { return r.Language != "" },
+	"tags":             func(r Recipe) bool { return len(r.Tags) > 0 },
+	"cuisine":          func(r Recipe) bool { return r.Cuisine != "" },
+	"course":           func(r Recipe) bool { return r.Course != "" },
+	"ingredients":      func(r Recipe) bool { return len(r.Ingredients) > 0 },
+	"instructions":     func(r Recipe) bool { return len(r.Instructions) > 0 },
+	"prepTimeMinutes":  func(r Recipe) bool { return r.PrepTimeMinutes > 0 },
+	"cookTimeMinutes":  func(r Recipe) bool { return r.CookTimeMinutes > 0 },
+	"totalTimeMinutes": func(r Recipe) bool { return r.TotalTimeMinutes > 0 },
+	"rating":           func(r Recipe) bool { return r.Rating != nil },
+	"nutrition":        func(r Recipe) bool { return r.Nutrition != nil },
+}
+
+// validateRequiredFields vérifie que chaque champ obligatoire configuré existe
+func validateRequiredFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := recipeFieldPresent[field]; !ok {
+			known := make([]string, 0, len(recipeFieldPresent))
+			for name := range recipeFieldPresent {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("champ obligatoire inconnu %q: %s attendu", field, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// validateRecipe retourne les champs obligatoires absents de la recette, dans l'ordre de required
+func validateRecipe(recipe Recipe, required []string) []string {
+	var missing []string
+	for _, field := range required {
+		if present, ok := recipeFieldPresent[field]; ok && !present(recipe) {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// InvalidRecipe est une recette mise en quarantaine dans invalid.json
+type InvalidRecipe struct {
+	Missing []string `json:"missing"` // Champs obligatoires absents
+	Recipe  Recipe   `json:"recipe"`
+}
+
+// recipeQuarantine écarte les recettes incomplètes avant leur écriture dans data.json
+// Thread-safe grâce au Mutex ; une quarantaine nil accepte toutes les recettes (selftest, tests)
+type recipeQuarantine struct {
+	required []string
+	stats    *ScrapingStats // Compte les recettes écartées (nil = non comptées)
+
+	mu      sync.Mutex
+	entries []InvalidRecipe
+}
+
+// newRecipeQuarantine crée une quarantaine pour les champs obligatoires donnés (défaut si vide)
+// Les recettes écartées sont retirées des recettes complétées de stats (nil = non comptées)
+func newRecipeQuarantine(required []string, stats *ScrapingStats) *recipeQuarantine {
+	if len(required) == 0 {
+		required = defaultRequiredFields
+	}
+	return &recipeQuarantine{required: append([]string(nil), required...), stats: stats}
+}
+
+// Accept retourne true si la recette a tous les champs obligatoires, la met en quarantaine sinon
+func (q *recipeQuarantine) Accept(recipe Recipe) bool {
+	if q == nil {
+		return true
+	}
+	missing := validateRecipe(recipe, q.required)
+	if len(missing) == 0 {
+		return true
+	}
+	logRecipeInvalid(recipe.Name, missing)
+	if q.stats != nil {
+		q.stats.RecordInvalid()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries = append(q.entries, InvalidRecipe{Missing: missing, Recipe: recipe})
+	return false
+}
+
+// Entries retourne une copie des recettes en quarantaine
+func (q *recipeQuarantine) Entries() []InvalidRecipe {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]InvalidRecipe(nil), q.entries...)
+}
+
+// Save écrit la quarantaine dans dir/filename, vide comprise, pour ne pas laisser celle d'une exécution précédente
+func (q *recipeQuarantine) Save(dir, filename string) error {
+	entries := q.Entries()
+	if entries == nil {
+		entries = []InvalidRecipe{}
+	}
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutputFile(dir, filename, content)
+}
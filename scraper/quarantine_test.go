@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/maxime-louis14/api-golang/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Recette avec ingrédients et instructions, mais sans image ni valeurs nutritionnelles
+var partialRecipe = Recipe{
+	Name:         "Soupe",
+	Page:         "https://example.com/recipe/soupe",
+	Ingredients:  []Ingredient{{Quantity: "1", Unit: "l", Name: "eau"}},
+	Instructions: []Instruction{{Number: "1", Description: "Chauffer"}},
+}
+
+// Test des différents jeux de champs obligatoires
+func TestValidateRecipeRequiredFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []string
+		recipe   Recipe
+		missing  []string
+	}{
+		{"défaut complet", defaultRequiredFields, partialRecipe, nil},
+		{"défaut sans instructions", defaultRequiredFields, Recipe{Name: "Soupe", Ingredients: partialRecipe.Ingredients}, []string{"instructions"}},
+		{"défaut vide", defaultRequiredFields, Recipe{Name: "  "}, []string{"name", "ingredients", "instructions"}},
+		{"strict", []string{"name", "ingredients", "instructions", "image", "nutrition"}, partialRecipe, []string{"image", "nutrition"}},
+		{"strict complet", []string{"image", "nutrition"}, Recipe{Image: "https://example.com/soupe.jpg", Nutrition: &recipe.Nutrition{Calories: "120"}}, nil},
+		{"souple", []string{"name"}, Recipe{Name: "Soupe"}, nil},
+		{"aucun", []string{}, Recipe{}, nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.missing, validateRecipe(tt.recipe, tt.required), tt.name)
+	}
+}
+
+// Test que la quarantaine écarte les recettes incomplètes et les écrit dans invalid.json
+func TestRecipeQuarantine(t *testing.T) {
+	// Les trois recettes ont été comptées complétées par leur worker
+	stats := NewScrapingStats(1)
+	for i := 0; i < 3; i++ {
+		stats.IncrementRecipesFound()
+		stats.IncrementRecipesCompleted()
+	}
+	quarantine := newRecipeQuarantine([]string{"name", "ingredients", "image"}, stats)
+
+	completedRecipes := make(chan Recipe, 3)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	done := make(chan bool)
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, nil, quarantine)
+
+	withImage := partialRecipe
+	withImage.Image = "https://example.com/soupe.jpg"
+	completedRecipes <- withImage
+	completedRecipes <- partialRecipe
+	completedRecipes <- Recipe{Name: "Vide"}
+	close(completedRecipes)
+	<-done
+
+	require.Len(t, recipes, 1)
+	assert.Equal(t, withImage.Image, recipes[0].Image)
+
+	// Les recettes écartées ne comptent pas dans le taux de succès
+	assert.Equal(t, int64(1), stats.RecipesCompleted)
+	assert.Equal(t, int64(2), stats.RecipesInvalid)
+	assert.Equal(t, exitLowSuccessRate, successRateExitCode(stats, 0.5))
+
+	dir := t.TempDir()
+	require.NoError(t, quarantine.Save(dir, invalidFilename))
+	content, err := os.ReadFile(filepath.Join(dir, invalidFilename))
+	require.NoError(t, err)
+	var entries []InvalidRecipe
+	require.NoError(t, json.Unmarshal(content, &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"image"}, entries[0].Missing)
+	assert.Equal(t, "Soupe", entries[0].Recipe.Name)
+	assert.Equal(t, []string{"ingredients", "image"}, entries[1].Missing)
+
+	// Sans champs configurés : name, ingredients, instructions
+	assert.Equal(t, defaultRequiredFields, newRecipeQuarantine(nil, nil).required)
+	// Une quarantaine nil accepte tout
+	assert.True(t, (*recipeQuarantine)(nil).Accept(Recipe{}))
+}
+
+// Test de required_fields dans le fichier de configuration
+func TestLoadScraperConfigRequiredFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultConfigFilename)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"categories": ["https://example.com/recipes/1/"], "required_fields": ["name", "ingredients", "instructions", "image", "nutrition"]}`), 0644))
+	config, err := loadScraperConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "ingredients", "instructions", "image", "nutrition"}, config.RequiredFields)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"categories": ["https://example.com/recipes/1/"], "required_fields": ["name", "photo"]}`), 0644))
+	_, err = loadScraperConfig(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"photo"`)
+}
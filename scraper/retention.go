@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// timestampGlob reconnaît un horodatage de fichier de sortie (scraperspec.OutputTimestampLayout : 20240101-060000)
+// Plus strict que *, pour ne jamais supprimer un fichier posé à la main à côté (data-old.json)
+var timestampGlob = strings.Repeat("[0-9]", 8) + "-" + strings.Repeat("[0-9]", 6)
+
+// outputRetention est la rétention des fichiers de sortie horodatés (-keep-last, -keep-days)
+// Avec les deux limites, un fichier est gardé s'il respecte l'une ou l'autre
+type outputRetention struct {
+	Last int // Nombre de fichiers les plus récents gardés (0 = pas de limite par nombre)
+	Days int // Âge maximal en jours des fichiers gardés (0 = pas de limite par âge)
+}
+
+// enabled indique si une limite est définie
+func (r outputRetention) enabled() bool {
+	return r.Last > 0 || r.Days > 0
+}
+
+// keeps indique si le fichier de rang rank (0 = le plus récent) modifié à modifiedAt est gardé
+func (r outputRetention) keeps(rank int, modifiedAt, now time.Time) bool {
+	if r.Last > 0 && rank < r.Last {
+		return true
+	}
+	if r.Days > 0 && now.Sub(modifiedAt) <= time.Duration(r.Days)*24*time.Hour {
+		return true
+	}
+	return false
+}
+
+// outputGlob retourne le motif des fichiers de sortie horodatés produits par -output
+// ({timestamp} dans le nom, ou suffixe ajouté par -schedule) ; vide si la sortie n'est pas horodatée
+func outputGlob(output string, scheduled bool) string {
+	switch {
+	case output == stdoutOutput:
+		return ""
+	case strings.Contains(output, scraperspec.OutputTimestampPlaceholder):
+		return strings.ReplaceAll(output, scraperspec.OutputTimestampPlaceholder, timestampGlob)
+	case scheduled:
+		ext := filepath.Ext(output)
+		return strings.TrimSuffix(output, ext) + "-" + timestampGlob + ext
+	default:
+		return ""
+	}
+}
+
+// pruneOldOutputs supprime dans dir les fichiers correspondant à pattern que keep ne garde pas,
+// du plus récent au plus ancien par date de modification (à égalité, par nom)
+// current, le fichier qui vient d'être écrit (relatif à dir), n'est jamais supprimé et compte parmi les gardés
+// Retourne les chemins supprimés ; une suppression en échec n'interrompt pas les suivantes
+func pruneOldOutputs(dir, pattern string, keep outputRetention, current string, now time.Time) ([]string, error) {
+	if !keep.enabled() || pattern == "" {
+		return nil, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	type output struct {
+		path       string
+		modifiedAt time.Time
+	}
+	currentPath := filepath.Clean(filepath.Join(dir, current))
+	outputs := make([]output, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		outputs = append(outputs, output{path: path, modifiedAt: info.ModTime()})
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		if !outputs[i].modifiedAt.Equal(outputs[j].modifiedAt) {
+			return outputs[i].modifiedAt.After(outputs[j].modifiedAt)
+		}
+		return outputs[i].path > outputs[j].path
+	})
+
+	var removed []string
+	var errs []error
+	for rank, candidate := range outputs {
+		if filepath.Clean(candidate.path) == currentPath || keep.keeps(rank, candidate.modifiedAt, now) {
+			continue
+		}
+		if err := os.Remove(candidate.path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, candidate.path)
+	}
+	return removed, errors.Join(errs...)
+}
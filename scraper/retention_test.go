@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDatedOutputs crée data-<horodatage>.json pour chaque âge, modifié à now - âge
+func writeDatedOutputs(t *testing.T, dir string, now time.Time, ages ...time.Duration) []string {
+	names := make([]string, 0, len(ages))
+	for _, age := range ages {
+		at := now.Add(-age)
+		name := expandOutputPattern("data-{timestamp}.json", at)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+		require.NoError(t, os.Chtimes(path, at, at))
+		names = append(names, name)
+	}
+	return names
+}
+
+// remaining liste les fichiers restant dans dir
+func remaining(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// -keep-last garde les N fichiers les plus récents, sans toucher aux fichiers hors motif
+func TestPruneOldOutputsKeepLast(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 10, 6, 0, 0, 0, time.Local)
+	names := writeDatedOutputs(t, dir, now, 0, 24*time.Hour, 48*time.Hour, 72*time.Hour)
+	for _, other := range []string{"data.json", "data-old.json", "stats.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, other), []byte(`[]`), 0644))
+	}
+
+	removed, err := pruneOldOutputs(dir, outputGlob("data-{timestamp}.json", false), outputRetention{Last: 2}, names[0], now)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(dir, names[2]), filepath.Join(dir, names[3])}, removed)
+	assert.ElementsMatch(t, []string{names[0], names[1], "data.json", "data-old.json", "stats.json"}, remaining(t, dir))
+}
+
+// -keep-days garde les fichiers modifiés dans les D derniers jours
+func TestPruneOldOutputsKeepDays(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 10, 6, 0, 0, 0, time.Local)
+	names := writeDatedOutputs(t, dir, now, time.Hour, 36*time.Hour, 5*24*time.Hour, 9*24*time.Hour)
+
+	removed, err := pruneOldOutputs(dir, outputGlob("data-{timestamp}.json", false), outputRetention{Days: 2}, names[0], now)
+	require.NoError(t, err)
+	assert.Len(t, removed, 2)
+	assert.ElementsMatch(t, names[:2], remaining(t, dir))
+
+	// Avec les deux limites, un fichier est gardé s'il respecte l'une ou l'autre
+	dir = t.TempDir()
+	names = writeDatedOutputs(t, dir, now, time.Hour, 36*time.Hour, 5*24*time.Hour, 9*24*time.Hour)
+	_, err = pruneOldOutputs(dir, outputGlob("data-{timestamp}.json", false), outputRetention{Last: 3, Days: 2}, names[0], now)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, names[:3], remaining(t, dir))
+}
+
+// Le fichier qui vient d'être écrit n'est jamais supprimé, même s'il n'est pas le plus récent
+func TestPruneOldOutputsKeepsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 10, 6, 0, 0, 0, time.Local)
+	names := writeDatedOutputs(t, dir, now, 0, 24*time.Hour, 48*time.Hour)
+
+	removed, err := pruneOldOutputs(dir, outputGlob("data-{timestamp}.json", false), outputRetention{Last: 1}, names[2], now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, names[1])}, removed)
+	assert.ElementsMatch(t, []string{names[0], names[2]}, remaining(t, dir))
+
+	// Sans rétention ni sortie horodatée, rien n'est supprimé
+	removed, err = pruneOldOutputs(dir, outputGlob("data-{timestamp}.json", false), outputRetention{}, names[0], now)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	removed, err = pruneOldOutputs(dir, outputGlob("data.json", false), outputRetention{Last: 1}, "data.json", now)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestOutputGlob(t *testing.T) {
+	assert.Equal(t, "data-"+timestampGlob+".json", outputGlob("data-{timestamp}.json", false))
+	assert.Equal(t, "data-"+timestampGlob+".json", outputGlob("data.json", true))
+	assert.Empty(t, outputGlob("data.json", false))
+	assert.Empty(t, outputGlob(stdoutOutput, true))
+
+	// Le motif reconnaît les noms produits par -schedule et par {timestamp}
+	at := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	matched, err := filepath.Match(outputGlob("data.json", true), timestampedFilename("data.json", at))
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func TestRetentionOptions(t *testing.T) {
+	o, err := parseOptions([]string{"-keep-last", "5", "-keep-days", "30"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, 5, o.KeepLast)
+	assert.Equal(t, 30, o.KeepDays)
+
+	for _, args := range [][]string{{"-keep-last", "-1"}, {"-keep-days", "une semaine"}} {
+		_, err := parseOptions(args, io.Discard)
+		assert.Error(t, err, args)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/robfig/cron/v3"
+)
+
+// scheduledRun exécute une collecte planifiée en sautant l'échéance si la précédente est encore en cours
+type scheduledRun struct {
+	run      func()
+	schedule cron.Schedule
+	running  atomic.Bool
+}
+
+// Run est appelé par le planificateur à chaque échéance
+func (s *scheduledRun) Run() {
+	if !s.running.CompareAndSwap(false, true) {
+		logScheduleSkipped(s.schedule.Next(time.Now()))
+		return
+	}
+	defer s.running.Store(false)
+
+	logScheduleRunStart()
+	s.run()
+	logScheduleNextRun(s.schedule.Next(time.Now()))
+}
+
+// parseSchedule valide une planification : cron standard à 5 champs ou descripteur (@every 6h, @daily...)
+func parseSchedule(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(spec)
+}
+
+// startSchedule lance run à chaque échéance de spec
+// stop arrête le planificateur et attend la fin de l'exécution en cours
+func startSchedule(spec string, run func()) (stop func(), err error) {
+	schedule, err := parseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	c := cron.New()
+	c.Schedule(schedule, &scheduledRun{run: run, schedule: schedule})
+	c.Start()
+	logScheduleStart(spec, schedule.Next(time.Now()))
+
+	return func() {
+		<-c.Stop().Done()
+	}, nil
+}
+
+// runSchedule garde le processus actif et lance run selon spec jusqu'à SIGINT ou SIGTERM
+func runSchedule(spec string, run func()) error {
+	stop, err := startSchedule(spec, run)
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	logScheduleStop()
+	stop()
+	return nil
+}
+
+// expandOutputPattern remplace {timestamp} dans le nom du fichier de sortie par l'horodatage t
+func expandOutputPattern(name string, t time.Time) string {
+	return strings.ReplaceAll(name, scraperspec.OutputTimestampPlaceholder, t.Format(scraperspec.OutputTimestampLayout))
+}
+
+// timestampedFilename insère l'horodatage d'une exécution planifiée avant l'extension (data.json → data-20240101-060000.json)
+// La sortie sur stdout (-output -) et un nom contenant déjà {timestamp}, horodaté à la sauvegarde, sont conservés tels quels
+func timestampedFilename(name string, t time.Time) string {
+	if name == stdoutOutput || strings.Contains(name, scraperspec.OutputTimestampPlaceholder) {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + t.Format(scraperspec.OutputTimestampLayout) + ext
+}
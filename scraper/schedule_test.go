@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du mode planifié : une planification rapide déclenche plusieurs exécutions
+func TestScheduleFiresRuns(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	stop, err := startSchedule("@every 1s", func() {
+		runs <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("exécution %d non déclenchée", i+1)
+		}
+	}
+
+	_, err = startSchedule("toutes les heures", func() {})
+	assert.Error(t, err)
+}
+
+// Test du chevauchement : une échéance est ignorée tant que l'exécution précédente tourne
+func TestScheduledRunSkipsOverlap(t *testing.T) {
+	schedule, err := parseSchedule("@every 1h")
+	require.NoError(t, err)
+
+	var count atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	job := &scheduledRun{schedule: schedule, run: func() {
+		count.Add(1)
+		close(started)
+		<-release
+	}}
+
+	finished := make(chan struct{})
+	go func() {
+		job.Run()
+		close(finished)
+	}()
+	<-started
+
+	job.Run() // Ignorée : retourne immédiatement
+	assert.Equal(t, int32(1), count.Load())
+
+	close(release)
+	<-finished
+	assert.False(t, job.running.Load())
+}
+
+// Test du nom des fichiers des exécutions planifiées et de l'option -schedule
+func TestScheduleOutput(t *testing.T) {
+	at := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	assert.Equal(t, "data-20240301-060000.json", timestampedFilename("data.json", at))
+	assert.Equal(t, "recipes-20240301-060000", timestampedFilename("recipes", at))
+	assert.Equal(t, stdoutOutput, timestampedFilename(stdoutOutput, at))
+	// Un nom avec {timestamp} est déjà horodaté à la sauvegarde
+	assert.Equal(t, "data-{timestamp}.json", timestampedFilename("data-{timestamp}.json", at))
+
+	o, err := parseOptions([]string{"-schedule", "0 */6 * * *"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "0 */6 * * *", o.Schedule)
+	_, err = parseOptions([]string{"-schedule", "@every"}, io.Discard)
+	assert.Error(t, err)
+}
+
+// {timestamp} est remplacé par l'horodatage de la sauvegarde, partout dans le nom
+func TestExpandOutputPattern(t *testing.T) {
+	at := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	assert.Equal(t, "data-20240301-060000.json", expandOutputPattern("data-{timestamp}.json", at))
+	assert.Equal(t, "20240301-060000/data-20240301-060000.ndjson", expandOutputPattern("{timestamp}/data-{timestamp}.ndjson", at))
+	assert.Equal(t, "data.json", expandOutputPattern("data.json", at))
+}
+
+// saveRecipesToFile développe le motif au moment de l'écriture et retourne le nom du fichier écrit
+func TestSaveRecipesToFilePattern(t *testing.T) {
+	dir := t.TempDir()
+	before := time.Now().Truncate(time.Second)
+
+	saved, err := saveRecipesToFile([]Recipe{{Name: "Soupe"}}, dir, "data-{timestamp}.json")
+	require.NoError(t, err)
+	assert.Regexp(t, `^data-\d{8}-\d{6}\.json$`, saved)
+	at, err := time.ParseInLocation("data-"+scraperspec.OutputTimestampLayout+".json", saved, time.Local)
+	require.NoError(t, err)
+	assert.False(t, at.Before(before))
+
+	_, err = os.Stat(filepath.Join(dir, saved))
+	assert.NoError(t, err)
+}
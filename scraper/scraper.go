@@ -1,17 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gocolly/colly"
+	"github.com/maxime-louis14/api-golang/recipe"
 )
 
 // Variables de versioning injectées lors du build
@@ -22,35 +32,46 @@ var (
 	buildTime = "unknown" // Timestamp de compilation
 )
 
-// BuildInfo supprimé - non utilisé après réduction des logs
+// BuildInfo contient les informations de build (exposées par -version -json)
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
 
 // Recipe représente une recette complète avec tous ses détails
-type Recipe struct {
-	Name         string        `json:"name"`         // Nom de la recette
-	Page         string        `json:"page"`         // URL de la page de la recette
-	Image        string        `json:"image"`        // URL de l'image de la recette
-	Ingredients  []Ingredient  `json:"ingredients"`  // Liste des ingrédients
-	Instructions []Instruction `json:"instructions"` // Liste des instructions
-}
+// Le modèle est partagé avec l'API (package recipe) : data.json se décode tel quel en models.Recette
+type Recipe = recipe.Recipe
 
 // Ingredient représente un ingrédient avec sa quantité et son unité
-type Ingredient struct {
-	Quantity string `json:"quantity"` // Quantité (ex: "2", "1/2")
-	Unit     string `json:"unit"`     // Unité (ex: "cups", "tablespoons")
-}
+type Ingredient = recipe.Ingredient
 
 // Instruction représente une étape de la recette
-type Instruction struct {
-	Number      string `json:"number"`      // Numéro de l'étape (ex: "1", "2")
-	Description string `json:"description"` // Description de l'étape
-}
+type Instruction = recipe.Instruction
+
+// Nutrition reprend les valeurs nutritionnelles de la page, unités comprises
+type Nutrition = recipe.Nutrition
+
+// Rating est la note moyenne d'une recette et le nombre d'avis
+type Rating = recipe.Rating
+
+// recipeID et recipeContentHash sont recipe.ID et recipe.ContentHash, utilisables là où une variable recipe masque le package
+var (
+	recipeID          = recipe.ID
+	recipeContentHash = recipe.ContentHash
+)
 
 // RecipeData contient les informations de base d'une recette avant le scraping détaillé
 // Utilisé pour passer les données entre les goroutines
+// Les tags JSON servent au fichier de débordement (spillover.jsonl)
 type RecipeData struct {
-	URL   string // URL de la page de la recette
-	Title string // Titre de la recette
-	Image string // URL de l'image de la recette
+	URL      string `json:"url"`                // URL de la page de la recette
+	Title    string `json:"title"`              // Titre de la recette
+	Image    string `json:"image,omitempty"`    // URL de l'image de la recette
+	Category string `json:"category,omitempty"` // Catégorie d'origine (dernier segment de l'URL de la page de catégorie)
 }
 
 // ScrapingStats contient toutes les statistiques de performance du scraper
@@ -60,11 +81,23 @@ type ScrapingStats struct {
 	TotalRequests    int64 `json:"total_requests"`     // Total des requêtes HTTP
 	MainPageRequests int64 `json:"main_page_requests"` // Requêtes vers les pages de catégories
 	RecipeRequests   int64 `json:"recipe_requests"`    // Requêtes vers les pages de recettes
+	PagesUnchanged   int64 `json:"pages_unchanged"`    // Pages de catégories non modifiées (réponse 304)
+
+	// Répartition des réponses par code HTTP (200, 403, 429, 500...)
+	StatusCodes map[int]int64 `json:"status_codes"`
 
 	// Compteurs de recettes
 	RecipesFound     int64 `json:"recipes_found"`     // Nombre de recettes découvertes
 	RecipesCompleted int64 `json:"recipes_completed"` // Nombre de recettes traitées avec succès
 	RecipesFailed    int64 `json:"recipes_failed"`    // Nombre de recettes en échec
+	RecipesSkipped   int64 `json:"recipes_skipped"`   // Recettes non visitées faute de budget (-max-requests)
+	RecipesInvalid   int64 `json:"recipes_invalid"`   // Recettes extraites mais incomplètes, mises en quarantaine
+
+	// Répartition des recettes en échec par catégorie (voir classifyFailure)
+	FailedHTTP    int64 `json:"failed_http"`    // Statut HTTP en erreur ou connexion impossible
+	FailedTimeout int64 `json:"failed_timeout"` // Délai dépassé ou requête annulée
+	FailedEmpty   int64 `json:"failed_empty"`   // Page sans ingrédient
+	FailedParse   int64 `json:"failed_parse"`   // Réponse illisible
 
 	// Métriques de performance temporelles
 	StartTime         time.Time     `json:"start_time"`          // Heure de début du scraping
@@ -73,24 +106,42 @@ type ScrapingStats struct {
 	RequestsPerSecond float64       `json:"requests_per_second"` // Requêtes par seconde
 	RecipesPerSecond  float64       `json:"recipes_per_second"`  // Recettes par seconde
 
+	// Latences des réponses (OnRequest → OnResponse), calculées par CalculateFinalStats
+	LatencyP50     time.Duration   `json:"latency_p50"` // Médiane
+	LatencyP90     time.Duration   `json:"latency_p90"` // 90e centile
+	LatencyP99     time.Duration   `json:"latency_p99"` // 99e centile
+	latencySamples []time.Duration // Réservoir d'échantillons (taille bornée par maxLatencySamples)
+	latencySeen    int64           // Nombre total de durées observées
+
+	// Temps cumulés des recettes par phase (voir recipePhases)
+	TotalHTTPDuration  time.Duration `json:"total_http_duration"`  // Requête → réponse
+	TotalParseDuration time.Duration `json:"total_parse_duration"` // Réponse → fin de l'extraction HTML
+
+	// Pics d'utilisation mémoire et de goroutines, échantillonnés pendant l'exécution (StartRuntimeSampler)
+	PeakHeapBytes  uint64 `json:"peak_heap_bytes"` // Pic de mémoire du tas allouée (HeapAlloc)
+	PeakGoroutines int    `json:"peak_goroutines"` // Pic du nombre de goroutines
+
 	// Configuration des workers
 	MaxWorkers    int   `json:"max_workers"`    // Nombre maximum de workers
 	ActiveWorkers int64 `json:"active_workers"` // Nombre de workers actifs
 
 	// Statistiques détaillées par worker
-	WorkerStats map[int]WorkerStats `json:"worker_stats"` // Map des stats par worker
+	// Écrites dans stats.json sous forme de tableau trié par worker (voir MarshalJSON)
+	WorkerStats map[int]WorkerStats `json:"-"` // Map des stats par worker
 
-	Mutex sync.RWMutex // Mutex pour la sécurité des accès concurrents
+	Mutex sync.RWMutex `json:"-"` // Mutex pour la sécurité des accès concurrents
 }
 
 // WorkerStats contient les statistiques d'un worker individuel
 type WorkerStats struct {
 	WorkerID         int           `json:"worker_id"`         // ID unique du worker
-	RequestsHandled  int64         `json:"requests_handled"`  // Nombre de requêtes traitées
-	RecipesProcessed int64         `json:"recipes_processed"` // Nombre de recettes traitées
+	RequestsHandled  int64         `json:"requests_handled"`  // Nombre de requêtes envoyées, nouvelles tentatives et échecs compris
+	RecipesProcessed int64         `json:"recipes_processed"` // Nombre de recettes complétées
 	StartTime        time.Time     `json:"start_time"`        // Heure de démarrage du worker
 	EndTime          time.Time     `json:"end_time"`          // Heure de fin du worker
 	Duration         time.Duration `json:"duration"`          // Durée totale d'activité
+	HTTPDuration     time.Duration `json:"http_duration"`     // Temps cumulé des requêtes HTTP
+	ParseDuration    time.Duration `json:"parse_duration"`    // Temps cumulé du parsing HTML
 }
 
 // NewScrapingStats crée une nouvelle instance de ScrapingStats
@@ -100,6 +151,7 @@ func NewScrapingStats(maxWorkers int) *ScrapingStats {
 		StartTime:   time.Now(),                // Initialiser avec l'heure actuelle
 		MaxWorkers:  maxWorkers,                // Stocker le nombre max de workers
 		WorkerStats: make(map[int]WorkerStats), // Initialiser la map des stats par worker
+		StatusCodes: make(map[int]int64),       // Initialiser la répartition des codes HTTP
 	}
 }
 
@@ -121,6 +173,37 @@ func (s *ScrapingStats) IncrementRecipeRequest() {
 	s.RecipeRequests++ // Incrémenter les requêtes vers les recettes
 }
 
+// IncrementPagesUnchanged incrémente le compteur de pages de catégories non modifiées (304)
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) IncrementPagesUnchanged() {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.PagesUnchanged++ // Incrémenter le nombre de pages inchangées
+}
+
+// AllPagesUnchanged indique une exécution -if-modified-since où toutes les pages de catégories ont répondu 304
+// sans qu'aucune recette ne soit trouvée : la sortie précédente reste valable
+func (s *ScrapingStats) AllPagesUnchanged() bool {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+	return s.PagesUnchanged > 0 && s.PagesUnchanged == s.MainPageRequests && s.RecipesFound == 0
+}
+
+// IncrementStatusCode comptabilise une réponse HTTP par code de statut
+// Les erreurs réseau (code 0, aucune réponse reçue) ne sont pas comptées
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) IncrementStatusCode(code int) {
+	if code == 0 {
+		return
+	}
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if s.StatusCodes == nil {
+		s.StatusCodes = make(map[int]int64)
+	}
+	s.StatusCodes[code]++
+}
+
 // IncrementRecipesFound incrémente le compteur de recettes découvertes
 // Thread-safe grâce au mutex
 func (s *ScrapingStats) IncrementRecipesFound() {
@@ -145,6 +228,55 @@ func (s *ScrapingStats) IncrementRecipesFailed() {
 	s.RecipesFailed++ // Incrémenter le nombre de recettes échouées
 }
 
+// RecordFailure compte une recette en échec dans RecipesFailed et dans sa catégorie (failureHTTP...)
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) RecordFailure(category string) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.RecipesFailed++
+	switch category {
+	case failureTimeout:
+		s.FailedTimeout++
+	case failureEmpty:
+		s.FailedEmpty++
+	case failureParse:
+		s.FailedParse++
+	default:
+		s.FailedHTTP++
+	}
+}
+
+// RecordInvalid compte une recette mise en quarantaine : comptée complétée par son worker,
+// elle passe de RecipesCompleted à RecipesInvalid et ne compte plus dans le taux de succès
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) RecordInvalid() {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.RecipesCompleted--
+	s.RecipesInvalid++
+}
+
+// IncrementRecipesSkipped incrémente le compteur de recettes non visitées (budget épuisé)
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) IncrementRecipesSkipped() {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.RecipesSkipped++
+}
+
+// FinishWorker enregistre les stats finales d'un worker à sa sortie et les retourne
+// Les compteurs remplacent ceux déjà enregistrés pour ce worker au lieu de s'y ajouter :
+// chaque recette reste comptée une seule fois même si l'enregistrement est répété
+func (s *ScrapingStats) FinishWorker(workerStats WorkerStats) WorkerStats {
+	workerStats.EndTime = time.Now()
+	workerStats.Duration = workerStats.EndTime.Sub(workerStats.StartTime)
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.WorkerStats[workerStats.WorkerID] = workerStats
+	return workerStats
+}
+
 func (s *ScrapingStats) UpdateWorkerStats(workerID int, requests, recipes int64) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
@@ -167,6 +299,64 @@ func (s *ScrapingStats) UpdateWorkerStats(workerID int, requests, recipes int64)
 	}
 }
 
+// AddPhaseDurations cumule les temps HTTP et de parsing d'une recette
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) AddPhaseDurations(httpDuration, parseDuration time.Duration) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	s.TotalHTTPDuration += httpDuration
+	s.TotalParseDuration += parseDuration
+}
+
+// SampleRuntime relève la mémoire du tas et le nombre de goroutines et met à jour les pics
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) SampleRuntime() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	goroutines := runtime.NumGoroutine()
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if mem.HeapAlloc > s.PeakHeapBytes {
+		s.PeakHeapBytes = mem.HeapAlloc
+	}
+	if goroutines > s.PeakGoroutines {
+		s.PeakGoroutines = goroutines
+	}
+}
+
+// runtimeSampleInterval est l'intervalle d'échantillonnage de la mémoire et des goroutines
+// runtime.ReadMemStats arrête brièvement le programme : inutile d'échantillonner plus souvent
+const runtimeSampleInterval = time.Second
+
+// StartRuntimeSampler appelle SampleRuntime immédiatement puis à chaque intervalle
+// Retourne la fonction d'arrêt, qui prend un dernier échantillon
+func (s *ScrapingStats) StartRuntimeSampler(interval time.Duration) func() {
+	s.SampleRuntime()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				s.SampleRuntime()
+				return
+			case <-ticker.C:
+				s.SampleRuntime()
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
 func (s *ScrapingStats) GetTotalRequests() int64 {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
@@ -184,29 +374,83 @@ func (s *ScrapingStats) CalculateFinalStats() {
 		s.RequestsPerSecond = float64(s.TotalRequests) / s.TotalDuration.Seconds()
 		s.RecipesPerSecond = float64(s.RecipesCompleted) / s.TotalDuration.Seconds()
 	}
+
+	s.computeLatencyPercentiles()
 }
 
 func (s *ScrapingStats) GetDetailedStats() ScrapingStats {
 	s.Mutex.RLock()
 	defer s.Mutex.RUnlock()
 
+	// Copier les maps pour que l'appelant puisse les lire sans verrou
+	statusCodes := make(map[int]int64, len(s.StatusCodes))
+	for code, count := range s.StatusCodes {
+		statusCodes[code] = count
+	}
+	workerStats := make(map[int]WorkerStats, len(s.WorkerStats))
+	for workerID, worker := range s.WorkerStats {
+		workerStats[workerID] = worker
+	}
+
 	// Créer une copie sans le mutex
 	return ScrapingStats{
-		TotalRequests:     s.TotalRequests,
-		MainPageRequests:  s.MainPageRequests,
-		RecipeRequests:    s.RecipeRequests,
-		RecipesFound:      s.RecipesFound,
-		RecipesCompleted:  s.RecipesCompleted,
-		RecipesFailed:     s.RecipesFailed,
-		StartTime:         s.StartTime,
-		EndTime:           s.EndTime,
-		TotalDuration:     s.TotalDuration,
-		RequestsPerSecond: s.RequestsPerSecond,
-		RecipesPerSecond:  s.RecipesPerSecond,
-		MaxWorkers:        s.MaxWorkers,
-		ActiveWorkers:     s.ActiveWorkers,
-		WorkerStats:       s.WorkerStats,
+		TotalRequests:      s.TotalRequests,
+		MainPageRequests:   s.MainPageRequests,
+		RecipeRequests:     s.RecipeRequests,
+		PagesUnchanged:     s.PagesUnchanged,
+		StatusCodes:        statusCodes,
+		RecipesFound:       s.RecipesFound,
+		RecipesCompleted:   s.RecipesCompleted,
+		RecipesFailed:      s.RecipesFailed,
+		RecipesSkipped:     s.RecipesSkipped,
+		RecipesInvalid:     s.RecipesInvalid,
+		FailedHTTP:         s.FailedHTTP,
+		FailedTimeout:      s.FailedTimeout,
+		FailedEmpty:        s.FailedEmpty,
+		FailedParse:        s.FailedParse,
+		StartTime:          s.StartTime,
+		EndTime:            s.EndTime,
+		TotalDuration:      s.TotalDuration,
+		RequestsPerSecond:  s.RequestsPerSecond,
+		RecipesPerSecond:   s.RecipesPerSecond,
+		LatencyP50:         s.LatencyP50,
+		LatencyP90:         s.LatencyP90,
+		LatencyP99:         s.LatencyP99,
+		TotalHTTPDuration:  s.TotalHTTPDuration,
+		TotalParseDuration: s.TotalParseDuration,
+		PeakHeapBytes:      s.PeakHeapBytes,
+		PeakGoroutines:     s.PeakGoroutines,
+		MaxWorkers:         s.MaxWorkers,
+		ActiveWorkers:      s.ActiveWorkers,
+		WorkerStats:        workerStats,
+	}
+}
+
+// SortedWorkerStats retourne les stats des workers triées par ID, pour un affichage et un stats.json stables
+// Thread-safe grâce au mutex
+func (s *ScrapingStats) SortedWorkerStats() []WorkerStats {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	workers := make([]WorkerStats, 0, len(s.WorkerStats))
+	for _, worker := range s.WorkerStats {
+		workers = append(workers, worker)
 	}
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].WorkerID < workers[j].WorkerID
+	})
+	return workers
+}
+
+// scrapingStatsJSON a les champs de ScrapingStats sans sa méthode MarshalJSON
+type scrapingStatsJSON ScrapingStats
+
+// MarshalJSON écrit worker_stats sous forme de tableau trié par ID (une map donnerait l'ordre "1", "10", "2")
+func (s *ScrapingStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*scrapingStatsJSON
+		WorkerStats []WorkerStats `json:"worker_stats"`
+	}{(*scrapingStatsJSON)(s), s.SortedWorkerStats()})
 }
 
 // getPhysicalCores détecte le vrai nombre de cœurs physiques
@@ -277,27 +521,66 @@ func calculateAdaptiveRatio(numCores int) int {
 	}
 }
 
-// calculateOptimalWorkers calcule le nombre optimal de workers basé sur les ressources CPU
-// minWorkers: nombre minimum de workers (par défaut 1)
-// maxWorkers: nombre maximum de workers (par défaut 50)
-func calculateOptimalWorkers(minWorkers, maxWorkers int) int {
-	// Détecter le vrai nombre de cœurs physiques
-	numPhysicalCores := getPhysicalCores()
+// workerSizing décrit la décision de dimensionnement du pool de workers
+type workerSizing struct {
+	LogicalCPU    int    // runtime.NumCPU()
+	PhysicalCores int    // Cœurs physiques détectés (après correction)
+	AdaptiveRatio int    // Workers par cœur
+	Calculated    int    // Résultat de l'heuristique avant bornes
+	Workers       int    // Nombre de workers retenu
+	Override      bool   // SCRAPER_WORKERS utilisé à la place de l'heuristique
+	Corrected     bool   // Détection des cœurs incohérente, remplacée par le nombre logique
+	OverrideError string // SCRAPER_WORKERS invalide (ignoré)
+}
 
-	// Calculer le ratio adaptatif basé sur le nombre de cœurs
-	adaptiveRatio := calculateAdaptiveRatio(numPhysicalCores)
+// sizeWorkers calcule le nombre de workers à partir des CPU détectés
+// override (SCRAPER_WORKERS) contourne l'heuristique ; le résultat reste borné par [minWorkers, maxWorkers]
+func sizeWorkers(logicalCPU, physicalCores, minWorkers, maxWorkers int, override string) workerSizing {
+	sizing := workerSizing{LogicalCPU: logicalCPU, PhysicalCores: physicalCores}
 
-	optimalWorkers := numPhysicalCores * adaptiveRatio
+	// Garde-fous : des cœurs physiques absents ou plus nombreux que les CPU logiques sont incohérents
+	if sizing.LogicalCPU < 1 {
+		sizing.LogicalCPU = 1
+	}
+	if sizing.PhysicalCores < 1 || sizing.PhysicalCores > sizing.LogicalCPU {
+		sizing.PhysicalCores = sizing.LogicalCPU
+		sizing.Corrected = true
+	}
+
+	sizing.AdaptiveRatio = calculateAdaptiveRatio(sizing.PhysicalCores)
+	sizing.Calculated = sizing.PhysicalCores * sizing.AdaptiveRatio
+	sizing.Workers = sizing.Calculated
+
+	if override != "" {
+		if workers, err := strconv.Atoi(override); err == nil && workers > 0 {
+			sizing.Workers = workers
+			sizing.Override = true
+		} else {
+			sizing.OverrideError = fmt.Sprintf("SCRAPER_WORKERS invalide %q: entier positif attendu", override)
+		}
+	}
 
 	// Appliquer les limites
-	if optimalWorkers < minWorkers {
-		optimalWorkers = minWorkers
+	if sizing.Workers < minWorkers {
+		sizing.Workers = minWorkers
 	}
-	if optimalWorkers > maxWorkers {
-		optimalWorkers = maxWorkers
+	if sizing.Workers > maxWorkers {
+		sizing.Workers = maxWorkers
 	}
 
-	return optimalWorkers
+	return sizing
+}
+
+// resolveWorkerSizing dimensionne le pool à partir de la machine courante et de SCRAPER_WORKERS
+func resolveWorkerSizing(minWorkers, maxWorkers int) workerSizing {
+	return sizeWorkers(runtime.NumCPU(), getPhysicalCores(), minWorkers, maxWorkers, os.Getenv("SCRAPER_WORKERS"))
+}
+
+// calculateOptimalWorkers calcule le nombre optimal de workers basé sur les ressources CPU
+// minWorkers: nombre minimum de workers (par défaut 1)
+// maxWorkers: nombre maximum de workers (par défaut 50)
+func calculateOptimalWorkers(minWorkers, maxWorkers int) int {
+	return resolveWorkerSizing(minWorkers, maxWorkers).Workers
 }
 
 // printVersionInfo affiche les informations de version
@@ -305,7 +588,28 @@ func printVersionInfo() {
 	logVersionPrint(version, gitCommit, buildTime, runtime.Version(), runtime.GOOS, runtime.GOARCH)
 }
 
-// getBuildInfo supprimé - non utilisé après réduction des logs
+// getBuildInfo retourne les informations de build du binaire
+func getBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+}
+
+// writeVersion écrit les informations de version (texte ou objet JSON unique)
+func writeVersion(w io.Writer, asJSON bool) error {
+	info := getBuildInfo()
+	if asJSON {
+		return json.NewEncoder(w).Encode(info)
+	}
+	_, err := fmt.Fprintf(w, "Go MongoDB Scrapper\nVersion: %s\nGit Commit: %s\nBuild Time: %s\nGo Version: %s\nOS/Arch: %s/%s\n",
+		info.Version, info.GitCommit, info.BuildTime, info.GoVersion, info.OS, info.Arch)
+	return err
+}
 
 // userAgents contient une liste de User-Agents réalistes pour simuler différents navigateurs
 var userAgents = []string{
@@ -339,8 +643,8 @@ func configureRealisticHeaders(r *colly.Request) {
 
 	// Headers standards d'un navigateur moderne
 	r.Headers.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
-	r.Headers.Set("Accept-Language", "en-US,en;q=0.9,fr;q=0.8")
-	r.Headers.Set("Accept-Encoding", "gzip, deflate, br, zstd")
+	r.Headers.Set("Accept-Language", acceptLanguage(opts.Locale))
+	r.Headers.Set("Accept-Encoding", acceptedEncodings)
 	r.Headers.Set("DNT", "1")
 	r.Headers.Set("Connection", "keep-alive")
 	r.Headers.Set("Upgrade-Insecure-Requests", "1")
@@ -355,18 +659,151 @@ func configureRealisticHeaders(r *colly.Request) {
 	r.Headers.Set("sec-ch-ua-mobile", "?0")
 	r.Headers.Set("sec-ch-ua-platform", `"Windows"`)
 
-	// Ajouter un Referer réaliste
-	if r.URL != nil && r.URL.Host != "" {
-		// Pour la première visite, utiliser Google comme referer
-		if !strings.Contains(r.URL.String(), "allrecipes.com") || r.URL.Path == "/" {
-			r.Headers.Set("Referer", "https://www.google.com/")
-		} else {
-			// Pour les pages internes, utiliser le domaine comme referer
-			r.Headers.Set("Referer", "https://www.allrecipes.com/")
-		}
+	// Ajouter un Referer réaliste (désactivable avec -no-referer)
+	if opts.NoReferer {
+		r.Headers.Del("Referer")
 	} else {
-		// Referer par défaut pour la première visite
-		r.Headers.Set("Referer", "https://www.google.com/")
+		r.Headers.Set("Referer", realisticReferer(r.URL))
+	}
+
+	// En-têtes fournis par l'utilisateur, prioritaires sur les valeurs simulées
+	applyCustomHeaders(*r.Headers)
+}
+
+// applyCustomHeaders ajoute les en-têtes (-header) configurés
+// Les cookies (-cookie) sont dans le jar partagé (addCustomCookies), ajoutés ensuite par le client HTTP
+func applyCustomHeaders(headers http.Header) {
+	for _, header := range opts.Headers {
+		headers.Set(header.Name, header.Value)
+	}
+}
+
+// addCustomCookies range les cookies -cookie dans sharedCookieJar pour l'hôte de chaque URL de siteURLs
+// Comme les cookies de warm-up, ils ne sont envoyés qu'au site scrapé (pas au sitemap ni aux autres hôtes)
+// et sont transmis au navigateur headless
+func addCustomCookies(siteURLs ...string) {
+	if len(opts.Cookies) == 0 {
+		return
+	}
+	for _, siteURL := range siteURLs {
+		u, err := url.Parse(siteURL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		cookies := make([]*http.Cookie, 0, len(opts.Cookies))
+		for _, cookie := range opts.Cookies {
+			cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+		}
+		sharedCookieJar.SetCookies(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}, cookies)
+	}
+}
+
+// realisticReferer simule une navigation : arrivée depuis un moteur de recherche sur la page d'accueil,
+// puis navigation interne depuis l'accueil du même site pour les autres pages
+func realisticReferer(u *url.URL) string {
+	if u == nil || u.Host == "" || u.Path == "" || u.Path == "/" {
+		return searchReferer(opts.Locale)
+	}
+	return u.Scheme + "://" + u.Host + "/"
+}
+
+// defaultAcceptLanguage est l'en-tête Accept-Language historique (aucune -locale)
+const defaultAcceptLanguage = "en-US,en;q=0.9,fr;q=0.8"
+
+// acceptLanguage construit l'en-tête Accept-Language pour une locale (ex: fr-FR → fr-FR,fr;q=0.9,en;q=0.8)
+// Une valeur contenant déjà une liste (virgule) est utilisée telle quelle
+func acceptLanguage(locale string) string {
+	if locale == "" {
+		return defaultAcceptLanguage
+	}
+	if strings.Contains(locale, ",") {
+		return locale
+	}
+
+	language := strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0])
+	header := locale
+	if language != locale {
+		header += "," + language + ";q=0.9"
+	}
+	if language != "en" {
+		header += ",en;q=0.8"
+	}
+	return header
+}
+
+// googleDomains associe un pays (région de la locale) à son domaine Google
+var googleDomains = map[string]string{
+	"fr": "www.google.fr",
+	"be": "www.google.be",
+	"ch": "www.google.ch",
+	"ca": "www.google.ca",
+	"de": "www.google.de",
+	"es": "www.google.es",
+	"it": "www.google.it",
+	"gb": "www.google.co.uk",
+	"au": "www.google.com.au",
+}
+
+// searchReferer retourne le Referer de moteur de recherche correspondant au pays de la locale
+func searchReferer(locale string) string {
+	parts := strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)
+	if len(parts) == 2 {
+		if domain, ok := googleDomains[strings.ToLower(parts[1])]; ok {
+			return "https://" + domain + "/"
+		}
+	}
+	return "https://www.google.com/"
+}
+
+// sharedCookieJar est partagé par tous les collecteurs pour réutiliser les cookies de session
+var sharedCookieJar = newCookieJar()
+
+// newCookieJar crée un cookie jar vide
+func newCookieJar() *cookiejar.Jar {
+	jar, _ := cookiejar.New(nil) // cookiejar.New ne retourne jamais d'erreur sans options
+	return jar
+}
+
+// newCollector crée un collecteur partageant le cookie jar et borné par -request-timeout
+// Les requêtes sont faites avec ctx : annulées avec lui, leurs durées mesurées par requestTimings
+// sont comptées dans stats et remises aux autres destinataires de ctx (withRequestRecorder)
+// Sans timeout explicite, un serveur qui ne répond pas bloquerait la requête indéfiniment
+func newCollector(ctx context.Context, stats *ScrapingStats) *colly.Collector {
+	collector := colly.NewCollector()
+	collector.SetCookieJar(sharedCookieJar)
+	collector.SetRequestTimeout(opts.RequestTimeout)
+	collector.WithTransport(&contextTransport{ctx: withResponseTimes(ctx, stats), base: requestTimings})
+	return collector
+}
+
+// warmUp visite les pages d'accueil pour obtenir les cookies de session avant le scraping
+// Les cookies sont stockés dans sharedCookieJar et réutilisés par les autres collecteurs
+func warmUp(stats *ScrapingStats, urls []string, delay time.Duration) {
+	if len(urls) == 0 {
+		return
+	}
+
+	collector := newCollector(context.Background(), stats)
+	collector.OnRequest(func(r *colly.Request) {
+		configureRealisticHeaders(r)
+		stats.IncrementMainPageRequest()
+		logWarmupRequest(r.URL.String())
+	})
+	collector.OnResponse(func(r *colly.Response) {
+		stats.IncrementStatusCode(r.StatusCode)
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		stats.IncrementStatusCode(r.StatusCode)
+	})
+
+	for _, url := range urls {
+		if err := visitWithBudget(collector, url); err != nil {
+			logWarmupError(url, err)
+		}
+	}
+
+	if delay > 0 {
+		time.Sleep(delay)
 	}
 }
 
@@ -381,8 +818,9 @@ func getRandomDelay(minMs, maxMs int) time.Duration {
 
 // createMainCollector crée et configure le collecteur principal pour les pages de catégories
 // Ce collecteur visite les pages de listes de recettes et extrait les URLs des recettes individuelles
-func createMainCollector(stats *ScrapingStats, recipeURLs chan<- RecipeData) *colly.Collector {
-	collector := colly.NewCollector()
+// spillover: fichier des recettes trouvées quand recipeURLs est plein (nil = recettes ignorées)
+func createMainCollector(stats *ScrapingStats, recipeURLs chan<- RecipeData, spillover *spilloverWriter) *colly.Collector {
+	collector := newCollector(context.Background(), stats)
 
 	// Configuration des limites pour être respectueux du serveur
 	// Délais augmentés et parallélisme réduit pour éviter la détection
@@ -403,9 +841,14 @@ func createMainCollector(stats *ScrapingStats, recipeURLs chan<- RecipeData) *co
 		logRequest(r.URL.String(), stats.GetTotalRequests())
 	})
 
+	collector.OnResponse(func(r *colly.Response) {
+		stats.IncrementStatusCode(r.StatusCode)
+	})
+
 	// Gérer les erreurs HTTP (403, 429, etc.)
 	collector.OnError(func(r *colly.Response, err error) {
 		statusCode := r.StatusCode
+		stats.IncrementStatusCode(statusCode)
 		if statusCode == 403 || statusCode == 429 {
 			logInfo("⚠️  Erreur %d détectée pour %s: %v\n", statusCode, r.Request.URL, err)
 			logInfo("🔄 Attente prolongée avant retry (10-20s)...\n")
@@ -430,18 +873,14 @@ func createMainCollector(stats *ScrapingStats, recipeURLs chan<- RecipeData) *co
 
 			// Créer l'objet RecipeData avec les informations extraites
 			recipeData := RecipeData{
-				URL:   page,
-				Title: title,
-				Image: image,
+				URL:      page,
+				Title:    title,
+				Image:    image,
+				Category: categorySlug(e.Request.URL),
 			}
 
-			// Envoyer la recette dans le channel (non-bloquant)
-			select {
-			case recipeURLs <- recipeData:
-				logRecipeFound(stats.RecipesFound, title)
-			default:
-				logRecipeQueueFull(title)
-			}
+			// Envoyer la recette dans le channel (non-bloquant, débordement sur disque si plein)
+			enqueueRecipe(stats, recipeURLs, spillover, recipeData)
 		}
 	})
 
@@ -449,18 +888,27 @@ func createMainCollector(stats *ScrapingStats, recipeURLs chan<- RecipeData) *co
 }
 
 // createMainCollectorWithPagination crée un collecteur avec support de la pagination
-func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- RecipeData, maxPages int) *colly.Collector {
-	collector := colly.NewCollector()
+// lastModified: store des en-têtes Last-Modified pour les requêtes conditionnelles (nil = désactivé)
+// spillover: fichier des recettes trouvées quand recipeURLs est plein (nil = recettes ignorées)
+func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- RecipeData, maxPages int, lastModified *lastModifiedStore, spillover *spilloverWriter) *colly.Collector {
+	// Chaque réponse de page de catégorie est loggée avec sa durée réseau
+	collector := newCollector(withRequestRecorder(context.Background(), func(timing requestTiming) {
+		logResponse(timing.URL, timing.Duration, timing.Size)
+	}), stats)
 
 	// Configuration des limites avec délais plus longs pour éviter la détection
 	// Parallélisme réduit à 1 pour éviter la détection anti-bot
 	collector.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
-		Parallelism: 1,               // Réduit à 1 requête à la fois pour éviter la détection
-		Delay:       2 * time.Second, // Délai de base augmenté à 2 secondes
-		RandomDelay: 2 * time.Second, // Délai aléatoire jusqu'à 2 secondes (fonctionnalité native Colly)
+		Parallelism: 1, // Réduit à 1 requête à la fois pour éviter la détection
 	})
 
+	// Suspension de toutes les requêtes si le site bloque
+	siteBreaker.track(collector)
+
+	// Délai adaptatif propre au collecteur : 2s + jusqu'à 2s aléatoires, augmenté en cas de 403/429
+	newAdaptiveLimiter(adaptiveMinDelay, adaptiveMaxDelay, 2*time.Second).track(collector)
+
 	logConfig("Configuration des délais: 100ms entre chaque requête de page principale (respect du serveur)")
 	logConfig("Limite de parallélisme: 10 requêtes simultanées maximum pour éviter la surcharge")
 
@@ -468,29 +916,41 @@ func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- R
 	visitedPages := make(map[string]int)
 	var mutex sync.Mutex
 
-	var requestTimes = make(map[string]time.Time)
-	var requestTimesMutex sync.Mutex
-
 	collector.OnRequest(func(r *colly.Request) {
 		// Configurer les headers réalistes pour éviter la détection
 		configureRealisticHeaders(r)
 
-		// Les délais aléatoires sont gérés automatiquement par Colly via RandomDelay dans LimitRule
+		// Requête conditionnelle si la page a déjà été vue lors d'une exécution précédente
+		if lastModified != nil {
+			if since := lastModified.Get(r.URL.String()); since != "" {
+				r.Headers.Set("If-Modified-Since", since)
+			}
+		}
+
 		stats.IncrementMainPageRequest()
-		requestTimesMutex.Lock()
-		requestTimes[r.URL.String()] = time.Now()
-		requestTimesMutex.Unlock()
 		logRequest(r.URL.String(), stats.GetTotalRequests())
 	})
 
 	collector.OnResponse(func(r *colly.Response) {
-		requestTimesMutex.Lock()
-		startTime, exists := requestTimes[r.Request.URL.String()]
-		requestTimesMutex.Unlock()
-		if exists {
-			duration := time.Since(startTime)
-			logResponse(r.Request.URL.String(), duration, len(r.Body))
+		stats.IncrementStatusCode(r.StatusCode)
+
+		// Mémoriser la date de modification pour la prochaine exécution
+		if lastModified != nil {
+			if value := r.Headers.Get("Last-Modified"); value != "" {
+				lastModified.Set(r.Request.URL.String(), categorySlug(r.Request.URL), value)
+			}
+		}
+	})
+
+	// Une réponse 304 passe par OnError : la page est inchangée, ses recettes ne sont pas re-queuées
+	collector.OnError(func(r *colly.Response, err error) {
+		stats.IncrementStatusCode(r.StatusCode)
+		if r.StatusCode == http.StatusNotModified {
+			stats.IncrementPagesUnchanged()
+			logPageUnchanged(r.Request.URL.String())
+			return
 		}
+		logInfo("❌ Erreur HTTP %d pour %s: %v\n", r.StatusCode, r.Request.URL, err)
 	})
 
 	// Gérer les recettes sur la page actuelle
@@ -502,17 +962,17 @@ func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- R
 		if page != "" && title != "" {
 			stats.IncrementRecipesFound()
 			recipeData := RecipeData{
-				URL:   page,
-				Title: title,
-				Image: image,
+				URL:      page,
+				Title:    title,
+				Image:    image,
+				Category: categorySlug(e.Request.URL),
 			}
 
-			select {
-			case recipeURLs <- recipeData:
-				logRecipeFound(stats.RecipesFound, title)
-			default:
-				logRecipeQueueFull(title)
+			// La date de la page n'est retenue qu'une fois cette recette terminée
+			if lastModified != nil {
+				lastModified.RecipeQueued(recipeData.Category)
 			}
+			enqueueRecipe(stats, recipeURLs, spillover, recipeData)
 		}
 	})
 
@@ -544,7 +1004,7 @@ func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- R
 			// Visiter la page suivante avec un délai aléatoire plus long
 			randomDelay := getRandomDelay(2000, 5000) // Délai aléatoire entre 2s et 5s
 			time.Sleep(randomDelay)
-			collector.Visit(nextPageURL)
+			visitWithBudget(collector, nextPageURL)
 		} else {
 			logPaginationLimit(baseCategory, maxPages)
 		}
@@ -554,36 +1014,38 @@ func createMainCollectorWithPagination(stats *ScrapingStats, recipeURLs chan<- R
 }
 
 // createRecipeCollector crée un collecteur pour collecter une recette individuelle
-func createRecipeCollector(stats *ScrapingStats) *colly.Collector {
-	collector := colly.NewCollector()
+// Les requêtes sont faites avec ctx (voir newCollector)
+func createRecipeCollector(ctx context.Context, stats *ScrapingStats) *colly.Collector {
+	collector := newCollector(ctx, stats)
 
-	// Configuration avec délais plus longs pour éviter la détection
 	collector.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
 		Parallelism: 1,
-		Delay:       2 * time.Second, // Délai de base augmenté à 2 secondes
 	})
 
-	// Log explicatif pour les délais (seulement une fois)
-	_ = stats
+	// Suspension de toutes les requêtes si le site bloque, puis délai adaptatif partagé entre les recettes
+	siteBreaker.track(collector)
+	recipeLimiter.track(collector)
 
 	collector.OnRequest(func(r *colly.Request) {
 		// Configurer les headers réalistes pour éviter la détection
 		configureRealisticHeaders(r)
 
-		// Les délais aléatoires sont gérés automatiquement par Colly via RandomDelay dans LimitRule
 		stats.IncrementRecipeRequest()
 		logRecipeRequest(r.URL.String(), stats.GetTotalRequests())
 	})
 
+	collector.OnResponse(func(r *colly.Response) {
+		stats.IncrementStatusCode(r.StatusCode)
+	})
+
 	// Gérer les erreurs HTTP (403, 429, etc.)
 	collector.OnError(func(r *colly.Response, err error) {
 		statusCode := r.StatusCode
+		stats.IncrementStatusCode(statusCode)
 		if statusCode == 403 || statusCode == 429 {
+			// Le délai des requêtes suivantes est augmenté par recipeLimiter
 			logInfo("⚠️  Erreur %d détectée pour la recette %s: %v\n", statusCode, r.Request.URL, err)
-			logInfo("🔄 Attente prolongée avant retry (10-20s)...\n")
-			// Attendre beaucoup plus longtemps en cas d'erreur (10-20 secondes)
-			time.Sleep(getRandomDelay(10000, 20000))
 		} else {
 			logInfo("❌ Erreur HTTP %d pour la recette %s: %v\n", statusCode, r.Request.URL, err)
 		}
@@ -592,181 +1054,327 @@ func createRecipeCollector(stats *ScrapingStats) *colly.Collector {
 	return collector
 }
 
-// scrapeRecipeDetails configure les handlers pour collecter les détails d'une recette
-func scrapeRecipeDetails(collector *colly.Collector, recipe *Recipe, completedRecipes chan<- Recipe, stats *ScrapingStats) {
-	// Collecter les ingrédients - Nouveaux sélecteurs CSS pour AllRecipes 2024
-	collector.OnHTML("ul.mm-recipes-structured-ingredients__list", func(e *colly.HTMLElement) {
-		var ingredients []Ingredient
-
-		e.ForEach("li.mm-recipes-structured-ingredients__list-item", func(_ int, ingr *colly.HTMLElement) {
-			// Extraire la quantité et l'unité séparément
-			quantity := strings.TrimSpace(ingr.ChildText("span[data-ingredient-quantity=true]"))
-			unit := strings.TrimSpace(ingr.ChildText("span[data-ingredient-unit=true]"))
-			name := strings.TrimSpace(ingr.ChildText("span[data-ingredient-name=true]"))
-
-			// Si on a des données structurées, les utiliser
-			if quantity != "" || unit != "" || name != "" {
-				// Construire le texte complet de l'ingrédient
-				fullText := strings.TrimSpace(ingr.Text)
-				ingredients = append(ingredients, Ingredient{
-					Quantity: fullText, // Texte complet pour l'instant
-					Unit:     "",       // Pas de séparation pour l'instant
-				})
-			}
-		})
+// errEmptyRecipe signale une page de recette sans ingrédient, même après les nouvelles tentatives (-empty-retries)
+var errEmptyRecipe = errors.New("aucun ingrédient extrait de la page")
 
-		recipe.Ingredients = ingredients
-		logIngredientsFound(len(ingredients), recipe.Name)
-	})
+// recipeExtraction est le bilan de l'extraction d'une recette, lu par l'appelant après scrapeRecipeDetails
+type recipeExtraction struct {
+	Attempts   int   // Pages récupérées (nouvelles tentatives comprises), hors budget épuisé
+	Retries    int   // Nouvelles tentatives sur une extraction vide
+	StatusCode int   // Statut HTTP de la dernière réponse en erreur (0 si aucune)
+	Err        error // Erreur de récupération, errEmptyRecipe ou errParseRecipe (la recette n'est alors pas envoyée)
+}
+
+// scrapeRecipeDetails récupère la page de la recette par fetcher et en extrait les détails (parseRecipePage)
+// Une page qui répond sans ingrédient (rendu incomplet, variante A/B) est retéléchargée jusqu'à -empty-retries fois
+// Avec -debug-html, le HTML d'une page restée vide ou en erreur est sauvegardé dans debug/
+// Chaque téléchargement remplace les listes du précédent : une page re-tentée produit la même recette qu'un premier essai
+// extractor choisit la source des ingrédients et instructions : sélecteurs CSS, JSON-LD ou JSON-LD puis CSS (-extractor)
+// phases (optionnel) reçoit les durées HTTP et d'analyse
+func scrapeRecipeDetails(ctx context.Context, fetcher Fetcher, recipe *Recipe, extractor string, completedRecipes chan<- Recipe, stats *ScrapingStats, phases *recipePhases) *recipeExtraction {
+	extraction := &recipeExtraction{}
+	if phases == nil {
+		phases = &recipePhases{}
+	}
+	base, err := url.Parse(recipe.Page)
+	if err != nil {
+		extraction.Err = err
+		return extraction
+	}
 
-	// Collecter les instructions - Nouveaux sélecteurs CSS pour AllRecipes 2024
-	collector.OnHTML("div.mm-recipes-steps__content", func(e *colly.HTMLElement) {
-		var instructions []Instruction
-
-		// Chercher dans les listes ordonnées avec la structure correcte
-		e.ForEach("ol.mntl-sc-block li", func(i int, inst *colly.HTMLElement) {
-			number := strconv.Itoa(i + 1)
-			// Extraire le texte de la balise <p> à l'intérieur du <li>
-			description := strings.TrimSpace(inst.ChildText("p.mntl-sc-block-html"))
-			if description == "" {
-				// Fallback sur le texte complet si pas de balise p
-				description = strings.TrimSpace(inst.Text)
+	var parseErr error // Erreur d'analyse de la dernière réponse (errParseRecipe)
+	var lastBody []byte
+	for {
+		var httpDuration time.Duration
+		fetchStart := time.Now()
+		body, header, err := fetcher.Fetch(withHTTPDuration(ctx, &httpDuration), recipe.Page)
+		if errors.Is(err, errBudgetExhausted) && extraction.Attempts > 0 {
+			break // Plus de budget pour une nouvelle tentative : la recette reste vide
+		}
+		if err != nil {
+			if !errors.Is(err, errBudgetExhausted) {
+				extraction.Attempts++
 			}
-			if description != "" {
-				instructions = append(instructions, Instruction{
-					Number:      number,
-					Description: description,
-				})
+			// Page en erreur (statut HTTP, timeout) : conserver ce que le serveur a renvoyé (-debug-html)
+			var fetchErr *fetchError
+			if errors.As(err, &fetchErr) {
+				extraction.StatusCode = fetchErr.StatusCode
+				saveDebugHTML(recipe.Page, fetchErr.Body)
 			}
-		})
+			extraction.Err = err
+			return extraction
+		}
+		extraction.Attempts++
+		if httpDuration == 0 {
+			httpDuration = time.Since(fetchStart) // Fetcher sans mesure réseau propre
+		}
+		phases.HTTP += httpDuration
+
+		parseStart := time.Now()
+		parseErr = parseRecipeResponse(recipe, body, header, base, extractor)
+		phases.Parse += time.Since(parseStart)
+		lastBody = body
+		if len(recipe.Ingredients) > 0 {
+			break
+		}
 
-		recipe.Instructions = instructions
-		logInstructionsFound(len(instructions), recipe.Name)
-	})
+		// Extraction vide : retélécharger la page (dans la limite du budget) avant de l'accepter
+		if extraction.Retries >= opts.EmptyRetries {
+			break
+		}
+		extraction.Retries++
+		logEmptyRecipeRetry(recipe.Page, extraction.Retries, opts.EmptyRetries)
+	}
 
-	// Quand la collecte de la recette est terminée
-	collector.OnScraped(func(r *colly.Response) {
-		stats.IncrementRecipesCompleted()
-		completedRecipes <- *recipe
-		logRecipeCompleted(stats.RecipesCompleted, recipe.Name)
-	})
+	if len(recipe.Ingredients) == 0 {
+		extraction.Err = errEmptyRecipe
+		if parseErr != nil {
+			extraction.Err = parseErr
+		}
+		saveDebugHTML(recipe.Page, lastBody)
+		return extraction
+	}
+
+	if opts.DetectLanguage {
+		recipe.Language = detectRecipeLanguage(*recipe)
+	}
+	stats.IncrementRecipesCompleted()
+	completedRecipes <- *recipe
+	logRecipeCompleted(stats.RecipesCompleted, recipe.Name)
+	return extraction
+}
+
+// parseRecipeResponse extrait une réponse HTML dans recipe, en remplaçant les listes précédentes
+// Retourne une erreur errParseRecipe si la réponse n'est pas du HTML lisible
+func parseRecipeResponse(recipe *Recipe, body []byte, header http.Header, base *url.URL, extractor string) error {
+	recipe.Ingredients = nil
+	recipe.Instructions = nil
+	recipe.Tags, recipe.Cuisine, recipe.Course = nil, "", ""
+	jsonLDDetails{}.apply(recipe)
+	if contentType := header.Get("Content-Type"); !strings.Contains(strings.ToLower(contentType), "html") {
+		return fmt.Errorf("%w: réponse %q au lieu de HTML", errParseRecipe, contentType)
+	}
+	parsed, err := parseRecipePage(body, base, extractor)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errParseRecipe, err)
+	}
+
+	// Nom et image de la page, si la carte de la catégorie ne les a pas fournis (ex: sitemap, -selftest)
+	if recipe.Name == "" {
+		recipe.Name = parsed.Name
+	}
+	if recipe.Image == "" {
+		recipe.Image = parsed.Image
+	}
+
+	recipe.Ingredients = parsed.Ingredients
+	recipe.Instructions = parsed.Instructions
+	recipe.Tags = parsed.Tags
+	recipe.Cuisine = parsed.Cuisine
+	recipe.Course = parsed.Course
+	recipe.PrepTimeMinutes, recipe.CookTimeMinutes, recipe.TotalTimeMinutes = parsed.PrepTimeMinutes, parsed.CookTimeMinutes, parsed.TotalTimeMinutes
+	recipe.Nutrition, recipe.Rating = parsed.Nutrition, parsed.Rating
+	logIngredientsFound(len(recipe.Ingredients), recipe.Name)
+	logInstructionsFound(len(recipe.Instructions), recipe.Name)
+	return nil
 }
 
 // processRecipeReusable traite une recette dans un worker réutilisable
-func processRecipeReusable(recipeData RecipeData, stats *ScrapingStats, completedRecipes chan<- Recipe, workerStats *WorkerStats) {
+// fetcher récupère la page (collyFetcher hors tests) ; monitor (optionnel) reçoit le heartbeat du worker
+// et peut annuler la requête en cours
+func processRecipeReusable(recipeData RecipeData, fetcher Fetcher, stats *ScrapingStats, completedRecipes chan<- Recipe, workerStats *WorkerStats, monitor *workerMonitor, failed *failedRecipes) {
 	startTime := time.Now()
 	logWorkerStart(workerStats.WorkerID, recipeData.Title)
 	logWorkerSteps()
 
-	// Créer un collecteur dédié pour cette recette
-	recipeCollector := createRecipeCollector(stats)
+	// Contexte propre à cette recette, annulable par le superviseur
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	recipe := Recipe{
-		Name:  recipeData.Title,
-		Page:  recipeData.URL,
-		Image: recipeData.Image,
+	if monitor != nil {
+		monitor.Begin(workerStats.WorkerID, recipeData.URL, cancel)
+		defer monitor.End(workerStats.WorkerID)
 	}
 
-	// Configurer la collecte des détails
-	scrapeRecipeDetails(recipeCollector, &recipe, completedRecipes, stats)
+	recipe := Recipe{
+		Name:     recipeData.Title,
+		Page:     recipeData.URL,
+		Image:    recipeData.Image,
+		Category: recipeData.Category,
+	}
 
-	// Visiter la page de la recette
-	httpStart := time.Now()
-	err := recipeCollector.Visit(recipeData.URL)
-	httpDuration := time.Since(httpStart)
+	// Récupérer la page et extraire les détails (tentatives et dernier statut HTTP reportés dans failed.json)
+	var phases recipePhases
+	extraction := scrapeRecipeDetails(ctx, fetcher, &recipe, opts.Extractor, completedRecipes, stats, &phases)
+	err := extraction.Err // Page vide après les nouvelles tentatives : comptée en échec
 
+	if errors.Is(err, errBudgetExhausted) {
+		// Budget épuisé : la recette n'est pas visitée, les workers vident la file sans requête
+		stats.IncrementRecipesSkipped()
+		return
+	}
+	// Chaque recette visitée est comptée une seule fois, par le worker qui l'a traitée,
+	// avec toutes ses requêtes (nouvelles tentatives comprises), qu'elle aboutisse ou non
+	workerStats.RequestsHandled += int64(extraction.Attempts)
 	if err != nil {
-		stats.IncrementRecipesFailed()
+		failure := classifyFailure(err)
+		stats.RecordFailure(failure)
+		failed.Add(FailedRecipe{
+			URL:        recipeData.URL,
+			Title:      recipeData.Title,
+			Category:   recipeData.Category,
+			Error:      err.Error(),
+			Failure:    failure,
+			StatusCode: extraction.StatusCode,
+			Attempts:   extraction.Attempts,
+		})
 		logWorkerError(workerStats.WorkerID, recipeData.Title, err)
 	} else {
-		// Mettre à jour les stats du worker
-		workerStats.RequestsHandled++
 		workerStats.RecipesProcessed++
-		logWorkerHTTPComplete(httpDuration)
+		logWorkerHTTPComplete(phases.HTTP)
 	}
 
+	workerStats.HTTPDuration += phases.HTTP
+	workerStats.ParseDuration += phases.Parse
+	stats.AddPhaseDurations(phases.HTTP, phases.Parse)
+
 	duration := time.Since(startTime)
-	logWorkerComplete(workerStats.WorkerID, duration, httpDuration, recipeData.Title)
+	logWorkerComplete(workerStats.WorkerID, duration, phases.HTTP, phases.Parse, recipeData.Title)
 }
 
 // startRecipeProcessor démarre la goroutine qui traite les URLs de recettes
-func startRecipeProcessor(recipeURLs <-chan RecipeData, completedRecipes chan<- Recipe, stats *ScrapingStats, wg *sync.WaitGroup) {
+// failed: collecteur des recettes en échec (nil = non collectées)
+func startRecipeProcessor(recipeURLs <-chan RecipeData, completedRecipes chan<- Recipe, stats *ScrapingStats, wg *sync.WaitGroup, failed *failedRecipes) {
 	go func() {
 		maxWorkers := stats.MaxWorkers // Utiliser le nombre optimal calculé automatiquement
-		semaphore := make(chan struct{}, maxWorkers)
-
-		logWorkerInit(maxWorkers)
-
-		// Créer des workers réutilisables
-		for i := 0; i < maxWorkers; i++ {
-			wg.Add(1)
-			go func(workerID int) {
-				defer wg.Done()
-				workerStats := WorkerStats{
-					WorkerID:         workerID,
-					RequestsHandled:  0,
-					RecipesProcessed: 0,
-					StartTime:        time.Now(),
-				}
-
-				logWorkerStarted(workerID)
-
-				// Le worker traite les recettes en continu
-				for recipeData := range recipeURLs {
-					// Log de la queue
-					queueLength := len(recipeURLs)
-					logWorkerQueue(workerID, queueLength)
-
-					// Acquérir un slot dans le semaphore
-					semaphore <- struct{}{}
 
-					// Traiter la recette
-					processRecipeReusable(recipeData, stats, completedRecipes, &workerStats)
+		// Superviseur des heartbeats : signale et débloque les workers bloqués
+		monitor := newWorkerMonitor()
+		stopSupervisor := make(chan struct{})
+		go monitor.supervise(heartbeatInterval, opts.StuckThreshold, stopSupervisor)
 
-					// Libérer le slot
-					<-semaphore
-				}
-
-				// Mettre à jour les stats finales du worker
-				workerStats.EndTime = time.Now()
-				workerStats.Duration = workerStats.EndTime.Sub(workerStats.StartTime)
-				stats.Mutex.Lock()
-				stats.WorkerStats[workerID] = workerStats
-				stats.Mutex.Unlock()
+		logWorkerInit(maxWorkers)
 
-				logWorkerFinished(workerID, workerStats.RequestsHandled, workerStats.RecipesProcessed, workerStats.Duration)
-			}(i)
+		// Au plus maxWorkers recettes sont traitées en même temps, quel que soit le modèle. Chaque recette
+		// a son collecteur et n'envoie qu'une requête à la fois : c'est aussi la limite des requêtes
+		// sortantes vers les pages de recettes (le collecteur principal a la sienne, Parallelism: 3)
+		// Les deux modèles partagent processRecipeReusable et ne rendent la main qu'une fois la file vidée
+		fetcher := newCollyFetcher(stats)
+		process := func(recipeData RecipeData, workerStats *WorkerStats) {
+			processRecipeReusable(recipeData, fetcher, stats, completedRecipes, workerStats, monitor, failed)
+		}
+		if opts.ConcurrencyModel == concurrencySemaphore {
+			runRecipeSemaphore(recipeURLs, stats, wg, maxWorkers, process)
+		} else {
+			runRecipePool(recipeURLs, stats, wg, maxWorkers, process)
 		}
 
-		logWorkersReady(maxWorkers)
-
-		// Attendre que toutes les goroutines se terminent
-		wg.Wait()
+		close(stopSupervisor)
 		close(completedRecipes)
 		logAllWorkersFinished(maxWorkers)
 	}()
 }
 
 // startRecipeCollector démarre la goroutine qui collecte les recettes terminées
-func startRecipeCollector(completedRecipes <-chan Recipe, recipes *[]Recipe, recipesMutex *sync.RWMutex, done chan<- bool) {
+// stream: sortie des recettes au fil de l'eau (-output -), nil = sauvegarde en fin d'exécution uniquement
+// quarantine: recettes incomplètes écartées de la sortie (nil = toutes les recettes sont gardées)
+// Une recette acceptée déjà collectée sous le même identifiant (recipeID, ex: trouvée dans deux catégories) est ignorée
+func startRecipeCollector(completedRecipes <-chan Recipe, recipes *[]Recipe, recipesMutex *sync.RWMutex, done chan<- bool, stream *RecipeWriter, quarantine *recipeQuarantine) {
 	go func() {
+		seen := make(map[string]bool)
 		for recipe := range completedRecipes {
+			if !quarantine.Accept(recipe) {
+				continue
+			}
+			recipe.RecipeID = recipeID(recipe.Page)
+			if recipe.RecipeID != "" {
+				if seen[recipe.RecipeID] {
+					logDuplicateRecipe(recipe.Name, recipe.Page)
+					continue
+				}
+				seen[recipe.RecipeID] = true
+			}
+
 			recipesMutex.Lock()
 			*recipes = append(*recipes, recipe)
 			recipesMutex.Unlock()
+
+			if stream != nil {
+				if err := stream.Write(recipe); err != nil {
+					logStreamError(err)
+					stream = nil // Le lecteur est parti : inutile de continuer à écrire
+				}
+			}
 		}
 		done <- true
 	}()
 }
 
-// saveRecipesToFile sauvegarde les recettes dans un fichier JSON
-func saveRecipesToFile(recipes []Recipe, filename string) error {
-	content, err := json.MarshalIndent(recipes, "", "  ")
+// saveRecipesToFile sauvegarde les recettes dans un fichier au format choisi (-format)
+// dir: répertoire de sortie créé si nécessaire (vide = répertoire courant)
+// {timestamp} dans filename est remplacé par l'horodatage de la sauvegarde ; le nom écrit est retourné
+func saveRecipesToFile(recipes []Recipe, dir, filename string) (string, error) {
+	var buf bytes.Buffer
+	writer := NewRecipeWriter(&buf, opts.Format, opts.Compact)
+	for _, recipe := range recipes {
+		if err := writer.Write(recipe); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	filename = expandOutputPattern(filename, time.Now())
+	return filename, writeOutputFile(dir, filename, buf.Bytes())
+}
+
+// encodeRecipes sérialise les recettes en JSON indenté, ou compact avec une recette par ligne (-compact)
+// L'ordre des recettes et de leurs ingrédients est conservé : deux exécutions identiques donnent les mêmes octets
+func encodeRecipes(recipes []Recipe, compact bool) ([]byte, error) {
+	if !compact {
+		return json.MarshalIndent(recipes, "", "  ")
+	}
+	if len(recipes) == 0 {
+		return json.Marshal(recipes)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for i, recipe := range recipes {
+		line, err := json.Marshal(recipe)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		if i < len(recipes)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("]\n")
+	return buf.Bytes(), nil
+}
+
+// saveStatsToFile sauvegarde les statistiques détaillées de l'exécution dans le répertoire de sortie
+func saveStatsToFile(stats *ScrapingStats, dir, filename string) error {
+	detailedStats := stats.GetDetailedStats()
+	content, err := json.MarshalIndent(&detailedStats, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filename, content, 0644)
+	return writeOutputFile(dir, filename, content)
+}
+
+// writeOutputFile écrit un fichier dans le répertoire de sortie en le créant au besoin
+func writeOutputFile(dir, filename string, content []byte) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, filename), content, 0644)
 }
 
 // printDetailedStats affiche les statistiques détaillées
@@ -778,29 +1386,39 @@ func printDetailedStats(stats *ScrapingStats, filename string) {
 	logDetailedStatsPerformance(detailedStats.TotalDuration, detailedStats.RequestsPerSecond, detailedStats.RecipesPerSecond)
 
 	// Requêtes
-	logDetailedStatsRequests(detailedStats.TotalRequests, detailedStats.MainPageRequests, detailedStats.RecipeRequests)
+	logDetailedStatsRequests(detailedStats.TotalRequests, detailedStats.MainPageRequests, detailedStats.RecipeRequests, detailedStats.PagesUnchanged)
+	logDetailedStatsStatusCodes(detailedStats.StatusCodes)
+	logDetailedStatsLatency(detailedStats.LatencyP50, detailedStats.LatencyP90, detailedStats.LatencyP99)
 
 	// Recettes
-	successRate := float64(detailedStats.RecipesCompleted) / float64(detailedStats.RecipesFound) * 100
-	logDetailedStatsRecipes(detailedStats.RecipesFound, detailedStats.RecipesCompleted, detailedStats.RecipesFailed, successRate)
+	// Une exécution entièrement bloquée ne trouve aucune recette : taux nul plutôt que NaN
+	successRate := 0.0
+	if detailedStats.RecipesFound > 0 {
+		successRate = float64(detailedStats.RecipesCompleted) / float64(detailedStats.RecipesFound) * 100
+	}
+	logDetailedStatsRecipes(detailedStats.RecipesFound, detailedStats.RecipesCompleted, detailedStats.RecipesFailed, detailedStats.RecipesInvalid, successRate)
+	logDetailedStatsFailures(detailedStats.FailedHTTP, detailedStats.FailedTimeout, detailedStats.FailedEmpty, detailedStats.FailedParse)
+	logDetailedStatsPhases(detailedStats.TotalHTTPDuration, detailedStats.TotalParseDuration, detailedStats.RecipesCompleted)
+	logDetailedStatsRuntime(detailedStats.PeakHeapBytes, detailedStats.PeakGoroutines)
 
 	// Configuration automatique
-	numLogicalCPU := runtime.NumCPU()
-	numPhysicalCores := getPhysicalCores()
-	adaptiveRatio := calculateAdaptiveRatio(numPhysicalCores)
-	calculatedWorkers := numPhysicalCores * adaptiveRatio
-	logDetailedStatsConfig(numLogicalCPU, numPhysicalCores, adaptiveRatio, calculatedWorkers, detailedStats.MaxWorkers)
+	sizing := sizeWorkers(runtime.NumCPU(), getPhysicalCores(), 1, detailedStats.MaxWorkers, "")
+	logDetailedStatsConfig(sizing.LogicalCPU, sizing.PhysicalCores, sizing.AdaptiveRatio, sizing.Calculated, detailedStats.MaxWorkers)
 
 	// Détails par worker
-	if len(detailedStats.WorkerStats) > 0 {
+	if workers := detailedStats.SortedWorkerStats(); len(workers) > 0 {
 		logDetailedStatsWorkersHeader()
-		for workerID, workerStats := range detailedStats.WorkerStats {
-			logDetailedStatsWorker(workerID, workerStats.RequestsHandled, workerStats.RecipesProcessed, workerStats.Duration)
+		for _, workerStats := range workers {
+			logDetailedStatsWorker(workerStats.WorkerID, workerStats.RequestsHandled, workerStats.RecipesProcessed, workerStats.Duration)
 		}
 	}
 
 	// Calculs de performance
-	avgRequestsPerRecipe := float64(detailedStats.RecipeRequests) / float64(detailedStats.RecipesCompleted)
+	// Négatif quand aucune recette n'est complétée : la moyenne est affichée « n/a »
+	avgRequestsPerRecipe := -1.0
+	if detailedStats.RecipesCompleted > 0 {
+		avgRequestsPerRecipe = float64(detailedStats.RecipeRequests) / float64(detailedStats.RecipesCompleted)
+	}
 	avgTimePerRecipe := 0.0
 	if detailedStats.RecipesPerSecond > 0 {
 		avgTimePerRecipe = 1 / detailedStats.RecipesPerSecond
@@ -810,25 +1428,164 @@ func printDetailedStats(stats *ScrapingStats, filename string) {
 	logDetailedStatsFooter(filename)
 }
 
-// printRealTimeStats affiche les statistiques en temps réel (désactivé pour réduire la verbosité)
-func printRealTimeStats(stats *ScrapingStats) {
+// exitLowSuccessRate est le code de sortie quand le taux de succès est sous -min-success-rate
+// (1 est réservé aux erreurs d'exécution, 2 aux options invalides)
+const exitLowSuccessRate = 3
+
+// successRateExitCode vérifie le taux de succès (RecipesCompleted/RecipesFound) après CalculateFinalStats
+// Retourne 0 si minRate n'est pas défini ou est atteint, exitLowSuccessRate sinon
+// Aucune recette trouvée compte comme un taux nul : les sélecteurs de catégories sont probablement cassés,
+// sauf si toutes les pages ont répondu 304 (-if-modified-since)
+func successRateExitCode(stats *ScrapingStats, minRate float64) int {
+	if minRate <= 0 || stats.AllPagesUnchanged() {
+		return 0
+	}
+
+	// Les recettes non visitées faute de budget (-max-requests) ne comptent pas comme des échecs
+	stats.Mutex.RLock()
+	found, completed := stats.RecipesFound-stats.RecipesSkipped, stats.RecipesCompleted
+	stats.Mutex.RUnlock()
+
+	rate := 0.0
+	if found > 0 {
+		rate = float64(completed) / float64(found)
+	}
+	if rate >= minRate {
+		return 0
+	}
+	logSuccessRateTooLow(rate, minRate)
+	return exitLowSuccessRate
+}
+
+// printRealTimeStats affiche une ligne de progression rafraîchie sur stderr (-progress)
+// Retourne la fonction d'arrêt, qui termine la ligne par un retour à la ligne
+func printRealTimeStats(stats *ScrapingStats) func() {
+	if !opts.Progress {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				writeProgress(os.Stderr, stats)
+				fmt.Fprintln(os.Stderr)
+				return
+			case <-ticker.C:
+				writeProgress(os.Stderr, stats)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
 }
 
 // main est la fonction principale du collecteur
 // Elle orchestre tout le processus de collecte : collecte des URLs, traitement des recettes, et sauvegarde
 func main() {
+	// Lecture des options de ligne de commande
+	parsedOptions, err := parseOptions(os.Args[1:], os.Stderr)
+	if err != nil {
+		os.Exit(2)
+	}
+	opts = parsedOptions
+
+	// -version : afficher les informations de build et quitter
+	if opts.Version {
+		if err := writeVersion(os.Stdout, opts.JSON); err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur d'affichage de la version: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// -http2, -tls-min et -headless s'appliquent aussi à -selftest, -recipe et -category
+	configureTransport()
+	configureHeadless()
+
+	// -selftest : vérifier les sélecteurs sur une page de recette et quitter
+	if opts.Selftest != "" {
+		os.Exit(runSelftest(opts.Selftest, os.Stdout))
+	}
+
+	// -recipe : scraper une seule recette et l'écrire en JSON sur stdout
+	if opts.RecipeURL != "" {
+		os.Exit(runSingleRecipe(opts.RecipeURL, os.Stdout, os.Stderr))
+	}
+
+	// -category : parcourir une seule catégorie et écrire ses recettes en NDJSON sur stdout
+	if opts.CategoryURL != "" {
+		os.Exit(runCategory(opts.CategoryURL, opts.CategoryPages, os.Stdout, os.Stderr))
+	}
+
 	// ===== PHASE 0: INITIALISATION DU LOGGING =====
 	// Initialiser le système de logging vers un fichier
-	if err := initLogger(); err != nil {
+	if err := initLogger(opts.OutputDir); err != nil {
 		fmt.Fprintf(os.Stderr, "Erreur d'initialisation du logging: %v\n", err)
 		os.Exit(1)
 	}
 	defer closeLogger()
 
+	// Fichier de configuration (catégories), partagé avec l'API qui peut le modifier
+	// Chargé après le logger pour que les avertissements (variables d'environnement absentes) soient loggés
+	configs, err := newConfigWatcher(opts.ConfigPath, opts.WatchConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erreur de chargement de la configuration: %v\n", err)
+		closeLogger() // os.Exit n'exécute pas les defer
+		os.Exit(2)
+	}
+
 	// ===== PHASE 1: INITIALISATION =====
 	// Afficher les informations de version et de build
 	printVersionInfo()
 
+	// -schedule : le processus reste actif et lance une exécution à chaque échéance, dans un fichier horodaté
+	if opts.Schedule != "" {
+		err := runSchedule(opts.Schedule, func() {
+			runScrape(configs, timestampedFilename(opts.Output, time.Now()))
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur de planification: %v\n", err)
+			closeLogger() // os.Exit n'exécute pas les defer
+			os.Exit(2)
+		}
+		return
+	}
+
+	// -min-success-rate : signaler un taux de succès insuffisant par le code de sortie (CI)
+	if code := runScrape(configs, opts.Output); code != 0 {
+		closeLogger() // os.Exit n'exécute pas les defer
+		os.Exit(code)
+	}
+}
+
+// resetRunState remet à neuf l'état partagé par les collecteurs au début d'une exécution :
+// budget de requêtes (-max-requests, 0 = illimité), pages de -debug-html, disjoncteur et délai adaptatif
+// Avec -schedule, un blocage observé par une exécution ne ralentit pas la suivante
+func resetRunState() {
+	crawlBudget = newRequestBudget(opts.MaxRequests)
+	configureDebugHTML()
+	siteBreaker = newCircuitBreaker(circuitWindow, circuitMinSamples, circuitThreshold, circuitCooldown)
+	recipeLimiter = newAdaptiveLimiter(adaptiveMinDelay, adaptiveMaxDelay, 0)
+}
+
+// runScrape exécute une collecte complète et sauvegarde les recettes dans output (relatif à -output-dir)
+// Chaque exécution repart de statistiques et d'un état partagé neufs (resetRunState)
+// Retourne le code de sortie : 1 si la sauvegarde échoue, celui demandé par -min-success-rate sinon
+// (0 si le taux est suffisant)
+func runScrape(configs *configWatcher, output string) int {
+	var err error
+
+	resetRunState()
+
 	// Configuration du collecteur - paramètres ajustables
 	const minWorkers = 1          // Nombre minimum de workers
 	const maxWorkers = 100        // Nombre maximum de workers
@@ -836,19 +1593,44 @@ func main() {
 	const maxRecipesPerPage = 20  // Estimation du nombre de recettes par page
 
 	// Configuration automatique basée sur les ressources CPU
-	optimalWorkers := calculateOptimalWorkers(minWorkers, maxWorkers)
+	sizing := resolveWorkerSizing(minWorkers, maxWorkers)
+	if sizing.OverrideError != "" {
+		logWorkerOverrideError(sizing.OverrideError)
+	}
+	if opts.DebugWorkers {
+		logWorkerSizing(sizing)
+	}
+	optimalWorkers := sizing.Workers
 
 	// Créer l'objet de statistiques thread-safe
 	stats := NewScrapingStats(optimalWorkers)
 
-	// Démarrer l'affichage des statistiques en temps réel (désactivé pour réduire la verbosité)
-	printRealTimeStats(stats)
+	// Démarrer l'affichage de la progression en temps réel (-progress)
+	stopProgress := printRealTimeStats(stats)
+
+	// Relever les pics de mémoire et de goroutines pendant l'exécution (fuites de collecteurs...)
+	stopSampler := stats.StartRuntimeSampler(runtimeSampleInterval)
+
+	// Partager la progression avec l'API (GET /scraper/status) jusqu'à la fin de l'exécution
+	stopStatus := startStatusWriter(stats, filepath.Join(opts.OutputDir, statusFilename), statusInterval)
+	defer stopStatus()
+
+	// Catégories lues depuis le fichier de configuration (-config), liste par défaut s'il est absent
+	// Avec -watch-config, le fichier est relu au début de chaque exécution
+	config := configs.Current()
+
+	// Cookies -cookie pour les sites des catégories et du warm-up, avant la première requête
+	addCustomCookies(append(append([]string(nil), config.Categories...), opts.WarmupURLs...)...)
+
+	// Visites de warm-up pour obtenir les cookies de session partagés par les collecteurs
+	warmUp(stats, opts.WarmupURLs, opts.WarmupDelay)
 
 	// ===== PHASE 2: CONFIGURATION DES CHANNELS =====
 	// Channels pour la communication entre goroutines (pipeline de données)
-	recipeURLs := make(chan RecipeData, 2000)   // Channel pour les URLs de recettes (buffer de 2000)
-	completedRecipes := make(chan Recipe, 2000) // Channel pour les recettes complétées (buffer de 2000)
-	done := make(chan bool)                     // Channel de signalisation de fin
+	// Des files plus petites réduisent la mémoire au prix de plus d'attente (-url-buffer, -recipe-buffer)
+	recipeURLs := make(chan RecipeData, opts.URLBuffer)      // Channel pour les URLs de recettes
+	completedRecipes := make(chan Recipe, opts.RecipeBuffer) // Channel pour les recettes complétées
+	done := make(chan bool)                                  // Channel de signalisation de fin
 
 	// Slice thread-safe pour stocker toutes les recettes finales
 	var recipes []Recipe
@@ -858,65 +1640,118 @@ func main() {
 	var wg sync.WaitGroup
 
 	// ===== PHASE 3: CONFIGURATION DES COLLECTEURS =====
+	// Charger les dates de modification connues si les requêtes conditionnelles sont activées
+	lastModifiedPath := filepath.Join(opts.OutputDir, lastModifiedFilename)
+	var lastModified *lastModifiedStore
+	if opts.IfModifiedSince {
+		lastModified, err = loadLastModifiedStore(lastModifiedPath)
+		if err != nil {
+			logInfo("⚠️  Impossible de charger %s, requêtes non conditionnelles: %v\n", lastModifiedPath, err)
+			lastModified = nil
+		}
+	}
+
+	// Recettes trouvées quand le channel est plein : mises de côté pour une reprise avec -resume
+	spilloverPath := filepath.Join(opts.OutputDir, spilloverFilename)
+	var resumed []RecipeData
+	spillover := newSpilloverWriter(spilloverPath)
+	if opts.Resume {
+		// Les recettes qui débordent pendant la reprise sont écrites à part : le fichier repris n'est
+		// remplacé qu'après la sauvegarde, un arrêt avant permet de reprendre à nouveau
+		var resumeWriter *spilloverWriter
+		resumed, resumeWriter, err = resumeSpillover(spilloverPath)
+		if err != nil {
+			logInfo("⚠️  Impossible de lire %s, pas de reprise: %v\n", spilloverPath, err)
+			resumed = nil
+		} else {
+			spillover = resumeWriter
+		}
+	}
+
 	// Créer le collecteur principal avec support de la pagination
-	mainCollector := createMainCollectorWithPagination(stats, recipeURLs, maxPagesPerCategory)
+	mainCollector := createMainCollectorWithPagination(stats, recipeURLs, maxPagesPerCategory, lastModified, spillover)
 
 	// ===== PHASE 4: DÉMARRAGE DES GOROUTINES DE TRAITEMENT =====
 	// Démarrer la goroutine qui collecte les recettes terminées
-	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done)
+	// -output - : les recettes sont écrites sur stdout dès qu'elles sont terminées
+	var stream *RecipeWriter
+	if output == stdoutOutput {
+		stream = NewRecipeWriter(os.Stdout, opts.Format, opts.Compact)
+	}
+	// Recettes sans les champs obligatoires de la configuration : écrites dans invalid.json plutôt que dans la sortie
+	quarantine := newRecipeQuarantine(config.RequiredFields, stats)
+	collected := (<-chan Recipe)(completedRecipes)
+	if lastModified != nil {
+		collected = lastModified.trackCompleted(completedRecipes)
+	}
+	startRecipeCollector(collected, &recipes, &recipesMutex, done, stream, quarantine)
 
 	// Démarrer les workers qui traitent les URLs de recettes
-	startRecipeProcessor(recipeURLs, completedRecipes, stats, &wg)
+	failed := &failedRecipes{}
+	startRecipeProcessor(recipeURLs, completedRecipes, stats, &wg, failed)
+
+	// -resume : traiter d'abord les recettes mises de côté par l'exécution précédente
+	if len(resumed) > 0 {
+		logResume(len(resumed), spilloverPath)
+		for _, recipeData := range resumed {
+			stats.IncrementRecipesFound()
+			if lastModified != nil {
+				lastModified.RecipeQueued(recipeData.Category)
+			}
+			recipeURLs <- recipeData // Bloquant : les workers consomment en parallèle
+		}
+	}
 
 	// ===== PHASE 5: DÉFINITION DES CATÉGORIES À SCRAPER =====
-	// Liste des catégories de recettes AllRecipes à scraper
 	// Chaque catégorie sera visitée avec pagination automatique
-	categories := []string{
-		"https://www.allrecipes.com/recipes/16369/soups-stews-and-chili/soup/",               // Soupes
-		"https://www.allrecipes.com/recipes/1246/soups-stews-and-chili/soup/chicken-soup/",   // Soupes de poulet
-		"https://www.allrecipes.com/recipes/76/appetizers-and-snacks/",                       // Apéritifs et collations
-		"https://www.allrecipes.com/recipes/113/appetizers-and-snacks/pastries/",             // Pâtisseries
-		"https://www.allrecipes.com/recipes/1059/fruits-and-vegetables/vegetables/",          // Légumes
-		"https://www.allrecipes.com/recipes/1083/fruits-and-vegetables/vegetables/cucumber/", // Concombres
-		"https://www.allrecipes.com/recipes/77/drinks/",                                      // Boissons
-		"https://www.allrecipes.com/recipes/79/desserts/",                                    // Desserts
-		"https://www.allrecipes.com/recipes/81/side-dish/",                                   // Accompagnements
-		"https://www.allrecipes.com/recipes/1569/everyday-cooking/on-the-go/tailgating/",     // Tailgating
-	}
+	categories := config.Categories
 
 	// ===== PHASE 6: EXÉCUTION DU SCRAPING =====
-	// Démarrer le scraping de toutes les catégories définies
-	categoryStartTime := time.Now()
-	logScrapingStart(len(categories))
-	estimatedPages := len(categories) * maxPagesPerCategory
-	estimatedRecipes := len(categories) * maxPagesPerCategory * maxRecipesPerPage
-	estimatedSeconds := (estimatedPages*100 + estimatedRecipes*50) / 1000
-	logScrapingEstimate(estimatedPages, estimatedRecipes, estimatedSeconds)
-
-	for i, category := range categories {
-		categoryPhaseStart := time.Now()
-		logCategoryStart(i+1, len(categories), category)
-		logCategoryInfo(maxPagesPerCategory, maxRecipesPerPage)
-
-		// Visiter la catégorie (avec pagination automatique)
-		err := mainCollector.Visit(category)
-		if err != nil {
-			logCategoryError(category, err)
-			continue // Continuer avec la catégorie suivante en cas d'erreur
+	if opts.Sitemap != "" {
+		// Mode sitemap : les URLs de recettes viennent du sitemap, sans parcours des catégories
+		if err := feedSitemap(stats, recipeURLs, opts.Sitemap, opts.SitemapPattern); err != nil {
+			logSitemapError(opts.Sitemap, err)
 		}
+	} else {
+		// Démarrer le scraping de toutes les catégories définies
+		categoryStartTime := time.Now()
+		logScrapingStart(len(categories))
+		estimatedPages := len(categories) * maxPagesPerCategory
+		estimatedRecipes := len(categories) * maxPagesPerCategory * maxRecipesPerPage
+		estimatedSeconds := (estimatedPages*100 + estimatedRecipes*50) / 1000
+		logScrapingEstimate(estimatedPages, estimatedRecipes, estimatedSeconds)
+
+		for i, category := range categories {
+			categoryPhaseStart := time.Now()
+			logCategoryStart(i+1, len(categories), category)
+			logCategoryInfo(maxPagesPerCategory, maxRecipesPerPage)
+
+			// Visiter la catégorie (avec pagination automatique)
+			err := visitWithBudget(mainCollector, category)
+			if errors.Is(err, errBudgetExhausted) {
+				break // Plus de requêtes possibles : finaliser avec les recettes déjà trouvées
+			}
+			if isNotModified(err) {
+				continue // Catégorie inchangée depuis la dernière exécution
+			}
+			if err != nil {
+				logCategoryError(category, err)
+				continue // Continuer avec la catégorie suivante en cas d'erreur
+			}
 
-		categoryDuration := time.Since(categoryPhaseStart)
-		logCategoryComplete(i+1, len(categories), categoryDuration)
+			categoryDuration := time.Since(categoryPhaseStart)
+			logCategoryComplete(i+1, len(categories), categoryDuration)
 
-		// Pause respectueuse entre les catégories pour éviter de surcharger le serveur
-		if i < len(categories)-1 {
-			logCategoryPause()
-			time.Sleep(1 * time.Second)
+			// Pause respectueuse entre les catégories pour éviter de surcharger le serveur
+			if i < len(categories)-1 {
+				logCategoryPause()
+				time.Sleep(1 * time.Second)
+			}
 		}
-	}
 
-	totalCategoryTime := time.Since(categoryStartTime)
-	logCategoryPhaseComplete(totalCategoryTime)
+		totalCategoryTime := time.Since(categoryStartTime)
+		logCategoryPhaseComplete(totalCategoryTime)
+	}
 
 	// Fermer le channel des URLs pour signaler qu'il n'y a plus de recettes à traiter
 	stats.Mutex.RLock()
@@ -933,30 +1768,105 @@ func main() {
 
 	logProcessingClose()
 	close(recipeURLs)
+	if err := spillover.Close(); err != nil {
+		logInfo("⚠️  Erreur à la fermeture de %s: %v\n", spilloverPath, err)
+	}
+	if count := spillover.Count(); count > 0 {
+		logSpilloverSummary(count, spilloverPath)
+	}
+	if crawlBudget.Exhausted() {
+		stats.Mutex.RLock()
+		skipped := stats.RecipesSkipped
+		stats.Mutex.RUnlock()
+		logBudgetSummary(opts.MaxRequests, skipped)
+	}
 
 	// Attendre que toutes les recettes soient collectées (signal du collector)
 	<-done
+	stopProgress()
 	logProcessingComplete()
 
+	// Persister les dates de modification pour la prochaine exécution, une fois les recettes terminées
+	if lastModified != nil {
+		if err := lastModified.Save(); err != nil {
+			logInfo("⚠️  Impossible de sauvegarder %s: %v\n", lastModifiedPath, err)
+		}
+	}
+
 	// ===== PHASE 9: SAUVEGARDE ET STATISTIQUES =====
-	// Sauvegarder toutes les recettes dans un fichier JSON
-	filename := "data.json"
-	logSaveStart(len(recipes), filename)
+	// Sauvegarder toutes les recettes dans le fichier de sortie (ou terminer la sortie sur stdout)
+	filename := filepath.Join(opts.OutputDir, output)
+	if stream != nil {
+		filename = "stdout"
+	}
+	// -if-modified-since : si toutes les pages ont répondu 304, le fichier précédent n'est pas remplacé par une liste vide
+	unchanged := stream == nil && stats.AllPagesUnchanged()
 	saveStart := time.Now()
+	saved := ""
 	recipesMutex.RLock()
-	err := saveRecipesToFile(recipes, filename)
+	switch {
+	case unchanged:
+		logOutputUnchanged(filename)
+	case stream != nil:
+		logSaveStart(len(recipes), filename)
+		err = stream.Close()
+	default:
+		logSaveStart(len(recipes), filename)
+		saved, err = saveRecipesToFile(recipes, opts.OutputDir, output)
+		filename = filepath.Join(opts.OutputDir, saved)
+	}
 	recipesMutex.RUnlock()
 	saveDuration := time.Since(saveStart)
+	stopSampler() // La sauvegarde, qui encode toutes les recettes, compte dans le pic mémoire
 
-	if err == nil {
-		logSaveComplete(saveDuration)
-	} else {
+	if err != nil {
 		logSaveError(err)
-		return
+		return 1
+	}
+	if !unchanged {
+		logSaveComplete(saveDuration)
+	}
+
+	// -resume : les recettes reprises sont sauvegardées, le fichier repris laisse place aux nouveaux débordements
+	if spillover.path != spilloverPath {
+		if err := commitResume(spilloverPath, spillover); err != nil {
+			logInfo("⚠️  Impossible de remplacer %s: %v\n", spilloverPath, err)
+		}
 	}
 
 	// Afficher les statistiques détaillées de performance
 	printDetailedStats(stats, filename)
 
-	// Afficher les informations de build dans les logs finaux
+	// Sauvegarder les statistiques à côté des recettes
+	if err := saveStatsToFile(stats, opts.OutputDir, "stats.json"); err != nil {
+		logInfo("⚠️  Impossible de sauvegarder stats.json: %v\n", err)
+	}
+
+	// Recettes en échec, à examiner ou relancer
+	if err := failed.Save(opts.OutputDir, failedFilename); err != nil {
+		logInfo("⚠️  Impossible de sauvegarder %s: %v\n", failedFilename, err)
+	} else if count := len(failed.Entries()); count > 0 {
+		logFailedRecipesSaved(count, filepath.Join(opts.OutputDir, failedFilename))
+	}
+
+	// Recettes incomplètes, écartées de la sortie
+	if err := quarantine.Save(opts.OutputDir, invalidFilename); err != nil {
+		logInvalidRecipesSaveError(invalidFilename, err)
+	} else if count := len(quarantine.Entries()); count > 0 {
+		logInvalidRecipesSaved(count, filepath.Join(opts.OutputDir, invalidFilename))
+	}
+
+	// -keep-last / -keep-days : les fichiers horodatés des exécutions précédentes au-delà de la rétention sont supprimés
+	retention := outputRetention{Last: opts.KeepLast, Days: opts.KeepDays}
+	if saved != "" && retention.enabled() {
+		removed, err := pruneOldOutputs(opts.OutputDir, outputGlob(opts.Output, opts.Schedule != ""), retention, saved, time.Now())
+		if err != nil {
+			logPruneError(err)
+		}
+		if len(removed) > 0 {
+			logOutputsPruned(removed)
+		}
+	}
+
+	return successRateExitCode(stats, opts.MinSuccessRate)
 }
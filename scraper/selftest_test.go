@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelftest(t *testing.T) {
+	useTestCookieJar(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/broken" {
+			// Balisage modifié : plus aucun sélecteur ne correspond
+			w.Write([]byte(`<html><body><div class="new-layout"><p>Soupe</p></div></body></html>`))
+			return
+		}
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	var report bytes.Buffer
+	assert.Equal(t, 0, runSelftest(server.URL+"/recipe", &report))
+	assert.Contains(t, report.String(), "PASS  name         Soupe de légumes")
+	assert.Contains(t, report.String(), "PASS  ingredients  2 ingrédient(s)")
+	assert.Contains(t, report.String(), "PASS  instructions 2 étape(s)")
+	assert.Contains(t, report.String(), "PASS  image        https://img.example/soupe.jpg")
+	assert.NotContains(t, report.String(), "FAIL")
+
+	report.Reset()
+	assert.Equal(t, 1, runSelftest(server.URL+"/broken", &report))
+	assert.Contains(t, report.String(), "FAIL  name         vide")
+	assert.Contains(t, report.String(), "FAIL  ingredients  0 ingrédient(s)")
+	assert.Contains(t, report.String(), "4 champ(s) en échec")
+}
+
+func TestCheckRecipeFields(t *testing.T) {
+	checks := checkRecipeFields(Recipe{Name: "Soupe", Ingredients: []Ingredient{{Quantity: "2 carottes"}}})
+	failed := map[string]bool{}
+	for _, check := range checks {
+		failed[check.Field] = !check.Pass
+	}
+	assert.Equal(t, map[string]bool{"name": false, "ingredients": false, "instructions": true, "image": true}, failed)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// runSingleRecipe scrape une recette avec le traitement des workers et l'écrit en JSON (une ligne) sur w
+// Utilisé par l'API (POST /scraper/diff) pour comparer une page à la version enregistrée
+// Retourne le code de sortie : 0 si la recette a été extraite, 1 sinon (raison écrite sur errOut)
+func runSingleRecipe(recipeURL string, w, errOut io.Writer) int {
+	resetRunState()
+	addCustomCookies(recipeURL)
+	stats := NewScrapingStats(1)
+	completedRecipes := make(chan Recipe, 1)
+	processRecipeReusable(RecipeData{URL: recipeURL}, newCollyFetcher(stats), stats, completedRecipes, &WorkerStats{WorkerID: 1}, nil, nil)
+
+	select {
+	case recipe := <-completedRecipes:
+		if err := NewRecipeWriter(w, formatNDJSON, true).Write(recipe); err != nil {
+			fmt.Fprintf(errOut, "Erreur d'écriture de la recette: %v\n", err)
+			return 1
+		}
+		return 0
+	default:
+		fmt.Fprintf(errOut, "Aucune recette extraite de %s\n", redactURI(recipeURL))
+		return 1
+	}
+}
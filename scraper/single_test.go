@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du mode -recipe : la recette est écrite en JSON sur une ligne avec son empreinte
+func TestRunSingleRecipe(t *testing.T) {
+	useTestCookieJar(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(recipePageHTML))
+	}))
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	require.Equal(t, 0, runSingleRecipe(server.URL+"/recipe", &out, &errOut))
+	var recipe Recipe
+	require.NoError(t, json.Unmarshal(out.Bytes(), &recipe))
+	assert.Equal(t, "Soupe de légumes", recipe.Name)
+	assert.Len(t, recipe.Ingredients, 2)
+	assert.Equal(t, recipeContentHash(recipe), recipe.ContentHash)
+
+	out.Reset()
+	assert.Equal(t, 1, runSingleRecipe(server.URL+"/missing", &out, &errOut))
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), "Aucune recette extraite")
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSitemapDepth limite l'imbrication des index de sitemaps (protection contre les boucles)
+const maxSitemapDepth = 3
+
+// sitemapDocument représente un sitemap (<urlset>) ou un index de sitemaps (<sitemapindex>)
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// isIndex indique si le document est un index pointant vers d'autres sitemaps
+func (d sitemapDocument) isIndex() bool {
+	return d.XMLName.Local == "sitemapindex"
+}
+
+// decodeSitemap décode un sitemap, compressé en gzip ou non (détection par l'en-tête gzip)
+func decodeSitemap(r io.Reader) (sitemapDocument, error) {
+	var doc sitemapDocument
+
+	buffered := bufio.NewReader(r)
+	magic, _ := buffered.Peek(2)
+	var reader io.Reader = buffered
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return doc, fmt.Errorf("sitemap gzip invalide: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("sitemap XML invalide: %v", err)
+	}
+	if name := doc.XMLName.Local; name != "urlset" && name != "sitemapindex" {
+		return doc, fmt.Errorf("élément racine inattendu <%s>: <urlset> ou <sitemapindex> attendu", name)
+	}
+	return doc, nil
+}
+
+// parseSitemap extrait les URLs (<loc>) d'un sitemap ou d'un index de sitemaps
+// Pour un index, les URLs retournées sont celles des sitemaps enfants
+func parseSitemap(r io.Reader) ([]string, error) {
+	doc, err := decodeSitemap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := doc.URLs
+	if doc.isIndex() {
+		entries = doc.Sitemaps
+	}
+
+	var urls []string
+	for _, entry := range entries {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			urls = append(urls, loc)
+		}
+	}
+	return urls, nil
+}
+
+// fetchSitemapURLs télécharge un sitemap et suit récursivement les index
+// Seules les URLs de pages correspondant à pattern sont retournées, sans doublons
+func fetchSitemapURLs(client *http.Client, stats *ScrapingStats, sitemapURL string, pattern *regexp.Regexp) ([]string, error) {
+	seen := make(map[string]bool)
+	var pages []string
+
+	var visit func(u string, depth int) error
+	visit = func(u string, depth int) error {
+		if depth > maxSitemapDepth || seen[u] {
+			return nil
+		}
+		seen[u] = true
+
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", getRandomUserAgent())
+		req.Header.Set("Accept-Language", acceptLanguage(opts.Locale))
+		applyCustomHeaders(req.Header)
+
+		if !crawlBudget.Take() {
+			return errBudgetExhausted
+		}
+		stats.IncrementMainPageRequest()
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		stats.IncrementStatusCode(resp.StatusCode)
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("sitemap %s: statut HTTP %d", u, resp.StatusCode)
+		}
+
+		doc, err := decodeSitemap(resp.Body)
+		if err != nil {
+			return fmt.Errorf("sitemap %s: %v", u, err)
+		}
+
+		if doc.isIndex() {
+			for _, child := range doc.Sitemaps {
+				if err := visit(strings.TrimSpace(child.Loc), depth+1); err != nil {
+					logSitemapError(child.Loc, err) // Un sitemap enfant en échec n'interrompt pas les autres
+				}
+			}
+			return nil
+		}
+
+		for _, entry := range doc.URLs {
+			loc := strings.TrimSpace(entry.Loc)
+			if loc != "" && !seen[loc] && pattern.MatchString(loc) {
+				seen[loc] = true
+				pages = append(pages, loc)
+			}
+		}
+		return nil
+	}
+
+	if err := visit(sitemapURL, 0); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// titleFromURL déduit un titre provisoire du slug de l'URL (le sitemap ne fournit pas de titre)
+// Exemple : https://site/recipe/123/chicken-noodle-soup/ → "Chicken Noodle Soup"
+func titleFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	slug := path.Base(strings.TrimSuffix(parsed.Path, "/"))
+	if slug == "." || slug == "/" {
+		return rawURL
+	}
+	words := strings.FieldsFunc(slug, func(r rune) bool { return r == '-' || r == '_' })
+	for i, word := range words {
+		// Première lettre entière, même sur plusieurs octets (é, ç...)
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	if len(words) == 0 {
+		return rawURL
+	}
+	return strings.Join(words, " ")
+}
+
+// feedSitemap alimente recipeURLs avec les recettes du sitemap, à la place du parcours des catégories
+func feedSitemap(stats *ScrapingStats, recipeURLs chan<- RecipeData, sitemapURL string, pattern *regexp.Regexp) error {
+	client := &http.Client{
+		Jar:       sharedCookieJar,
+		Timeout:   opts.RequestTimeout,
+		Transport: newHTTPTransport(opts.HTTP2, opts.TLSMin),
+	}
+
+	pages, err := fetchSitemapURLs(client, stats, sitemapURL, pattern)
+	if err != nil {
+		return err
+	}
+	logSitemapLoaded(sitemapURL, len(pages))
+
+	for _, page := range pages {
+		stats.IncrementRecipesFound()
+		title := titleFromURL(page)
+		recipeURLs <- RecipeData{URL: page, Title: title} // Bloquant : les workers consomment en parallèle
+		logRecipeFound(stats.RecipesFound, title)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleURLSet = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://example.com/recipe/1/chicken-soup/</loc><lastmod>2024-01-01</lastmod></url>
+	<url><loc> https://example.com/recipe/2/apple-pie/ </loc></url>
+	<url><loc>https://example.com/article/how-to-cook/</loc></url>
+</urlset>`
+
+const sampleIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://example.com/sitemap_1.xml</loc></sitemap>
+	<sitemap><loc>https://example.com/sitemap_2.xml.gz</loc></sitemap>
+</sitemapindex>`
+
+func gzipBytes(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// Test de l'extraction des URLs d'un sitemap
+func TestParseSitemapURLSet(t *testing.T) {
+	urls, err := parseSitemap(strings.NewReader(sampleURLSet))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/recipe/1/chicken-soup/",
+		"https://example.com/recipe/2/apple-pie/",
+		"https://example.com/article/how-to-cook/",
+	}, urls)
+}
+
+// Test d'un index de sitemaps : les sitemaps enfants sont retournés
+func TestParseSitemapIndex(t *testing.T) {
+	urls, err := parseSitemap(strings.NewReader(sampleIndex))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/sitemap_1.xml", "https://example.com/sitemap_2.xml.gz"}, urls)
+}
+
+// Test d'un sitemap compressé en gzip
+func TestParseSitemapGzip(t *testing.T) {
+	urls, err := parseSitemap(bytes.NewReader(gzipBytes(t, sampleURLSet)))
+	require.NoError(t, err)
+	assert.Len(t, urls, 3)
+}
+
+// Test des documents invalides
+func TestParseSitemapInvalid(t *testing.T) {
+	_, err := parseSitemap(strings.NewReader("<html><body>pas un sitemap</body></html>"))
+	assert.Error(t, err)
+	_, err = parseSitemap(strings.NewReader("pas du XML"))
+	assert.Error(t, err)
+}
+
+// Test du parcours récursif : index → sitemap simple + sitemap gzip, filtrage par motif
+func TestFetchSitemapURLsNested(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap_index.xml":
+			io.WriteString(w, strings.ReplaceAll(sampleIndex, "https://example.com", server.URL))
+		case "/sitemap_1.xml":
+			io.WriteString(w, sampleURLSet)
+		case "/sitemap_2.xml.gz":
+			w.Header().Set("Content-Type", "application/x-gzip")
+			w.Write(gzipBytes(t, `<urlset><url><loc>https://example.com/recipe/3/pancakes/</loc></url>
+				<url><loc>https://example.com/recipe/1/chicken-soup/</loc></url></urlset>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	stats := NewScrapingStats(1)
+	pages, err := fetchSitemapURLs(server.Client(), stats, server.URL+"/sitemap_index.xml", regexp.MustCompile(`/recipe/`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/recipe/1/chicken-soup/",
+		"https://example.com/recipe/2/apple-pie/",
+		"https://example.com/recipe/3/pancakes/",
+	}, pages)
+	assert.Equal(t, int64(3), stats.MainPageRequests)
+
+	// Les recettes sont injectées dans la queue des workers
+	recipeURLs := make(chan RecipeData, 10)
+	require.NoError(t, feedSitemap(stats, recipeURLs, server.URL+"/sitemap_index.xml", regexp.MustCompile(`pancakes`)))
+	require.Len(t, recipeURLs, 1)
+	assert.Equal(t, RecipeData{URL: "https://example.com/recipe/3/pancakes/", Title: "Pancakes"}, <-recipeURLs)
+}
+
+func TestTitleFromURL(t *testing.T) {
+	assert.Equal(t, "Chicken Noodle Soup", titleFromURL("https://example.com/recipe/123/chicken-noodle-soup/"))
+	assert.Equal(t, "https://example.com/", titleFromURL("https://example.com/"))
+	assert.Equal(t, "Éclair Au Chocolat", titleFromURL("https://example.com/recette/%C3%A9clair-au-chocolat/"))
+}
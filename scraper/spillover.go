@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// spilloverFilename reçoit les recettes trouvées alors que le channel recipeURLs était plein
+// Elles sont reprises par l'exécution suivante avec -resume
+const spilloverFilename = "spillover.jsonl"
+
+// spilloverWriter ajoute les recettes non mises en file à spillover.jsonl, une par ligne
+// Thread-safe grâce au Mutex : les callbacks des collecteurs peuvent écrire en parallèle
+type spilloverWriter struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File // Ouvert à la première recette, pour ne pas créer de fichier vide
+	count int
+}
+
+// newSpilloverWriter crée un writer vers path (le fichier n'est créé qu'au premier ajout)
+func newSpilloverWriter(path string) *spilloverWriter {
+	return &spilloverWriter{path: path}
+}
+
+// Write ajoute une recette à la fin du fichier
+func (w *spilloverWriter) Write(data RecipeData) error {
+	line, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		w.file = file
+	}
+	if _, err := w.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Count retourne le nombre de recettes écrites
+func (w *spilloverWriter) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count
+}
+
+// Close ferme le fichier s'il a été ouvert
+func (w *spilloverWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// enqueueRecipe envoie une recette aux workers sans bloquer le collecteur
+// Si le channel est plein, la recette est écrite dans le fichier de débordement (ou ignorée sans fichier)
+func enqueueRecipe(stats *ScrapingStats, recipeURLs chan<- RecipeData, spillover *spilloverWriter, recipeData RecipeData) {
+	select {
+	case recipeURLs <- recipeData:
+		logRecipeFound(stats.RecipesFound, recipeData.Title)
+	default:
+		if spillover == nil {
+			logRecipeQueueFull(recipeData.Title)
+			return
+		}
+		if err := spillover.Write(recipeData); err != nil {
+			logSpilloverError(recipeData.Title, err)
+			return
+		}
+		logRecipeSpilled(recipeData.Title)
+	}
+}
+
+// loadSpillover lit les recettes d'un fichier de débordement (fichier absent = aucune recette)
+// Les lignes illisibles, par exemple tronquées par un arrêt brutal, sont ignorées
+func loadSpillover(path string) ([]RecipeData, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var recipes []RecipeData
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var data RecipeData
+		if err := json.Unmarshal([]byte(line), &data); err != nil || data.URL == "" {
+			continue
+		}
+		recipes = append(recipes, data)
+	}
+	return recipes, scanner.Err()
+}
+
+// spilloverPendingSuffix : pendant une reprise, les nouveaux débordements sont écrits dans un fichier à part
+// Le fichier repris reste intact tant que ses recettes ne sont pas traitées et sauvegardées (commitResume)
+const spilloverPendingSuffix = ".pending"
+
+// resumeSpillover lit les recettes à reprendre de path et crée le writer des nouveaux débordements (path.pending)
+// Un fichier .pending laissé par une reprise interrompue est d'abord rattaché à path pour ne rien perdre
+func resumeSpillover(path string) ([]RecipeData, *spilloverWriter, error) {
+	pendingPath := path + spilloverPendingSuffix
+	if err := appendSpillover(path, pendingPath); err != nil {
+		return nil, nil, err
+	}
+	resumed, err := loadSpillover(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resumed, newSpilloverWriter(pendingPath), nil
+}
+
+// appendSpillover ajoute le contenu de from à la fin de path puis supprime from (from absent = rien à faire)
+// Un saut de ligne sépare les deux contenus : une dernière ligne tronquée de path reste isolée
+func appendSpillover(path, from string) error {
+	content, err := os.ReadFile(from)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append([]byte("\n"), content...))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// commitResume remplace le fichier repris par les débordements de l'exécution (writer fermé)
+// Sans nouveau débordement, le fichier repris est supprimé
+func commitResume(path string, writer *spilloverWriter) error {
+	if writer.Count() > 0 {
+		return os.Rename(writer.path, path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test du débordement : les recettes qui ne tiennent pas dans le channel sont écrites dans spillover.jsonl
+func TestSpilloverOnFullChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cards strings.Builder
+		for i := 1; i <= 5; i++ {
+			fmt.Fprintf(&cards, `<a class="mntl-card" href="/recipe/%d"><span class="card__title-text">Recette %d</span></a>`, i, i)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><div class="mntl-taxonomysc-article-list-group">%s</div></body></html>`, cards.String())
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), spilloverFilename)
+	spillover := newSpilloverWriter(path)
+
+	// Channel de 2 places sans consommateur : 3 recettes débordent
+	stats := NewScrapingStats(1)
+	recipeURLs := make(chan RecipeData, 2)
+	collector := createMainCollectorWithPagination(stats, recipeURLs, 1, nil, spillover)
+	require.NoError(t, collector.Visit(server.URL+"/recipes/79/desserts/"))
+	require.NoError(t, spillover.Close())
+
+	assert.Len(t, recipeURLs, 2)
+	assert.Equal(t, 3, spillover.Count())
+
+	spilled, err := loadSpillover(path)
+	require.NoError(t, err)
+	require.Len(t, spilled, 3)
+	assert.Equal(t, server.URL+"/recipe/3", spilled[0].URL)
+	assert.Equal(t, "Recette 3", spilled[0].Title)
+	assert.Equal(t, "desserts", spilled[0].Category)
+	assert.Equal(t, server.URL+"/recipe/5", spilled[2].URL)
+}
+
+// Test de la relecture d'un fichier de débordement
+func TestLoadSpillover(t *testing.T) {
+	dir := t.TempDir()
+
+	// Fichier absent : rien à reprendre
+	recipes, err := loadSpillover(filepath.Join(dir, spilloverFilename))
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+
+	// Les lignes vides ou tronquées sont ignorées
+	path := filepath.Join(dir, spilloverFilename)
+	content := `{"url":"https://example.com/recipe/1","title":"Soupe"}` + "\n\n" + `{"url":"https://exa`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	recipes, err = loadSpillover(path)
+	require.NoError(t, err)
+	assert.Equal(t, []RecipeData{{URL: "https://example.com/recipe/1", Title: "Soupe"}}, recipes)
+
+	o, err := parseOptions([]string{"-resume"}, os.Stderr)
+	require.NoError(t, err)
+	assert.True(t, o.Resume)
+}
+
+// Test de la reprise : le fichier repris n'est remplacé par les nouveaux débordements qu'à commitResume
+func TestResumeSpillover(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, spilloverFilename)
+	soupe := `{"url":"https://example.com/recipe/1","title":"Soupe"}`
+	tarte := `{"url":"https://example.com/recipe/2","title":"Tarte"}`
+
+	// Dernière ligne tronquée par un arrêt brutal, débordements d'une reprise interrompue en attente
+	require.NoError(t, os.WriteFile(path, []byte(soupe+"\n"+`{"url":"https://exa`), 0644))
+	require.NoError(t, os.WriteFile(path+spilloverPendingSuffix, []byte(tarte+"\n"), 0644))
+
+	resumed, writer, err := resumeSpillover(path)
+	require.NoError(t, err)
+	assert.Equal(t, []RecipeData{
+		{URL: "https://example.com/recipe/1", Title: "Soupe"},
+		{URL: "https://example.com/recipe/2", Title: "Tarte"},
+	}, resumed)
+
+	require.NoError(t, writer.Write(RecipeData{URL: "https://example.com/recipe/3", Title: "Gratin"}))
+	require.NoError(t, writer.Close())
+
+	// Avant commitResume, une nouvelle reprise retrouverait les mêmes recettes
+	again, err := loadSpillover(path)
+	require.NoError(t, err)
+	assert.Equal(t, resumed, again)
+
+	require.NoError(t, commitResume(path, writer))
+	remaining, err := loadSpillover(path)
+	require.NoError(t, err)
+	assert.Equal(t, []RecipeData{{URL: "https://example.com/recipe/3", Title: "Gratin"}}, remaining)
+	assert.NoFileExists(t, path+spilloverPendingSuffix)
+
+	// Sans nouveau débordement, le fichier repris est supprimé
+	_, writer, err = resumeSpillover(path)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, commitResume(path, writer))
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/maxime-louis14/api-golang/scraperspec"
+)
+
+// statusFilename est l'état de l'exécution en cours, lu par l'API (GET /scraper/status)
+const statusFilename = "status.json"
+
+// statusInterval est la période de réécriture de status.json pendant une exécution
+const statusInterval = time.Second
+
+// runStatus est le contenu de status.json, format défini avec l'API dans scraperspec
+type runStatus = scraperspec.Status
+
+// Status photographie la progression de l'exécution à l'instant now
+func (s *ScrapingStats) Status(running bool, now time.Time) runStatus {
+	s.Mutex.RLock()
+	defer s.Mutex.RUnlock()
+
+	status := runStatus{
+		Running:          running,
+		PID:              os.Getpid(),
+		StartedAt:        s.StartTime,
+		UpdatedAt:        now,
+		RecipesFound:     s.RecipesFound,
+		RecipesCompleted: s.RecipesCompleted,
+		RecipesFailed:    s.RecipesFailed,
+		TotalRequests:    s.TotalRequests,
+	}
+	if elapsed := now.Sub(s.StartTime).Seconds(); elapsed > 0 {
+		status.RequestsPerSecond = float64(s.TotalRequests) / elapsed
+	}
+	if !running {
+		status.FinishedAt = &now
+	}
+	return status
+}
+
+// writeStatusFile écrit status.json via un fichier temporaire renommé,
+// pour que l'API ne lise jamais un fichier à moitié écrit
+func writeStatusFile(path string, status runStatus) error {
+	content, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// startStatusWriter écrit status.json immédiatement puis à chaque intervalle
+// Retourne la fonction d'arrêt, qui écrit l'état final (running: false)
+func startStatusWriter(stats *ScrapingStats, path string, interval time.Duration) func() {
+	write := func(running bool) {
+		if err := writeStatusFile(path, stats.Status(running, time.Now())); err != nil {
+			logStatusError(path, err)
+		}
+	}
+	write(true)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				write(false)
+				return
+			case <-ticker.C:
+				write(true)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
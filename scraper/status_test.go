@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readStatusFile relit status.json tel que l'API le lit
+func readStatusFile(t *testing.T, path string) runStatus {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var status runStatus
+	require.NoError(t, json.Unmarshal(content, &status))
+	return status
+}
+
+// Test de status.json : progression pendant l'exécution puis état final
+func TestStatusWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sortie", statusFilename)
+	stats := NewScrapingStats(2)
+
+	stop := startStatusWriter(stats, path, 10*time.Millisecond)
+
+	// Écrit dès le démarrage
+	status := readStatusFile(t, path)
+	assert.True(t, status.Running)
+	assert.Equal(t, os.Getpid(), status.PID)
+	assert.Nil(t, status.FinishedAt)
+
+	// La progression est reprise à l'écriture suivante
+	for i := 0; i < 3; i++ {
+		stats.IncrementRecipesFound()
+		stats.IncrementRecipeRequest()
+	}
+	stats.IncrementRecipesCompleted()
+	stats.IncrementRecipesFailed()
+	assert.Eventually(t, func() bool {
+		return readStatusFile(t, path).RecipesFound == 3
+	}, time.Second, 10*time.Millisecond)
+
+	stop()
+	status = readStatusFile(t, path)
+	assert.False(t, status.Running)
+	require.NotNil(t, status.FinishedAt)
+	assert.Equal(t, int64(3), status.RecipesFound)
+	assert.Equal(t, int64(1), status.RecipesCompleted)
+	assert.Equal(t, int64(1), status.RecipesFailed)
+	assert.Equal(t, int64(3), status.TotalRequests)
+	assert.Greater(t, status.RequestsPerSecond, 0.0)
+
+	_, err := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "le fichier temporaire doit être renommé")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// breadcrumbItemSelector lit les étapes du fil d'Ariane AllRecipes (ex: Recipes > Desserts > Cookies)
+const breadcrumbItemSelector = ".mntl-breadcrumbs li"
+
+// breadcrumbRootTags sont les étapes du fil d'Ariane qui ne décrivent pas la recette
+var breadcrumbRootTags = map[string]bool{"home": true, "recipes": true}
+
+// cuisineBreadcrumb est la rubrique AllRecipes dont les étapes suivantes sont des cuisines (ex: World Cuisine > European > Italian)
+const cuisineBreadcrumb = "world cuisine"
+
+// recipeTaxonomy regroupe les étiquettes, la cuisine et le type de plat d'une page de recette
+type recipeTaxonomy struct {
+	Tags    []string
+	Cuisine string
+	Course  string
+}
+
+// normalizeTag met une étiquette en minuscules et réduit ses espaces ("  Main  Dishes " -> "main dishes")
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// extractBreadcrumb lit les étapes normalisées du fil d'Ariane, sans les rubriques racines
+func extractBreadcrumb(doc *goquery.Selection) []string {
+	var items []string
+	doc.Find(breadcrumbItemSelector).Each(func(_ int, item *goquery.Selection) {
+		if tag := normalizeTag(item.Text()); tag != "" && !breadcrumbRootTags[tag] {
+			items = append(items, tag)
+		}
+	})
+	return items
+}
+
+// buildTaxonomy combine le fil d'Ariane et les catégories JSON-LD (ld peut être nil)
+// Cuisine et Course viennent de recipeCuisine et recipeCategory ; à défaut, du fil d'Ariane :
+// l'étape la plus précise sous World Cuisine pour la cuisine, la première rubrique sinon pour le type de plat
+// Tags reprend le fil d'Ariane puis les catégories, normalisés et sans doublon ; tout reste vide si la page n'en a pas
+func buildTaxonomy(breadcrumb []string, ld *jsonLDRecipe) recipeTaxonomy {
+	var taxonomy recipeTaxonomy
+	var categories, cuisines []string
+	if ld != nil {
+		categories, cuisines = ld.Categories, ld.Cuisines
+	}
+
+	if len(cuisines) > 0 {
+		taxonomy.Cuisine = normalizeTag(cuisines[0])
+	} else if len(breadcrumb) > 1 && breadcrumb[0] == cuisineBreadcrumb {
+		taxonomy.Cuisine = breadcrumb[len(breadcrumb)-1]
+	}
+	if len(categories) > 0 {
+		taxonomy.Course = normalizeTag(categories[0])
+	} else if len(breadcrumb) > 0 && breadcrumb[0] != cuisineBreadcrumb {
+		taxonomy.Course = breadcrumb[0]
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range [][]string{breadcrumb, categories, cuisines} {
+		for _, tag := range group {
+			if tag = normalizeTag(tag); tag != "" && !seen[tag] {
+				seen[tag] = true
+				taxonomy.Tags = append(taxonomy.Tags, tag)
+			}
+		}
+	}
+	return taxonomy
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fil d'Ariane et catégories JSON-LD : étiquettes normalisées, sans doublon ni rubrique racine
+func TestExtractTaxonomyBreadcrumbs(t *testing.T) {
+	doc := parseFixture(t, loadFixture(t, "recipe_breadcrumbs.html"))
+	assert.Equal(t, []string{"main dishes", "pasta and noodles", "lasagna"}, extractBreadcrumb(doc))
+
+	// Même avec -extractor css, les catégories JSON-LD renseignent la taxonomie
+	content := extractRecipeContent(doc, false)
+	assert.Nil(t, content.JSONLD)
+	assert.Equal(t, recipeTaxonomy{
+		Tags:    []string{"main dishes", "pasta and noodles", "lasagna", "dinner", "italian"},
+		Cuisine: "italian",
+		Course:  "dinner",
+	}, content.Taxonomy)
+}
+
+// Pages sans fil d'Ariane : catégories JSON-LD seules, ou rien du tout
+func TestExtractTaxonomyFixtures(t *testing.T) {
+	cookies := extractRecipeContent(parseFixture(t, loadFixture(t, "recipe_cookies.html")), true)
+	assert.Equal(t, recipeTaxonomy{Tags: []string{"dessert", "american"}, Cuisine: "american", Course: "dessert"}, cookies.Taxonomy)
+
+	plain := extractRecipeContent(parseFixture(t, loadFixture(t, "recipe_plain_steps.html")), true)
+	assert.Equal(t, recipeTaxonomy{}, plain.Taxonomy)
+}
+
+// Sans catégorie JSON-LD, cuisine et type de plat sont déduits du fil d'Ariane
+func TestBuildTaxonomyBreadcrumbFallback(t *testing.T) {
+	world := buildTaxonomy([]string{"world cuisine", "european", "italian"}, nil)
+	assert.Equal(t, recipeTaxonomy{Tags: []string{"world cuisine", "european", "italian"}, Cuisine: "italian"}, world)
+
+	dessert := buildTaxonomy([]string{"desserts", "cookies"}, &jsonLDRecipe{Categories: []string{" Desserts "}})
+	assert.Equal(t, recipeTaxonomy{Tags: []string{"desserts", "cookies"}, Course: "desserts"}, dessert)
+
+	assert.Equal(t, recipeTaxonomy{}, buildTaxonomy(nil, &jsonLDRecipe{}))
+}
+
+// parseRecipeResponse remplace la taxonomie d'une tentative précédente
+func TestParseRecipeResponseTaxonomy(t *testing.T) {
+	base, err := url.Parse("https://www.allrecipes.com/recipe/23600/worlds-best-lasagna/")
+	require.NoError(t, err)
+	header := http.Header{"Content-Type": []string{"text/html; charset=utf-8"}}
+
+	recipe := Recipe{Page: base.String()}
+	require.NoError(t, parseRecipeResponse(&recipe, loadFixture(t, "recipe_breadcrumbs.html"), header, base, extractorCSS))
+	assert.Equal(t, "italian", recipe.Cuisine)
+	assert.Equal(t, "dinner", recipe.Course)
+	assert.Len(t, recipe.Tags, 5)
+
+	require.NoError(t, parseRecipeResponse(&recipe, loadFixture(t, "recipe_plain_steps.html"), header, base, extractorCSS))
+	assert.Empty(t, recipe.Tags)
+	assert.Empty(t, recipe.Cuisine)
+	assert.Empty(t, recipe.Course)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// tlsVersions associe les valeurs de -tls-min aux versions TLS
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion valide la valeur de -tls-min (1.0, 1.1, 1.2 ou 1.3)
+func parseTLSVersion(value string, version *uint16) error {
+	v, ok := tlsVersions[value]
+	if !ok {
+		return fmt.Errorf("version TLS invalide %q: 1.0, 1.1, 1.2 ou 1.3 attendu", value)
+	}
+	*version = v
+	return nil
+}
+
+// newHTTPTransport crée le transport réseau des collecteurs, copie de http.DefaultTransport
+// http2 = false retire HTTP/2 de la négociation ALPN (certains anti-bots repèrent son empreinte) ;
+// tlsMin est la version TLS minimale acceptée (0 = défaut de Go)
+// Avec les valeurs par défaut, le transport se comporte comme http.DefaultTransport
+func newHTTPTransport(http2 bool, tlsMin uint16) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsMin != 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.MinVersion = tlsMin
+	}
+	if !http2 {
+		// Une map non nil et vide désactive HTTP/2 (documentation de net/http)
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		// Une fois utilisé, http.DefaultTransport annonce h2 en ALPN : la copie ne doit plus le proposer
+		// (nouvelle slice, NextProtos étant partagé avec la configuration d'origine)
+		if transport.TLSClientConfig != nil {
+			var protos []string
+			for _, proto := range transport.TLSClientConfig.NextProtos {
+				if proto != "h2" {
+					protos = append(protos, proto)
+				}
+			}
+			transport.TLSClientConfig.NextProtos = protos
+		}
+	}
+	return transport
+}
+
+// configureTransport installe sous requestTimings le transport réglé par -http2 et -tls-min
+// À appeler avant configureHeadless, qui l'enveloppe dans le repli headless
+func configureTransport() {
+	requestTimings.base = newDecodingTransport(newHTTPTransport(opts.HTTP2, opts.TLSMin))
+}
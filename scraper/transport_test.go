@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportOptions(t *testing.T) {
+	o, err := parseOptions(nil, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, o.HTTP2)
+	assert.Zero(t, o.TLSMin)
+
+	o, err = parseOptions([]string{"-http2=false", "-tls-min", "1.2"}, io.Discard)
+	require.NoError(t, err)
+	assert.False(t, o.HTTP2)
+	assert.Equal(t, uint16(tls.VersionTLS12), o.TLSMin)
+
+	for _, value := range []string{"1", "1.4", "TLS1.2", ""} {
+		_, err := parseOptions([]string{"-tls-min", value}, io.Discard)
+		assert.Error(t, err, value)
+	}
+}
+
+// Le transport reflète -http2 et -tls-min ; par défaut, il garde les réglages de http.DefaultTransport
+func TestNewHTTPTransport(t *testing.T) {
+	transport := newHTTPTransport(true, 0)
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Empty(t, transport.TLSNextProto)
+	if transport.TLSClientConfig != nil {
+		assert.Zero(t, transport.TLSClientConfig.MinVersion)
+	}
+
+	transport = newHTTPTransport(false, tls.VersionTLS12)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+	require.NotNil(t, transport.TLSClientConfig)
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	assert.NotContains(t, transport.TLSClientConfig.NextProtos, "h2")
+}
+
+// Contre un serveur TLS acceptant HTTP/2, -http2=false force HTTP/1.1 et -tls-min refuse une version trop ancienne
+func TestHTTPTransportNegotiation(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Handshakes refusés volontairement
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	// Certificat de test du serveur ajouté à la configuration TLS du transport
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	get := func(transport *http.Transport) (string, error) {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = trusted
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// Après une première requête, http.DefaultTransport annonce h2 en ALPN : ses copies en héritent
+	_, _ = http.DefaultClient.Get(server.URL)
+	defaultProtos := append([]string(nil), http.DefaultTransport.(*http.Transport).TLSClientConfig.NextProtos...)
+	require.Contains(t, defaultProtos, "h2")
+
+	proto, err := get(newHTTPTransport(true, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", proto)
+
+	proto, err = get(newHTTPTransport(false, tls.VersionTLS12))
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", proto)
+
+	_, err = get(newHTTPTransport(true, tls.VersionTLS13))
+	assert.Error(t, err)
+	assert.Equal(t, defaultProtos, http.DefaultTransport.(*http.Transport).TLSClientConfig.NextProtos)
+}
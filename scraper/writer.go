@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Formats de sortie des recettes (-format)
+const (
+	formatJSON   = "json"   // Tableau JSON (data.json historique)
+	formatNDJSON = "ndjson" // Une recette JSON par ligne, écrite dès qu'elle est terminée
+)
+
+// stdoutOutput est la valeur de -output qui envoie les recettes sur stdout
+const stdoutOutput = "-"
+
+// RecipeWriter écrit les recettes au format choisi sur un io.Writer
+// En NDJSON chaque recette est écrite immédiatement ; en JSON le tableau est écrit à la fermeture
+// Non thread-safe : utilisé par la seule goroutine qui collecte les recettes terminées
+type RecipeWriter struct {
+	w       io.Writer
+	format  string
+	compact bool
+	pending []Recipe // Recettes en attente du tableau JSON
+	count   int
+}
+
+// NewRecipeWriter crée un writer de recettes (format json ou ndjson)
+func NewRecipeWriter(w io.Writer, format string, compact bool) *RecipeWriter {
+	return &RecipeWriter{w: w, format: format, compact: compact}
+}
+
+// Write écrit (NDJSON) ou met en attente (JSON) une recette, avec son identifiant et son empreinte de contenu
+func (rw *RecipeWriter) Write(recipe Recipe) error {
+	rw.count++
+	recipe.RecipeID = recipeID(recipe.Page)
+	recipe.ContentHash = recipeContentHash(recipe)
+	if rw.format != formatNDJSON {
+		rw.pending = append(rw.pending, recipe)
+		return nil
+	}
+
+	line, err := json.Marshal(recipe)
+	if err != nil {
+		return err
+	}
+	_, err = rw.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close termine la sortie : écrit le tableau JSON des recettes en attente
+func (rw *RecipeWriter) Close() error {
+	if rw.format == formatNDJSON {
+		return nil
+	}
+	content, err := encodeRecipes(rw.pending, rw.compact)
+	if err != nil {
+		return err
+	}
+	rw.pending = nil
+	_, err = rw.w.Write(content)
+	return err
+}
+
+// Count retourne le nombre de recettes écrites
+func (rw *RecipeWriter) Count() int {
+	return rw.count
+}
+
+// parseFormat valide la valeur de -format
+func parseFormat(value string) (string, error) {
+	switch value {
+	case formatJSON, formatNDJSON:
+		return value, nil
+	}
+	return "", fmt.Errorf("format invalide %q: json ou ndjson attendu", value)
+}
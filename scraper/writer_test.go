@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test de -output - : les recettes terminées sont écrites sur stdout en NDJSON valide
+func TestStreamRecipesToStdout(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	completedRecipes := make(chan Recipe, 2)
+	done := make(chan bool)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	stream := NewRecipeWriter(os.Stdout, formatNDJSON, false)
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, stream, nil)
+
+	completedRecipes <- Recipe{Name: "Soupe", Page: "https://example.com/soupe"}
+	completedRecipes <- Recipe{Name: "Tarte\naux pommes", Page: "https://example.com/tarte"}
+	close(completedRecipes)
+	<-done
+	require.NoError(t, stream.Close())
+	writer.Close()
+	os.Stdout = stdout
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		require.True(t, json.Valid(scanner.Bytes()), scanner.Text())
+		var recipe Recipe
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &recipe))
+		names = append(names, recipe.Name)
+	}
+	assert.Equal(t, []string{"Soupe", "Tarte\naux pommes"}, names)
+	assert.Equal(t, 2, stream.Count())
+	assert.Len(t, recipes, 2)
+}
+
+// Test du format JSON : le tableau n'est écrit qu'à la fermeture
+func TestRecipeWriterJSON(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewRecipeWriter(&buf, formatJSON, true)
+	require.NoError(t, writer.Write(Recipe{Name: "Soupe"}))
+	assert.Zero(t, buf.Len())
+
+	require.NoError(t, writer.Close())
+	var recipes []Recipe
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &recipes))
+	assert.Equal(t, "Soupe", recipes[0].Name)
+}
+
+// Test des options -output et -format
+func TestOutputFormatOptions(t *testing.T) {
+	o, err := parseOptions(nil, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "data.json", o.Output)
+	assert.Equal(t, formatJSON, o.Format)
+
+	o, err = parseOptions([]string{"-format", "ndjson", "-output", "-"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, stdoutOutput, o.Output)
+	assert.Equal(t, formatNDJSON, o.Format)
+
+	_, err = parseOptions([]string{"-format", "csv"}, io.Discard)
+	assert.Error(t, err)
+}
+
+// Test de la séparation des sorties : stdout ne porte que les recettes, les logs vont sur stderr
+func TestLogsOnStderrWhileStreaming(t *testing.T) {
+	saved := opts
+	defer func() { opts = saved }()
+	opts = defaultOptions()
+	opts.Output = stdoutOutput
+	opts.Format = formatNDJSON
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	require.NoError(t, err)
+	stderrReader, stderrWriter, err := os.Pipe()
+	require.NoError(t, err)
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		log.SetOutput(os.Stderr)
+	}()
+
+	require.NoError(t, initLogger(t.TempDir()))
+	stream := NewRecipeWriter(os.Stdout, opts.Format, opts.Compact)
+	logInfo("🚀 Début du test\n")
+	require.NoError(t, stream.Write(Recipe{Name: "Soupe"}))
+	logSaveComplete(time.Second)
+	closeLogger()
+	stdoutWriter.Close()
+	stderrWriter.Close()
+
+	data, err := io.ReadAll(stdoutReader)
+	require.NoError(t, err)
+	logs, err := io.ReadAll(stderrReader)
+	require.NoError(t, err)
+
+	var recipe Recipe
+	require.NoError(t, json.Unmarshal(data, &recipe))
+	assert.Equal(t, "Soupe", recipe.Name)
+	assert.Equal(t, 1, bytes.Count(data, []byte("\n")))
+	assert.Contains(t, string(logs), "Début du test")
+	assert.Contains(t, string(logs), "Sauvegarde terminée")
+}
+
+// Une recette trouvée deux fois (deux catégories, URLs différentes) n'est collectée qu'une fois, avec son identifiant
+func TestRecipeCollectorSkipsDuplicates(t *testing.T) {
+	completedRecipes := make(chan Recipe, 4)
+	done := make(chan bool)
+	var recipes []Recipe
+	var recipesMutex sync.RWMutex
+	startRecipeCollector(completedRecipes, &recipes, &recipesMutex, done, nil, nil)
+
+	completedRecipes <- Recipe{Name: "Cookies", Page: "https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies/"}
+	completedRecipes <- Recipe{Name: "Soupe", Page: "https://example.com/soupe"}
+	completedRecipes <- Recipe{Name: "Cookies (desserts)", Page: "https://www.allrecipes.com/recipe/10813/best-chocolate-chip-cookies?internalSource=desserts"}
+	completedRecipes <- Recipe{Name: "Sans page"}
+	close(completedRecipes)
+	<-done
+
+	require.Len(t, recipes, 3)
+	assert.Equal(t, "Cookies", recipes[0].Name)
+	assert.Equal(t, recipeID(recipes[0].Page), recipes[0].RecipeID)
+	assert.NotEmpty(t, recipes[0].RecipeID)
+	assert.Equal(t, "Soupe", recipes[1].Name)
+	assert.Empty(t, recipes[2].RecipeID)
+}
+
+// Test de l'écriture de l'empreinte de contenu avec la recette
+func TestRecipeWriterWritesContentHash(t *testing.T) {
+	recipe := Recipe{
+		Name:         "Soupe de légumes",
+		Page:         "https://example.com/soupe",
+		Ingredients:  []Ingredient{{Quantity: "2 carottes", Name: "carottes"}},
+		Instructions: []Instruction{{Number: "1", Description: "Éplucher les légumes."}},
+	}
+
+	var out bytes.Buffer
+	writer := NewRecipeWriter(&out, formatNDJSON, false)
+	require.NoError(t, writer.Write(recipe))
+	var written Recipe
+	require.NoError(t, json.Unmarshal(out.Bytes(), &written))
+	assert.Equal(t, recipeContentHash(recipe), written.ContentHash)
+	assert.NotEmpty(t, written.ContentHash)
+}
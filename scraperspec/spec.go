@@ -0,0 +1,58 @@
+// Package scraperspec définit les fichiers et bornes partagés par le scraper et l'API :
+// configuration (scraper_config.json), progression (status.json), fichiers de sortie horodatés
+// et nombre de pages d'une catégorie. Les deux côtés importent ces définitions plutôt que de les recopier
+package scraperspec
+
+import "time"
+
+// Config est le fichier de configuration du scraper (JSON, YAML ou TOML selon l'extension), modifiable par l'API
+type Config struct {
+	// Pages de catégories parcourues avec pagination
+	Categories []string `json:"categories" yaml:"categories" toml:"categories"`
+	// Champs sans lesquels une recette va dans invalid.json plutôt que dans la sortie
+	// (noms json, ex: ["name", "ingredients", "instructions", "image", "nutrition"] ; vide = name, ingredients, instructions)
+	RequiredFields []string `json:"required_fields,omitempty" yaml:"required_fields,omitempty" toml:"required_fields,omitempty"`
+}
+
+// DefaultCategories est la liste historique des catégories AllRecipes, utilisée sans fichier de configuration
+var DefaultCategories = []string{
+	"https://www.allrecipes.com/recipes/16369/soups-stews-and-chili/soup/",               // Soupes
+	"https://www.allrecipes.com/recipes/1246/soups-stews-and-chili/soup/chicken-soup/",   // Soupes de poulet
+	"https://www.allrecipes.com/recipes/76/appetizers-and-snacks/",                       // Apéritifs et collations
+	"https://www.allrecipes.com/recipes/113/appetizers-and-snacks/pastries/",             // Pâtisseries
+	"https://www.allrecipes.com/recipes/1059/fruits-and-vegetables/vegetables/",          // Légumes
+	"https://www.allrecipes.com/recipes/1083/fruits-and-vegetables/vegetables/cucumber/", // Concombres
+	"https://www.allrecipes.com/recipes/77/drinks/",                                      // Boissons
+	"https://www.allrecipes.com/recipes/79/desserts/",                                    // Desserts
+	"https://www.allrecipes.com/recipes/81/side-dish/",                                   // Accompagnements
+	"https://www.allrecipes.com/recipes/1569/everyday-cooking/on-the-go/tailgating/",     // Tailgating
+}
+
+// Status est le contenu de status.json, réécrit par le scraper pendant une exécution
+// L'API n'a accès à la progression du scraper, processus séparé, qu'à travers ce fichier
+type Status struct {
+	Running           bool       `json:"running"`               // Faux une fois l'exécution terminée
+	PID               int        `json:"pid"`                   // Processus du scraper
+	StartedAt         time.Time  `json:"started_at"`            // Début de l'exécution
+	UpdatedAt         time.Time  `json:"updated_at"`            // Dernière écriture, pour détecter un scraper arrêté brutalement
+	FinishedAt        *time.Time `json:"finished_at,omitempty"` // Fin de l'exécution
+	RecipesFound      int64      `json:"recipes_found"`
+	RecipesCompleted  int64      `json:"recipes_completed"`
+	RecipesFailed     int64      `json:"recipes_failed"`
+	TotalRequests     int64      `json:"total_requests"`
+	RequestsPerSecond float64    `json:"requests_per_second"`
+	Stale             bool       `json:"stale"` // Positionné par l'API : en cours mais plus mis à jour, scraper probablement arrêté brutalement
+}
+
+// MaxCategoryPages borne le nombre de pages d'une catégorie parcourues à la demande (-category-pages, POST /scraper/category) :
+// une requête de l'API ne doit pas parcourir une catégorie entière
+const MaxCategoryPages = 20
+
+// OutputTimestampLayout est le format de l'horodatage des fichiers de sortie (20240101-060000)
+const OutputTimestampLayout = "20060102-150405"
+
+// OutputTimestampPlaceholder est remplacé dans -output par l'horodatage de la sauvegarde (-output data-{timestamp}.json)
+const OutputTimestampPlaceholder = "{timestamp}"
+
+// TimestampedDataPattern reconnaît les fichiers horodatés du scraper (-output data-{timestamp}.json, exécutions planifiées)
+const TimestampedDataPattern = "data-*.json"
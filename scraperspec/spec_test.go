@@ -0,0 +1,22 @@
+package scraperspec
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Un fichier -output data-{timestamp}.json horodaté par le scraper est retrouvé par l'API
+func TestTimestampedDataPatternMatchesOutput(t *testing.T) {
+	saved := strings.ReplaceAll("data-"+OutputTimestampPlaceholder+".json", OutputTimestampPlaceholder,
+		time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC).Format(OutputTimestampLayout))
+	assert.Equal(t, "data-20240101-060000.json", saved)
+
+	matched, err := filepath.Match(TimestampedDataPattern, saved)
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandKey identifie une commande MongoDB en cours (les ID de requête du driver sont propres à une connexion)
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// CommandMonitor retourne un moniteur du driver MongoDB ouvrant un span client par commande (find, insert...)
+// Le span est enfant du contexte passé à l'opération (c.UserContext() dans les handlers) ; le filtre et
+// les documents ne sont pas enregistrés, seuls la base, la collection et la commande le sont
+func CommandMonitor() *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := make(map[commandKey]trace.Span)
+
+	finish := func(finished event.CommandFinishedEvent, err error) {
+		key := commandKey{connectionID: finished.ConnectionID, requestID: finished.RequestID}
+		mu.Lock()
+		span, ok := spans[key]
+		delete(spans, key)
+		mu.Unlock()
+		if ok {
+			End(span, err)
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBNamespace(started.DatabaseName),
+				semconv.DBOperationName(started.CommandName),
+			}
+			name := started.CommandName
+			// La collection est la valeur de la commande elle-même : {"find": "recettes", ...}
+			if collection, ok := started.Command.Lookup(started.CommandName).StringValueOK(); ok {
+				attrs = append(attrs, semconv.DBCollectionName(collection))
+				name += " " + collection
+			}
+			if requestID := RequestIDFromContext(ctx); requestID != "" {
+				attrs = append(attrs, RequestIDKey.String(requestID))
+			}
+
+			_, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			mu.Lock()
+			spans[commandKey{connectionID: started.ConnectionID, requestID: started.RequestID}] = span
+			mu.Unlock()
+		},
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			finish(succeeded.CommandFinishedEvent, nil)
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			finish(failed.CommandFinishedEvent, errors.New(failed.Failure))
+		},
+	}
+}
@@ -0,0 +1,130 @@
+// Package tracing configure OpenTelemetry pour l'API : export OTLP des spans, propagation du
+// contexte W3C (traceparent) et utilitaires partagés par le middleware HTTP, MongoDB et le lancement du scraper
+// Sans OTEL_EXPORTER_OTLP_ENDPOINT, le fournisseur global reste celui d'OpenTelemetry (aucun span enregistré)
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifie les spans produits par l'API
+const TracerName = "github.com/maxime-louis14/api-golang"
+
+// DefaultServiceName est le nom de service des spans si OTEL_SERVICE_NAME n'est pas défini
+const DefaultServiceName = "api-golang"
+
+// Variables d'environnement portant le contexte de trace vers le scraper (convention OpenTelemetry des
+// processus enfants), lues par le scraper au démarrage
+const (
+	TraceParentEnv = "TRACEPARENT"
+	TraceStateEnv  = "TRACESTATE"
+)
+
+// RequestIDKey est l'attribut portant l'ID de requête, même nom que dans les logs
+const RequestIDKey = attribute.Key("request_id")
+
+// Enabled indique si l'export est configuré : OTEL_EXPORTER_OTLP_ENDPOINT (ou sa variante _TRACES_)
+// défini et OTEL_SDK_DISABLED différent de true
+func Enabled() bool {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("OTEL_SDK_DISABLED")), "true") {
+		return false
+	}
+	return strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")) != "" ||
+		strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")) != ""
+}
+
+// Setup installe la propagation W3C et, si Enabled, un fournisseur exportant les spans en OTLP/HTTP
+// L'exportateur lit lui-même les variables OTEL_EXPORTER_OTLP_* (endpoint, en-têtes, TLS, timeout)
+// La fonction retournée vide les spans en attente et doit être appelée à l'arrêt du serveur
+func Setup(ctx context.Context, serviceVersion string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// OTEL_SERVICE_NAME et OTEL_RESOURCE_ATTRIBUTES (WithFromEnv) priment sur les valeurs par défaut
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(DefaultServiceName), semconv.ServiceVersion(serviceVersion)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer retourne le traceur de l'API, issu du fournisseur global (remplaçable dans les tests)
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID associe l'ID de requête au contexte, pour les spans créés plus bas (MongoDB, scraper)
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext retourne l'ID de requête associé par ContextWithRequestID, vide sinon
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// Start ouvre un span enfant du contexte, avec l'ID de requête en attribut s'il est connu
+// requestID vide : celui du contexte est repris
+func Start(ctx context.Context, name, requestID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if requestID == "" {
+		requestID = RequestIDFromContext(ctx)
+	} else {
+		ctx = ContextWithRequestID(ctx, requestID)
+	}
+	if requestID != "" {
+		attrs = append(attrs, RequestIDKey.String(requestID))
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ferme le span, marqué en erreur si err n'est pas nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectEnv ajoute à env le contexte de trace de ctx (TRACEPARENT, TRACESTATE), pour qu'un processus
+// enfant rattache son exécution à la trace de la requête ; env est retourné tel quel sans span actif
+func InjectEnv(ctx context.Context, env []string) []string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if traceParent := carrier.Get("traceparent"); traceParent != "" {
+		env = append(env, TraceParentEnv+"="+traceParent)
+	}
+	if traceState := carrier.Get("tracestate"); traceState != "" {
+		env = append(env, TraceStateEnv+"="+traceState)
+	}
+	return env
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useInMemoryExporter installe un fournisseur global enregistrant les spans en mémoire le temps du test
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	assert.False(t, Enabled())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://collector:4318/v1/traces")
+	assert.True(t, Enabled())
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.False(t, Enabled())
+}
+
+// Le contexte du span est transmis au processus enfant dans TRACEPARENT ; sans span, env est inchangé
+func TestInjectEnv(t *testing.T) {
+	useInMemoryExporter(t)
+
+	env := []string{"PATH=/usr/bin"}
+	assert.Equal(t, env, InjectEnv(context.Background(), env))
+
+	ctx, span := Start(context.Background(), "scraper.run", "req-1")
+	defer span.End()
+	env = InjectEnv(ctx, env)
+	require.Len(t, env, 2)
+	sc := span.SpanContext()
+	assert.Equal(t, "TRACEPARENT=00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", env[1])
+}
+
+// Start reprend l'ID de requête du contexte, et le place dans le contexte quand il est fourni
+func TestStartRequestID(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	ctx, parent := Start(context.Background(), "parent", "req-7")
+	_, child := Start(ctx, "child", "")
+	End(child, nil)
+	End(parent, assert.AnError)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "req-7", spanAttributes(spans[0])[RequestIDKey].AsString())
+	assert.Equal(t, "req-7", spanAttributes(spans[1])[RequestIDKey].AsString())
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "req-7", RequestIDFromContext(ctx))
+}
+
+// Chaque commande MongoDB produit un span client, enfant du contexte de l'opération
+func TestCommandMonitor(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+	monitor := CommandMonitor()
+
+	ctx, parent := Start(context.Background(), "GET /recettes", "req-9")
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "recettes"}, {Key: "filter", Value: bson.D{{Key: "name", Value: "secret"}}}})
+	require.NoError(t, err)
+
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: command, DatabaseName: "recipes", CommandName: "find", RequestID: 1, ConnectionID: "c1"})
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: command, DatabaseName: "recipes", CommandName: "find", RequestID: 1, ConnectionID: "c2"})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, ConnectionID: "c1"}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, ConnectionID: "c2"}, Failure: "timeout"})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	succeeded, failed := spans[0], spans[1]
+
+	assert.Equal(t, "find recettes", succeeded.Name)
+	assert.Equal(t, trace.SpanKindClient, succeeded.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), succeeded.Parent.SpanID())
+	attrs := spanAttributes(succeeded)
+	assert.Equal(t, "mongodb", attrs["db.system"].AsString())
+	assert.Equal(t, "recipes", attrs["db.namespace"].AsString())
+	assert.Equal(t, "recettes", attrs["db.collection.name"].AsString())
+	assert.Equal(t, "find", attrs["db.operation.name"].AsString())
+	assert.Equal(t, "req-9", attrs[RequestIDKey].AsString())
+	assert.Equal(t, codes.Unset, succeeded.Status.Code)
+
+	// Le filtre n'est jamais enregistré
+	for _, value := range attrs {
+		assert.NotContains(t, value.Emit(), "secret")
+	}
+
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	assert.Equal(t, "timeout", failed.Status.Description)
+}